@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// loadABI reads a JSON ABI or a build artifact containing one, exiting on error.
+// An empty path returns nil.
+func loadABI(path string) *abi.ABI {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Error reading ABI: %v\n", err)
+		os.Exit(1)
+	}
+	contractABI, err := abi.ParseJSON(data)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return contractABI
+}
+
+// resolveMethod finds the method to call, exiting on error. With an ABI the method
+// is a name or full signature; without one it is a human-readable signature
+// such as "balanceOf(address) returns (uint256)".
+func resolveMethod(contractABI *abi.ABI, method string, argCount int) *abi.Method {
+	var m *abi.Method
+	var err error
+	if contractABI != nil {
+		m, err = contractABI.MethodByName(method, argCount)
+	} else {
+		m, err = abi.ParseSignature(method)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return m
+}
+
+// packCall encodes the calldata for a method from command-line arguments, exiting on error
+func packCall(method *abi.Method, args []string) []byte {
+	values, err := method.Inputs.ParseArgs(args)
+	if err != nil {
+		fmt.Printf("Error: %s: %v\n", method.Sig(), err)
+		os.Exit(1)
+	}
+	data, err := method.Pack(values...)
+	if err != nil {
+		fmt.Printf("Error encoding call: %v\n", err)
+		os.Exit(1)
+	}
+	return data
+}
+
+// printRevertReason prints the decoded revert reason when err is a reverted call
+func printRevertReason(err error, contractABI *abi.ABI) {
+	if data, ok := ethereum.RevertData(err); ok {
+		fmt.Printf("Revert reason: %s\n", abi.DecodeRevert(data, contractABI))
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/metana-bootcamp/ethwallet/internal/address"
+)
+
+// parseAddressArg parses an address argument, exiting on error. Mixed-case
+// addresses must match their EIP-55 checksum, or the EIP-1191 checksum on
+// networks that use it; addresses without a checksum are accepted with a warning.
+func parseAddressArg(name, s string) common.Address {
+	addr, err := address.Parse(s, selectedNetwork().ChecksumChainID())
+	if err != nil {
+		fmt.Printf("Error: Invalid %s address: %v\n", name, err)
+		os.Exit(1)
+	}
+	if !address.HasChecksum(s) {
+		fmt.Printf("Warning: %s address %s has no checksum, so typos cannot be detected\n", name, s)
+	}
+	return addr
+}
+
+// isPrivateKeyHex reports whether s has the form of a hex private key, with or
+// without the 0x prefix
+func isPrivateKeyHex(s string) bool {
+	digits := strings.TrimPrefix(s, "0x")
+	return len(digits) == 64 && strings.Trim(strings.ToLower(digits), "0123456789abcdef") == ""
+}
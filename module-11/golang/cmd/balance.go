@@ -3,54 +3,88 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 
+	"github.com/metana-bootcamp/ethwallet/internal/address"
 	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+	"github.com/metana-bootcamp/ethwallet/internal/units"
 )
 
 // NewBalanceCmd creates a new balance command
 func NewBalanceCmd() *cobra.Command {
 	var useEnvVar bool
 	var useHDWallet bool
+	var keystorePath string
+	var account string
+	var unit string
 
 	cmd := &cobra.Command{
-		Use:   "balance [address]",
+		Use:   "balance [address...]",
 		Short: "Check Ethereum balance",
-		Long:  `Check the balance of an Ethereum address or a private key.`,
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Check the balance of an Ethereum address, ENS name or private key.
+Several addresses can be given at once; they are queried in a single batch request.
+Balances are shown exactly, in ether unless --unit selects wei or gwei.`,
+		Args: cobra.ArbitraryArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			var address string
+			var accountAddress string
+			var ensName string
 			var hasPrivateKey bool
 			var isHDWallet bool
 			var hdKeyPair *ethereum.HDKeyPair
 
+			if _, err := units.UnitDecimals(unit); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+
 			// Load environment variables
 			envLoaded := ethereum.LoadEnvVariables()
 
+			// Several addresses are looked up together in one batch
+			if len(args) > 1 {
+				displayBatchBalances(args, unit)
+				return
+			}
+
 			// Get the address
 			if len(args) > 0 {
 				// Address provided as argument
 				addressArg := args[0]
 
-				// Check if it's a private key or address
-				if strings.HasPrefix(addressArg, "0x") && len(addressArg) == 42 {
+				// Check if it's a private key, ENS name or address
+				if ethereum.IsENSName(addressArg) {
+					// Resolved once connected to the network
+					ensName = addressArg
+				} else if !isPrivateKeyHex(addressArg) {
 					// It's an address
-					address = addressArg
+					accountAddress = parseAddressArg("account", addressArg).Hex()
 					hasPrivateKey = false
 				} else {
-					// Assume it's a private key
+					// It's a private key
 					keyPair, err := ethereum.ImportPrivateKey(addressArg)
 					if err != nil {
 						fmt.Printf("Error importing private key: %v\n", err)
 						os.Exit(1)
 					}
-					address = keyPair.Address.Hex()
+					accountAddress = keyPair.Address.Hex()
 					hasPrivateKey = true
-					fmt.Printf("Using address derived from private key: %s\n", address)
+					fmt.Printf("Using address derived from private key: %s\n", accountAddress)
+				}
+			} else if keystorePath != "" {
+				// Decrypt key from keystore, prompting for the passphrase
+				keyPair, err := loadKeystoreKeyPair(keystorePath, account)
+				if err != nil {
+					fmt.Printf("Error loading keystore: %v\n", err)
+					os.Exit(1)
 				}
+				accountAddress = keyPair.Address.Hex()
+				hasPrivateKey = true
+				fmt.Printf("Using address from keystore: %s\n", accountAddress)
 			} else if useEnvVar {
 				if useHDWallet {
 					// Use HD wallet from environment
@@ -67,17 +101,12 @@ func NewBalanceCmd() *cobra.Command {
 					}
 
 					// Import HD wallet
-					var err error
-					hdKeyPair, err = ethereum.ImportHDWallet(mnemonic, hdPath)
-					if err != nil {
-						fmt.Printf("Error importing HD wallet: %v\n", err)
-						os.Exit(1)
-					}
+					hdKeyPair = importHDWallet(mnemonic, hdPath, envUsesPassphrase(), false)
 
-					address = hdKeyPair.KeyPair.Address.Hex()
+					accountAddress = hdKeyPair.KeyPair.Address.Hex()
 					isHDWallet = true
 					hasPrivateKey = true
-					fmt.Printf("Using address from HD wallet: %s\n", address)
+					fmt.Printf("Using address from HD wallet: %s\n", accountAddress)
 				} else {
 					// Use from environment variable
 					privateKeyHex := os.Getenv("TEST_PRIVATE_KEY")
@@ -95,52 +124,55 @@ func NewBalanceCmd() *cobra.Command {
 						fmt.Printf("Error importing private key: %v\n", err)
 						os.Exit(1)
 					}
-					address = keyPair.Address.Hex()
+					accountAddress = keyPair.Address.Hex()
 					hasPrivateKey = true
-					fmt.Printf("Using address from TEST_PRIVATE_KEY: %s\n", address)
+					fmt.Printf("Using address from TEST_PRIVATE_KEY: %s\n", accountAddress)
 				}
 			} else {
 				// No address provided and no env var flag
-				fmt.Println("Error: Please provide an address, private key, or use the --env flag")
+				fmt.Println("Error: Please provide an address, private key, or use the --env or --keystore flag")
 				os.Exit(1)
 			}
 
-			// Get RPC URL
-			rpcURL := ethereum.GetRPCURL()
+			// Select the network and check its node serves the expected chain
 			ctx := context.Background()
+			network, client := connectNetwork(ctx)
+			if ensName != "" {
+				accountAddress = resolveAddressArg(ctx, network, client, "account", ensName).Hex()
+			}
 
 			// Display basic info
 			fmt.Println("\n=== BALANCE CHECK ===")
-			fmt.Printf("Checking balance for: %s\n", address)
-			fmt.Printf("Network RPC: %s\n", rpcURL)
+			fmt.Printf("Checking balance for: %s\n", accountAddress)
+			fmt.Printf("Network: %s (chain ID %d)\n", network.Name, network.ChainID)
+			fmt.Printf("Network RPC: %s\n", client.URL())
 
-			// Check balance
+			// Check balance and nonce in one batch
 			fmt.Println("\nQuerying network...")
-			balance, err := ethereum.GetBalance(ctx, ethereum.HexToAddress(address), rpcURL)
+			accounts, err := client.GetAccounts(ctx, []common.Address{ethereum.HexToAddress(accountAddress)})
+			if err == nil {
+				err = accounts[0].Err
+			}
 			if err != nil {
 				fmt.Printf("Error checking balance: %v\n", err)
 				os.Exit(1)
 			}
+			balance := accounts[0].Balance
 
 			// Display balance
 			fmt.Println("\n=== BALANCE RESULT ===")
-			fmt.Printf("Address: %s\n", address)
+			fmt.Printf("Address: %s%s\n", accountAddress, ensNameSuffix(ctx, network, client, ethereum.HexToAddress(accountAddress)))
 			fmt.Printf("Balance: %s wei\n", balance.String())
-			fmt.Printf("Balance: %s ETH\n", ethereum.WeiToEth(balance))
+			fmt.Printf("Balance: %s\n", formatBalance(balance, unit, network))
 
 			// If we have a private key, show additional info
 			if hasPrivateKey {
-				// Get nonce
-				nonce, err := ethereum.GetNonce(ctx, ethereum.HexToAddress(address), rpcURL)
-				if err != nil {
-					fmt.Printf("Error getting nonce: %v\n", err)
-					return
-				}
-				fmt.Printf("Nonce: %d\n", nonce)
+				fmt.Printf("Nonce: %d\n", accounts[0].Nonce)
 
 				// Get additional info
-				blockExplorer := ethereum.GetBlockExplorerURL()
-				fmt.Printf("View on Etherscan: %s/address/%s\n", blockExplorer, address)
+				if network.Explorer != "" {
+					fmt.Printf("View on explorer: %s/address/%s\n", network.Explorer, accountAddress)
+				}
 
 				// Show HD wallet info if available
 				if isHDWallet && hdKeyPair != nil && hdKeyPair.HDInfo != nil {
@@ -148,6 +180,9 @@ func NewBalanceCmd() *cobra.Command {
 					fmt.Printf("Mnemonic: %s\n", hdKeyPair.HDInfo.Mnemonic)
 					fmt.Printf("HD Path: %s\n", hdKeyPair.HDInfo.HDPath)
 					fmt.Printf("Account Index: %d\n", hdKeyPair.HDInfo.AccountIndex)
+					if hdKeyPair.HDInfo.HasPassphrase {
+						fmt.Println("Passphrase: yes (BIP-39)")
+					}
 
 					// Show a few derived addresses
 					fmt.Println("\n=== DERIVED ADDRESSES ===")
@@ -172,6 +207,63 @@ func NewBalanceCmd() *cobra.Command {
 	// Add flags
 	cmd.Flags().BoolVarP(&useEnvVar, "env", "e", false, "Use address from TEST_PRIVATE_KEY environment variable")
 	cmd.Flags().BoolVarP(&useHDWallet, "hd", "", false, "Use HD wallet from HD_MNEMONIC environment variable")
+	cmd.Flags().StringVarP(&keystorePath, "keystore", "k", "", "Use encrypted keystore file or directory (prompts for passphrase)")
+	cmd.Flags().StringVarP(&account, "account", "a", "", "Account address to select from the keystore directory")
+	cmd.Flags().StringVarP(&unit, "unit", "u", "ether", "Unit to display balances in: wei, gwei or ether")
 
 	return cmd
 }
+
+// displayBatchBalances queries balances and nonces for several addresses in one batch
+func displayBatchBalances(args []string, unit string) {
+	for _, arg := range args {
+		if !address.IsHex(arg) && !ethereum.IsENSName(arg) {
+			fmt.Printf("Error: Invalid address %s. Only addresses and ENS names can be combined in one query\n", arg)
+			os.Exit(1)
+		}
+	}
+
+	ctx := context.Background()
+	network, client := connectNetwork(ctx)
+
+	addresses := make([]common.Address, len(args))
+	for i, arg := range args {
+		addresses[i] = resolveAddressArg(ctx, network, client, "account", arg)
+	}
+
+	fmt.Println("\n=== BALANCE CHECK ===")
+	fmt.Printf("Checking %d addresses\n", len(addresses))
+	fmt.Printf("Network: %s (chain ID %d)\n", network.Name, network.ChainID)
+	fmt.Printf("Network RPC: %s\n", client.URL())
+
+	accounts, err := client.GetAccounts(ctx, addresses)
+	if err != nil {
+		fmt.Printf("Error checking balances: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== BALANCE RESULTS ===")
+	failed := false
+	for _, account := range accounts {
+		if account.Err != nil {
+			fmt.Printf("%s  error: %v\n", account.Address.Hex(), account.Err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%s%s  %s  (nonce %d)\n", account.Address.Hex(), ensNameSuffix(ctx, network, client, account.Address), formatBalance(account.Balance, unit, network), account.Nonce)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// formatBalance formats a balance in wei exactly in the given unit, labelling
+// ether amounts with the network's currency
+func formatBalance(balance *big.Int, unit string, network *ethereum.Network) string {
+	decimals, _ := units.UnitDecimals(unit)
+	if decimals == units.EtherDecimals {
+		return fmt.Sprintf("%s %s", ethereum.WeiToEth(balance), network.Currency)
+	}
+	return fmt.Sprintf("%s %s", units.Format(balance, decimals), strings.ToLower(unit))
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
+)
+
+// NewCallCmd creates a command for read-only contract calls
+func NewCallCmd() *cobra.Command {
+	var abiPath string
+	var from string
+	var block string
+
+	cmd := &cobra.Command{
+		Use:   "call <contract> <method> [args...]",
+		Short: "Call a contract function without sending a transaction",
+		Long: `Call a contract function with eth_call and decode the result.
+The method is a human-readable signature such as
+"balanceOf(address) returns (uint256)", or a function name when --abi is given.
+Arrays are written as [a,b] and tuples as (a,b). Revert reasons are decoded.`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			contract := args[0]
+			parseAddressArg("contract", contract)
+			if from != "" {
+				parseAddressArg("--from", from)
+			}
+
+			contractABI := loadABI(abiPath)
+			method := resolveMethod(contractABI, args[1], len(args)-2)
+			data := packCall(method, args[2:])
+
+			ctx := context.Background()
+			network, client := connectNetwork(ctx)
+
+			fmt.Println("\n=== CONTRACT CALL ===")
+			fmt.Printf("Network:  %s (chain ID %d)\n", network.Name, network.ChainID)
+			fmt.Printf("Contract: %s\n", contract)
+			fmt.Printf("Method:   %s\n", method.Sig())
+			fmt.Printf("Calldata: 0x%x\n", data)
+
+			result, err := client.CallContract(ctx, from, contract, data, block)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				printRevertReason(err, contractABI)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== RESULT ===")
+			if len(method.Outputs) == 0 {
+				// Without declared outputs only the raw data can be shown
+				fmt.Printf("Return data: 0x%x\n", result)
+				return
+			}
+
+			values, err := method.Unpack(result)
+			if err != nil {
+				fmt.Printf("Error decoding result: %v\n", err)
+				fmt.Printf("Return data: 0x%x\n", result)
+				os.Exit(1)
+			}
+			for i, output := range method.Outputs {
+				name := output.Name
+				if name == "" {
+					name = fmt.Sprintf("[%d]", i)
+				}
+				fmt.Printf("%s (%s): %s\n", name, output.Type, abi.FormatValue(output.Type, values[i]))
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&abiPath, "abi", "", "JSON ABI or build artifact used to resolve the method and decode custom errors")
+	cmd.Flags().StringVar(&from, "from", "", "Address the call is made from")
+	cmd.Flags().StringVar(&block, "block", "latest", "Block number (0x hex) or tag to call at")
+
+	return cmd
+}
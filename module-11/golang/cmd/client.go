@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// newRPCClient creates an RPC client for the network's primary RPC URL that fails
+// over to its fallback URLs and refuses to prepare transactions for another chain
+func newRPCClient(network *ethereum.Network) *ethereum.Client {
+	return ethereum.NewClient(network.RPCURL(),
+		ethereum.WithFallbackURLs(network.FallbackURLs()...),
+		ethereum.WithChainID(network.ChainID),
+	)
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// newTxDecodeCmd creates the command that inspects a signed raw transaction
+func newTxDecodeCmd() *cobra.Command {
+	var abiPath string
+	var methodSig string
+
+	cmd := &cobra.Command{
+		Use:   "decode <rawTxHex|signedTxFile>",
+		Short: "Decode a signed raw transaction",
+		Long: `Decode a signed raw transaction, given as 0x-prefixed hex or as a file written by
+"tx sign", without sending it: its type, every field, the sender recovered from
+the signature and the transaction hash.
+Legacy, EIP-2930 and EIP-1559 transactions are decoded field by field; other
+typed transactions are shown as raw RLP fields.
+With --abi, or --method and a signature such as "transfer(address,uint256)",
+the calldata is decoded into the method's arguments.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			rawTx := readRawTx(args[0])
+
+			tx, err := ethereum.DecodeTransaction(rawTx)
+			if err != nil {
+				fmt.Printf("Error decoding transaction: %v\n", err)
+				os.Exit(1)
+			}
+
+			currency := "ETH"
+			fmt.Println("\n=== DECODED TRANSACTION ===")
+			fmt.Printf("Type:      %s\n", txTypeName(tx.Type))
+			fmt.Printf("Hash:      %s\n", tx.Hash.Hex())
+			fmt.Printf("From:      %s (recovered from the signature)\n", tx.From.Hex())
+			if tx.ChainID == nil {
+				fmt.Printf("Chain ID:  none (signed without EIP-155 replay protection)\n")
+			} else if network := networkForChain(tx.ChainID.Uint64()); network != nil && tx.ChainID.IsUint64() {
+				fmt.Printf("Chain ID:  %s (%s)\n", tx.ChainID, network.Name)
+				currency = network.Currency
+			} else {
+				fmt.Printf("Chain ID:  %s\n", tx.ChainID)
+			}
+
+			if !tx.Known() {
+				fmt.Println("\nUnknown transaction type; raw RLP fields:")
+				for i, field := range tx.Fields {
+					fmt.Printf("[%d] 0x%x\n", i, []byte(field))
+				}
+				displaySignature(tx)
+				return
+			}
+
+			fmt.Printf("Nonce:     %d\n", tx.Nonce)
+			if tx.To != nil {
+				fmt.Printf("To:        %s\n", tx.To.Hex())
+			} else {
+				fmt.Printf("To:        (contract creation at %s)\n", ethereum.CreateAddress(tx.From, tx.Nonce).Hex())
+			}
+			fmt.Printf("Value:     %s wei (%s %s)\n", tx.Value, ethereum.WeiToEth(tx.Value), currency)
+			fmt.Printf("Gas limit: %d\n", tx.GasLimit)
+			if tx.Type == ethereum.TxTypeDynamicFee {
+				fmt.Printf("Priority fee: %s gwei\n", formatGwei(tx.MaxPriorityFeePerGas))
+				fmt.Printf("Max fee:      %s gwei\n", formatGwei(tx.MaxFeePerGas))
+			} else {
+				fmt.Printf("Gas price:    %s gwei\n", formatGwei(tx.GasPrice))
+			}
+
+			if tx.Type != ethereum.TxTypeLegacy {
+				fmt.Printf("Access list: %d address(es), %d storage key(s)\n", len(tx.AccessList), tx.AccessList.StorageKeyCount())
+				for _, tuple := range tx.AccessList {
+					fmt.Printf("  %s\n", tuple.Address.Hex())
+					for _, key := range tuple.StorageKeys {
+						fmt.Printf("    %s\n", key.Hex())
+					}
+				}
+			}
+
+			if len(tx.Data) > 0 {
+				fmt.Printf("Data:      %d bytes\n", len(tx.Data))
+				fmt.Printf("0x%x\n", tx.Data)
+			}
+			if tx.To != nil && len(tx.Data) >= 4 {
+				displayCalldata(tx.Data, abiPath, methodSig)
+			}
+
+			displaySignature(tx)
+		},
+	}
+
+	cmd.Flags().StringVar(&abiPath, "abi", "", "JSON ABI or build artifact used to decode the calldata")
+	cmd.Flags().StringVarP(&methodSig, "method", "m", "", "Method signature used to decode the calldata, e.g. \"transfer(address,uint256)\"")
+
+	return cmd
+}
+
+// displayCalldata shows the selector of contract calldata and, when an ABI or
+// method signature is given, the decoded arguments
+func displayCalldata(data []byte, abiPath, methodSig string) {
+	fmt.Println("\n=== CALLDATA ===")
+	fmt.Printf("Selector: 0x%x\n", data[:4])
+	if abiPath == "" && methodSig == "" {
+		return
+	}
+
+	var method *abi.Method
+	if contractABI := loadABI(abiPath); contractABI != nil && methodSig == "" {
+		m, ok := contractABI.MethodByID(data[:4])
+		if !ok {
+			fmt.Printf("No method in the ABI has selector 0x%x\n", data[:4])
+			return
+		}
+		method = m
+	} else {
+		method = resolveMethod(contractABI, methodSig, -1)
+	}
+
+	values, err := method.UnpackInput(data)
+	if err != nil {
+		fmt.Printf("Error decoding calldata: %v\n", err)
+		return
+	}
+	fmt.Printf("Method:   %s\n", method.Sig())
+	for i, input := range method.Inputs {
+		name := input.Name
+		if name == "" {
+			name = fmt.Sprintf("[%d]", i)
+		}
+		fmt.Printf("%s (%s): %s\n", name, input.Type, abi.FormatValue(input.Type, values[i]))
+	}
+}
+
+// displaySignature shows the signature values of a decoded transaction
+func displaySignature(tx *ethereum.DecodedTx) {
+	fmt.Println("\n=== SIGNATURE ===")
+	if tx.Type == ethereum.TxTypeLegacy {
+		fmt.Printf("V: %s\n", tx.V)
+	} else {
+		fmt.Printf("Y parity: %s\n", tx.V)
+	}
+	fmt.Printf("R: 0x%064x\n", tx.R)
+	fmt.Printf("S: 0x%064x\n", tx.S)
+}
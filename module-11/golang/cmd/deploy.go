@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// NewDeployCmd creates a new command for deploying contracts
+func NewDeployCmd() *cobra.Command {
+	var signer signerFlags
+	var useLegacy bool
+	var fees feeFlags
+	var valueArg string
+	var constructorArgs string
+	var confirmations uint64
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "deploy <privateKey> <bytecode|artifact.json>",
+		Short: "Deploy a contract",
+		Long: `Deploy a contract from 0x-prefixed bytecode or a compiled artifact JSON
+(Hardhat, Foundry or solc output). Constructor arguments are appended with
+--constructor-args: when the artifact's ABI has a constructor they are written
+as a comma-separated list, e.g. '"My Token",MTK,1000000', with arrays as [a,b]
+and tuples as (a,b); otherwise they are ABI-encoded 0x-prefixed hex.
+With --env or --keystore the private key argument is omitted.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			keyPair, args := signer.keyPairFromArgs(args, 1, "bytecode")
+
+			// Load init code from an artifact or hex
+			var initCode []byte
+			var contractName string
+			var contractABI *abi.ABI
+			if strings.HasSuffix(strings.ToLower(args[0]), ".json") {
+				artifact, err := ethereum.LoadContractArtifact(args[0])
+				if err != nil {
+					fmt.Printf("Error loading artifact: %v\n", err)
+					os.Exit(1)
+				}
+				initCode = artifact.Bytecode
+				contractName = artifact.ContractName
+				if artifact.ABI != nil {
+					if contractABI, err = abi.ParseJSON(artifact.ABI); err != nil {
+						fmt.Printf("Error: %v\n", err)
+						os.Exit(1)
+					}
+				}
+			} else {
+				var err error
+				initCode, err = ethereum.ParseBytecode(args[0])
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			// Constructor arguments follow the init code
+			if constructorArgs != "" {
+				initCode = append(initCode, encodeConstructorArgs(contractABI, constructorArgs)...)
+			}
+
+			value := parseAmountArg("value", valueArg, "wei")
+
+			// Select the network and check its node serves the expected chain
+			ctx := context.Background()
+			network, client := connectNetwork(ctx)
+			if !useLegacy && !network.EIP1559 {
+				fmt.Printf("Network %s does not support EIP-1559, sending a legacy transaction\n", network.Name)
+				useLegacy = true
+			}
+
+			// Choose the fees before anything is signed
+			fee := fees.resolve(ctx, client, useLegacy)
+
+			// Estimate gas with the init code
+			sendOpts := append(fee.opts, ethereum.WithData(initCode), nonceOption(nonceManager()))
+			preflight, err := client.Preflight(ctx, keyPair.Address, "", value, sendOpts...)
+			if err != nil {
+				fmt.Printf("Error preparing deployment: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== DEPLOYMENT DETAILS ===")
+			fmt.Printf("Network:   %s (chain ID %d)\n", network.Name, network.ChainID)
+			if contractName != "" {
+				fmt.Printf("Contract:  %s\n", contractName)
+			}
+			fmt.Printf("From:      %s\n", keyPair.Address.Hex())
+			fmt.Printf("Init code: %d bytes\n", len(initCode))
+			fmt.Printf("Value:     %s wei (%s %s)\n", value.String(), ethereum.WeiToEth(value), network.Currency)
+			fmt.Printf("Gas limit: %d\n", preflight.GasLimit)
+			fee.print()
+
+			// Send the contract creation transaction
+			fmt.Println("\n=== DEPLOYING CONTRACT ===")
+			var txHash string
+			var nonce uint64
+			sendOpts = append(sendOpts, ethereum.WithSentNonce(&nonce))
+			if useLegacy {
+				txHash, err = client.SendTransaction(ctx, keyPair, "", value, sendOpts...)
+			} else {
+				txHash, err = client.SendEIP1559Transaction(ctx, keyPair, "", value, fee.priorityFee, sendOpts...)
+			}
+			if err != nil {
+				fmt.Printf("Error deploying contract: %v\n", err)
+				os.Exit(1)
+			}
+
+			// The contract address follows from the nonce the transaction was signed with
+			predicted := ethereum.CreateAddress(keyPair.Address, nonce)
+			fmt.Printf("Transaction hash: %s\n", txHash)
+			fmt.Printf("Nonce:            %d\n", nonce)
+			fmt.Printf("Predicted address: %s\n", predicted.Hex())
+			if network.Explorer != "" {
+				fmt.Printf("View on explorer: %s\n", ethereum.FormatTransactionURL(txHash, network.Explorer))
+			}
+
+			// Wait for the receipt
+			fmt.Printf("\nWaiting for %d confirmation(s) (timeout %s)...\n", confirmations, timeout)
+			waitOpts := ethereum.DefaultWaitOptions()
+			waitOpts.Confirmations = confirmations
+			waitOpts.Timeout = timeout
+			waitOpts.From, waitOpts.Nonce = &keyPair.Address, nonce
+
+			result, err := client.WaitForTransaction(ctx, txHash, waitOpts)
+			displayTxResult(network, result, err)
+
+			if result.Status != ethereum.TxStatusSuccess {
+				os.Exit(1)
+			}
+
+			if result.Receipt.ContractAddress == nil {
+				fmt.Println("Error: receipt has no contract address")
+				os.Exit(1)
+			}
+			deployed := *result.Receipt.ContractAddress
+			fmt.Printf("\n✅ CONTRACT DEPLOYED: %s\n", deployed.Hex())
+			if deployed != predicted {
+				fmt.Printf("Note: differs from the predicted address %s\n", predicted.Hex())
+			}
+			if network.Explorer != "" {
+				fmt.Printf("View on explorer: %s/address/%s\n", network.Explorer, deployed.Hex())
+			}
+		},
+	}
+
+	// Add flags
+	signer.register(cmd)
+	cmd.Flags().BoolVarP(&useLegacy, "legacy", "l", false, "Use legacy transaction instead of EIP-1559")
+	fees.register(cmd)
+	cmd.Flags().StringVar(&valueArg, "value", "0", "Value sent to the constructor, in wei unless a unit is given (e.g. 0.1ether)")
+	cmd.Flags().StringVar(&constructorArgs, "constructor-args", "", "Constructor arguments: a comma-separated list typed by the artifact's ABI, or ABI-encoded 0x-prefixed hex")
+	cmd.Flags().Uint64VarP(&confirmations, "confirmations", "c", 1, "Number of block confirmations to wait for")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "t", 2*time.Minute, "Maximum time to wait for the transaction to be mined")
+
+	return cmd
+}
+
+// encodeConstructorArgs encodes --constructor-args, exiting on error. With an ABI
+// constructor the value is parsed as a comma-separated list of its inputs;
+// without one, or when the value does not parse that way, it must be
+// ABI-encoded hex.
+func encodeConstructorArgs(contractABI *abi.ABI, s string) []byte {
+	var typedErr error
+	if contractABI != nil && contractABI.Constructor != nil {
+		constructor := contractABI.Constructor
+		tuple := abi.Type{Kind: abi.TupleKind, Components: constructor.Inputs}
+		values, err := abi.ParseArg(tuple, "("+s+")")
+		if err == nil {
+			encoded, err := constructor.Inputs.Pack(values.([]interface{})...)
+			if err != nil {
+				fmt.Printf("Error encoding constructor arguments: %v\n", err)
+				os.Exit(1)
+			}
+			return encoded
+		}
+		typedErr = fmt.Errorf("constructor%s: %w", constructor.Sig(), err)
+	}
+
+	encoded, err := ethereum.HexDecode(s)
+	if err == nil && len(encoded)%32 != 0 {
+		err = fmt.Errorf("ABI-encoded arguments are a multiple of 32 bytes, got %d", len(encoded))
+	}
+	if err != nil {
+		if typedErr != nil {
+			err = typedErr
+		}
+		fmt.Printf("Error: Invalid --constructor-args: %v\n", err)
+		os.Exit(1)
+	}
+	return encoded
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// newENS returns the ENS resolver of the network, or nil when the network has
+// no ENS registry
+func newENS(network *ethereum.Network, client *ethereum.Client) *ethereum.ENS {
+	if network.ENSRegistry == "" {
+		return nil
+	}
+	return ethereum.NewENS(client, common.HexToAddress(network.ENSRegistry))
+}
+
+// resolveAddressArg resolves an ENS name such as vitalik.eth, or parses a hex
+// address like parseAddressArg, exiting on error
+func resolveAddressArg(ctx context.Context, network *ethereum.Network, client *ethereum.Client, name, s string) common.Address {
+	if !ethereum.IsENSName(s) {
+		return parseAddressArg(name, s)
+	}
+
+	ens := newENS(network, client)
+	if ens == nil {
+		fmt.Printf("Error: Network %s has no ENS registry; set ensRegistry in its profile to resolve %s\n", network.Name, s)
+		os.Exit(1)
+	}
+	addr, err := ens.Resolve(ctx, s)
+	if err != nil {
+		fmt.Printf("Error resolving %s: %v\n", s, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Resolved %s to %s\n", s, addr.Hex())
+	return addr
+}
+
+// ensNameSuffix returns " (name)" for an address whose primary ENS name resolves
+// back to it, or an empty string. Lookup failures are not reported.
+func ensNameSuffix(ctx context.Context, network *ethereum.Network, client *ethereum.Client, addr common.Address) string {
+	ens := newENS(network, client)
+	if ens == nil {
+		return ""
+	}
+	name, err := ens.LookupAddress(ctx, addr)
+	if err != nil || name == "" {
+		return ""
+	}
+	return " (" + name + ")"
+}
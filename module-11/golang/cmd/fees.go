@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+	"github.com/metana-bootcamp/ethwallet/internal/units"
+)
+
+// feeFlags are the fee flags shared by commands that send a transaction
+type feeFlags struct {
+	speed          string
+	maxFee         string
+	maxPriorityFee string
+	feeCap         string
+}
+
+// register adds the fee flags to a command. --priority-fee is kept as a
+// deprecated alias of --max-priority-fee.
+func (f *feeFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.speed, "speed", string(ethereum.FeeStandard), "Fee preset from recent blocks: slow, standard or fast")
+	cmd.Flags().StringVar(&f.maxFee, "max-fee", "", "Max fee per gas (the gas price with --legacy), in gwei unless a unit is given; overrides --speed")
+	cmd.Flags().StringVarP(&f.maxPriorityFee, "max-priority-fee", "f", "", "Priority fee per gas for EIP-1559 transactions, in gwei unless a unit is given; overrides --speed")
+	cmd.Flags().StringVar(&f.maxPriorityFee, "priority-fee", "", "Priority fee per gas for EIP-1559 transactions")
+	cmd.Flags().MarkDeprecated("priority-fee", "use --max-priority-fee instead")
+	cmd.Flags().StringVar(&f.feeCap, "fee-cap", "", "Refuse to sign when the max fee per gas (or gas price) is above this, in gwei unless a unit is given")
+}
+
+// options parses the fee flags into a priority fee (nil when not given) and send
+// options, leaving the missing fees to the library's fee oracle. With useLegacy
+// --max-fee is the gas price and there is no priority fee. It exits on invalid flags.
+func (f *feeFlags) options(useLegacy bool) (*big.Int, []ethereum.SendOption) {
+	speed, err := ethereum.ParseFeeSpeed(f.speed)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts := []ethereum.SendOption{ethereum.WithFeeSpeed(speed)}
+	if f.feeCap != "" {
+		opts = append(opts, ethereum.WithFeeCap(parseAmountArg("fee cap", f.feeCap, "gwei")))
+	}
+
+	if useLegacy {
+		if f.maxPriorityFee != "" {
+			fmt.Println("Note: --max-priority-fee is ignored for legacy transactions")
+		}
+		if f.maxFee != "" {
+			opts = append(opts, ethereum.WithGasPrice(parseAmountArg("max fee", f.maxFee, "gwei")))
+		}
+		return nil, opts
+	}
+	if f.maxFee != "" {
+		opts = append(opts, ethereum.WithMaxFeePerGas(parseAmountArg("max fee", f.maxFee, "gwei")))
+	}
+
+	var priorityFee *big.Int
+	if f.maxPriorityFee != "" {
+		priorityFee = parseAmountArg("max priority fee", f.maxPriorityFee, "gwei")
+	}
+	return priorityFee, opts
+}
+
+// feeChoice holds the fees chosen for a transaction and the send options carrying them
+type feeChoice struct {
+	useLegacy   bool
+	baseFee     *big.Int // pending base fee, nil when no estimate was needed
+	priorityFee *big.Int // nil for legacy transactions
+	maxFee      *big.Int // max fee per gas, or the gas price; nil when the node's gas price is used
+	feeCap      *big.Int
+	source      string // where the fees came from, e.g. "standard preset"
+	opts        []ethereum.SendOption
+}
+
+// resolve parses the fee flags and, for EIP-1559 transactions, fills in the fees
+// that were not given from the --speed preset of the fee oracle. It exits on
+// invalid flags and refuses to continue when the max fee is above --fee-cap.
+func (f *feeFlags) resolve(ctx context.Context, client *ethereum.Client, useLegacy bool) *feeChoice {
+	speed, err := ethereum.ParseFeeSpeed(f.speed)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	choice := &feeChoice{useLegacy: useLegacy, source: "--max-fee"}
+	if f.maxFee != "" {
+		choice.maxFee = parseAmountArg("max fee", f.maxFee, "gwei")
+	}
+	if f.feeCap != "" {
+		choice.feeCap = parseAmountArg("fee cap", f.feeCap, "gwei")
+		choice.opts = append(choice.opts, ethereum.WithFeeCap(choice.feeCap))
+	}
+
+	if useLegacy {
+		if f.maxPriorityFee != "" {
+			fmt.Println("Note: --max-priority-fee is ignored for legacy transactions")
+		}
+		if choice.maxFee != nil {
+			choice.opts = append(choice.opts, ethereum.WithGasPrice(choice.maxFee))
+		}
+	} else {
+		if f.maxPriorityFee != "" {
+			choice.priorityFee = parseAmountArg("max priority fee", f.maxPriorityFee, "gwei")
+		}
+		if choice.priorityFee == nil || choice.maxFee == nil {
+			suggestion, err := client.SuggestFees(ctx)
+			if err != nil {
+				fmt.Printf("Error estimating fees: %v\n", err)
+				fmt.Println("Set --max-fee and --max-priority-fee to send without the fee oracle")
+				os.Exit(1)
+			}
+			choice.baseFee = suggestion.BaseFee
+			if choice.priorityFee == nil {
+				choice.priorityFee = suggestion.Preset(speed).MaxPriorityFeePerGas
+			}
+			if choice.maxFee == nil {
+				choice.maxFee = suggestion.MaxFee(speed, choice.priorityFee)
+				choice.source = fmt.Sprintf("%s preset", speed)
+			}
+		}
+		if choice.maxFee.Cmp(choice.priorityFee) < 0 {
+			fmt.Printf("Error: max fee %s gwei is below the priority fee %s gwei\n", units.FormatGwei(choice.maxFee), units.FormatGwei(choice.priorityFee))
+			os.Exit(1)
+		}
+		choice.opts = append(choice.opts, ethereum.WithMaxFeePerGas(choice.maxFee))
+	}
+
+	if choice.maxFee != nil && choice.feeCap != nil && choice.maxFee.Cmp(choice.feeCap) > 0 {
+		fmt.Printf("Error: max fee %s gwei is above --fee-cap %s gwei; refusing to sign\n", units.FormatGwei(choice.maxFee), units.FormatGwei(choice.feeCap))
+		os.Exit(1)
+	}
+	return choice
+}
+
+// print displays the chosen fees
+func (c *feeChoice) print() {
+	if c.useLegacy {
+		if c.maxFee != nil {
+			fmt.Printf("Gas price:    %s gwei (--max-fee)\n", units.FormatGwei(c.maxFee))
+		}
+	} else {
+		if c.baseFee != nil {
+			fmt.Printf("Base fee:     %s gwei\n", units.FormatGwei(c.baseFee))
+		}
+		fmt.Printf("Priority fee: %s gwei\n", units.FormatGwei(c.priorityFee))
+		fmt.Printf("Max fee:      %s gwei (%s)\n", units.FormatGwei(c.maxFee), c.source)
+	}
+	if c.feeCap != nil {
+		fmt.Printf("Fee cap:      %s gwei\n", units.FormatGwei(c.feeCap))
+	}
+}
@@ -15,12 +15,27 @@ func NewKeygenCmd() *cobra.Command {
 	var useSimpleKey bool
 	var hdPath string
 	var mnemonic string
+	var keystoreDir string
+	var usePassphrase bool
+	var words int
 
 	cmd := &cobra.Command{
 		Use:   "keygen",
 		Short: "Generate a new Ethereum wallet",
-		Long:  `Generate a new Ethereum wallet (HD wallet by default) or a simple private key wallet.`,
+		Long: `Generate a new Ethereum wallet (HD wallet by default) or a simple private key wallet.
+HD wallets have a 12-word mnemonic unless --words is given. With --passphrase the
+seed also uses a BIP-39 passphrase (the "25th word"), which is prompted for without
+echoing and never saved; it is needed with the mnemonic to restore the wallet.`,
 		Run: func(cmd *cobra.Command, args []string) {
+			if useSimpleKey && (usePassphrase || cmd.Flags().Changed("words")) {
+				fmt.Println("Error: --passphrase and --words only apply to HD wallets")
+				os.Exit(1)
+			}
+			if mnemonic != "" && cmd.Flags().Changed("words") {
+				fmt.Println("Error: --words cannot be used with --mnemonic")
+				os.Exit(1)
+			}
+
 			// Load existing environment if saving
 			if saveToEnv {
 				ethereum.LoadEnvVariables()
@@ -34,11 +49,7 @@ func NewKeygenCmd() *cobra.Command {
 			// Import existing mnemonic if provided
 			if mnemonic != "" {
 				// Use provided mnemonic to import HD wallet
-				hdKeyPair, err := ethereum.ImportHDWallet(mnemonic, hdPath)
-				if err != nil {
-					fmt.Printf("Error importing HD wallet from mnemonic: %v\n", err)
-					os.Exit(1)
-				}
+				hdKeyPair := importHDWallet(mnemonic, hdPath, usePassphrase, true)
 
 				privateKeyHex = ethereum.ExportPrivateKey(hdKeyPair.KeyPair)
 				address = hdKeyPair.KeyPair.Address.Hex()
@@ -51,9 +62,14 @@ func NewKeygenCmd() *cobra.Command {
 				fmt.Printf("Private Key: %s\n", privateKeyHex)
 				fmt.Printf("Mnemonic:    %s\n", mnemonicPhrase)
 				fmt.Printf("HD Path:     %s\n", walletPath)
+				displayPassphraseUse(hdKeyPair.HDInfo)
+
+				if keystoreDir != "" {
+					writeKeystore(hdKeyPair.KeyPair, keystoreDir)
+				}
 
 				if saveToEnv {
-					err := updateEnvFileWithHD(privateKeyHex, address, mnemonicPhrase, walletPath)
+					err := updateEnvFileWithHD(privateKeyHex, address, mnemonicPhrase, walletPath, hdKeyPair.HDInfo.HasPassphrase)
 					if err != nil {
 						fmt.Printf("Error saving to .env file: %v\n", err)
 						return
@@ -81,6 +97,11 @@ func NewKeygenCmd() *cobra.Command {
 				fmt.Printf("Private Key: %s\n", privateKeyHex)
 				fmt.Printf("Address:     %s\n", address)
 
+				// Save to an encrypted keystore if requested
+				if keystoreDir != "" {
+					writeKeystore(keyPair, keystoreDir)
+				}
+
 				// Save to .env file if requested
 				if saveToEnv {
 					err := updateEnvFile(privateKeyHex, address)
@@ -96,11 +117,14 @@ func NewKeygenCmd() *cobra.Command {
 					hdPath = ethereum.DefaultHDPath
 				}
 
-				hdKeyPair, err := ethereum.GenerateHDWallet(hdPath)
+				// Generate the mnemonic first, so an invalid --words fails before
+				// the passphrase prompt
+				newMnemonic, err := ethereum.NewMnemonic(words)
 				if err != nil {
 					fmt.Printf("Error generating HD wallet: %v\n", err)
 					os.Exit(1)
 				}
+				hdKeyPair := importHDWallet(newMnemonic, hdPath, usePassphrase, true)
 
 				// Get key details
 				privateKeyHex = ethereum.ExportPrivateKey(hdKeyPair.KeyPair)
@@ -114,10 +138,16 @@ func NewKeygenCmd() *cobra.Command {
 				fmt.Printf("HD Path:     %s\n", walletPath)
 				fmt.Printf("Address:     %s\n", address)
 				fmt.Printf("Private Key: %s\n", privateKeyHex)
+				displayPassphraseUse(hdKeyPair.HDInfo)
+
+				// Save to an encrypted keystore if requested
+				if keystoreDir != "" {
+					writeKeystore(hdKeyPair.KeyPair, keystoreDir)
+				}
 
 				// Save to .env file if requested
 				if saveToEnv {
-					err := updateEnvFileWithHD(privateKeyHex, address, mnemonicPhrase, walletPath)
+					err := updateEnvFileWithHD(privateKeyHex, address, mnemonicPhrase, walletPath, hdKeyPair.HDInfo.HasPassphrase)
 					if err != nil {
 						fmt.Printf("Error saving to .env file: %v\n", err)
 						return
@@ -136,10 +166,30 @@ func NewKeygenCmd() *cobra.Command {
 	cmd.Flags().BoolVarP(&useSimpleKey, "simple", "", false, "Generate a simple private key instead of HD wallet")
 	cmd.Flags().StringVarP(&hdPath, "path", "p", ethereum.DefaultHDPath, "HD derivation path")
 	cmd.Flags().StringVarP(&mnemonic, "mnemonic", "m", "", "Import existing mnemonic instead of generating")
+	cmd.Flags().StringVarP(&keystoreDir, "keystore", "k", "", "Save the key as an encrypted keystore (v3) file in this directory")
+	cmd.Flags().BoolVar(&usePassphrase, "passphrase", false, "Use a BIP-39 passphrase with the mnemonic (prompted, never saved)")
+	cmd.Flags().IntVar(&words, "words", 12, "Number of mnemonic words: 12, 15, 18, 21 or 24")
 
 	return cmd
 }
 
+// displayPassphraseUse notes when an HD wallet uses a BIP-39 passphrase
+func displayPassphraseUse(info *ethereum.HDWalletInfo) {
+	if info.HasPassphrase {
+		fmt.Println("Passphrase:  yes (not shown or saved; the mnemonic alone does not restore this wallet)")
+	}
+}
+
+// writeKeystore saves the key to an encrypted keystore file, exiting on failure
+func writeKeystore(keyPair *ethereum.KeyPair, dir string) {
+	filename, err := saveToKeystore(keyPair, dir)
+	if err != nil {
+		fmt.Printf("Error saving keystore: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("\nEncrypted keystore saved to %s\n", filename)
+}
+
 // updateEnvFile updates or creates a .env file with the new keys
 func updateEnvFile(privateKey, address string) error {
 	// Create or update .env file
@@ -181,8 +231,9 @@ func updateEnvFile(privateKey, address string) error {
 	return nil
 }
 
-// updateEnvFileWithHD updates or creates a .env file with HD wallet info
-func updateEnvFileWithHD(privateKey, address, mnemonic, hdPath string) error {
+// updateEnvFileWithHD updates or creates a .env file with HD wallet info. A BIP-39
+// passphrase is never written; HD_USE_PASSPHRASE makes --hd prompt for it instead.
+func updateEnvFileWithHD(privateKey, address, mnemonic, hdPath string, usePassphrase bool) error {
 	// Create or update .env file
 	envFile, err := os.OpenFile(".env", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
 	if err != nil {
@@ -198,7 +249,12 @@ func updateEnvFileWithHD(privateKey, address, mnemonic, hdPath string) error {
 		"TEST_ADDRESS=%s\n\n"+
 		"# HD Wallet Configuration\n"+
 		"HD_MNEMONIC=%s\n"+
-		"HD_PATH=%s\n\n", privateKey, address, mnemonic, hdPath)
+		"HD_PATH=%s\n", privateKey, address, mnemonic, hdPath)
+	if usePassphrase {
+		content += "# The BIP-39 passphrase is not stored; --hd prompts for it\n" +
+			"HD_USE_PASSPHRASE=true\n"
+	}
+	content += "\n"
 
 	// Preserve existing settings if any
 	rpcURL := os.Getenv("SEPOLIA_RPC_URL")
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// stdinReader is shared so consecutive prompts don't lose buffered piped input
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// promptPassphrase reads a passphrase from the terminal without echoing it.
+// When confirm is set the passphrase must be entered twice.
+func promptPassphrase(prompt string, confirm bool) (string, error) {
+	passphrase, err := readPassphrase(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	if confirm {
+		repeated, err := readPassphrase("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if passphrase != repeated {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+
+	return passphrase, nil
+}
+
+// readPassphrase reads a single line from stdin, hiding input when stdin is a terminal
+func readPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		passphrase, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		return string(passphrase), nil
+	}
+
+	// Non-interactive input (e.g. piped from a secrets manager)
+	line, err := stdinReader.ReadString('\n')
+	fmt.Fprintln(os.Stderr)
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// loadKeystoreKeyPair decrypts a key from a keystore file, or from the file
+// matching account inside a keystore directory, prompting for the passphrase
+func loadKeystoreKeyPair(keystorePath, account string) (*ethereum.KeyPair, error) {
+	if account != "" {
+		parseAddressArg("account", account)
+	}
+
+	info, err := os.Stat(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("keystore not found: %w", err)
+	}
+
+	filename := keystorePath
+	if info.IsDir() {
+		filename, err = ethereum.FindKeystoreFile(keystorePath, account)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Printf("Using keystore file: %s\n", filename)
+	passphrase, err := promptPassphrase("Keystore passphrase: ", false)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPair, err := ethereum.LoadKeystore(filename, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if account != "" && !strings.EqualFold(keyPair.Address.Hex(), account) {
+		return nil, fmt.Errorf("keystore key %s does not match account %s", keyPair.Address.Hex(), account)
+	}
+
+	return keyPair, nil
+}
+
+// saveToKeystore prompts for a new passphrase and writes an encrypted keystore file
+func saveToKeystore(keyPair *ethereum.KeyPair, dir string) (string, error) {
+	passphrase, err := promptPassphrase("New keystore passphrase: ", true)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("refusing to create a keystore with an empty passphrase")
+	}
+
+	return ethereum.SaveKeystore(keyPair, dir, passphrase, ethereum.StandardScryptN, ethereum.StandardScryptP)
+}
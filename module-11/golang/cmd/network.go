@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// Global network selection, set by the root command's persistent flags
+var (
+	networkName  string
+	networksFile string
+)
+
+// AddNetworkFlags registers the global --network and --networks-file flags on the root command
+func AddNetworkFlags(root *cobra.Command) {
+	root.PersistentFlags().StringVarP(&networkName, "network", "n", "", "Network profile to use (default from ETH_NETWORK or CHAIN_ID, else sepolia)")
+	root.PersistentFlags().StringVar(&networksFile, "networks-file", "", "Network profiles file (default from ETHWALLET_NETWORKS, else networks.json)")
+}
+
+// selectedNetwork returns the network chosen with --network, exiting on error
+func selectedNetwork() *ethereum.Network {
+	path := networksFile
+	if path == "" {
+		path = ethereum.GetNetworksFile()
+	}
+
+	network, err := ethereum.SelectNetwork(networkName, path)
+	if err != nil {
+		fmt.Printf("Error selecting network: %v\n", err)
+		os.Exit(1)
+	}
+	return network
+}
+
+// connectNetwork selects the network and verifies that its node serves the expected
+// chain, exiting on mismatch so nothing is ever signed for the wrong chain
+func connectNetwork(ctx context.Context) (*ethereum.Network, *ethereum.Client) {
+	network := selectedNetwork()
+	client := newRPCClient(network)
+
+	if err := client.VerifyChainID(ctx); err != nil {
+		fmt.Printf("Error verifying network %s: %v\n", network.Name, err)
+		os.Exit(1)
+	}
+	return network, client
+}
+
+// NewNetworksCmd creates a command that lists the available network profiles
+func NewNetworksCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "networks",
+		Short: "List network profiles",
+		Long: `List the built-in network profiles and those defined in the networks file.
+Select one for any command with --network <name>.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			path := networksFile
+			if path == "" {
+				path = ethereum.GetNetworksFile()
+			}
+
+			networks, err := ethereum.LoadNetworks(path)
+			if err != nil {
+				fmt.Printf("Error loading networks: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== NETWORKS ===")
+			for _, name := range ethereum.NetworkNames(networks) {
+				network := networks[name]
+				txType := "EIP-1559"
+				if !network.EIP1559 {
+					txType = "legacy"
+				}
+				fmt.Printf("%-12s chain %-10d %-4s %-8s %s\n", name, network.ChainID, network.Currency, txType, network.RPCURLs[0])
+			}
+		},
+	}
+}
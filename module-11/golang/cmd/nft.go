@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// NewNFTCmd creates the ERC-721/ERC-1155 command group
+func NewNFTCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nft",
+		Short: "Inspect and transfer ERC-721 and ERC-1155 tokens",
+		Long: `Show NFT ownership, balances and metadata, and transfer tokens with
+safeTransferFrom. The standard is detected with ERC-165. Token IDs are decimal
+or 0x hex.`,
+	}
+
+	cmd.AddCommand(newNFTOwnerCmd())
+	cmd.AddCommand(newNFTBalanceCmd())
+	cmd.AddCommand(newNFTBalanceBatchCmd())
+	cmd.AddCommand(newNFTMetadataCmd())
+	cmd.AddCommand(newNFTTransferCmd())
+
+	return cmd
+}
+
+// parseTokenID parses a decimal or 0x hex token ID, exiting on error
+func parseTokenID(s string) *big.Int {
+	id, ok := new(big.Int).SetString(s, 0)
+	if !ok || id.Sign() < 0 || id.BitLen() > 256 {
+		fmt.Printf("Error: Invalid token ID %q\n", s)
+		os.Exit(1)
+	}
+	return id
+}
+
+// loadNFT connects to the network and detects the contract's standard, exiting on error
+func loadNFT(ctx context.Context, address string) (*ethereum.Network, *ethereum.Client, *ethereum.NFT, ethereum.NFTStandard) {
+	contract := parseAddressArg("contract", address)
+	network, client := connectNetwork(ctx)
+
+	nft := ethereum.NewNFT(client, contract)
+	standard, err := nft.DetectStandard(ctx)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return network, client, nft, standard
+}
+
+func newNFTOwnerCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "owner <contract> <tokenId>",
+		Short: "Show the owner of an ERC-721 token",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			tokenID := parseTokenID(args[1])
+
+			ctx := context.Background()
+			_, _, nft, standard := loadNFT(ctx, args[0])
+			if standard != ethereum.ERC721 {
+				fmt.Println("Error: ERC-1155 tokens have no single owner; use nft balance <contract> <address> <tokenId>")
+				os.Exit(1)
+			}
+
+			owner, err := nft.OwnerOf(ctx, tokenID)
+			if err != nil {
+				fmt.Printf("Error getting owner: %v\n", err)
+				printRevertReason(err, nil)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== NFT OWNER ===")
+			fmt.Printf("Contract: %s (%s)\n", nft.Address.Hex(), standard)
+			fmt.Printf("Token ID: %s\n", tokenID.String())
+			fmt.Printf("Owner:    %s\n", owner.Hex())
+		},
+	}
+}
+
+func newNFTBalanceCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "balance <contract> <address> [tokenId]",
+		Short: "Show how many tokens an address holds",
+		Long: `Show the number of ERC-721 tokens held by an address, or its balance of an
+ERC-1155 token ID.`,
+		Args: cobra.RangeArgs(2, 3),
+		Run: func(cmd *cobra.Command, args []string) {
+			owner := parseAddressArg("owner", args[1])
+
+			ctx := context.Background()
+			_, _, nft, standard := loadNFT(ctx, args[0])
+
+			var balance *big.Int
+			var err error
+			if standard == ethereum.ERC1155 {
+				if len(args) < 3 {
+					fmt.Println("Error: ERC-1155 balances need a token ID")
+					os.Exit(1)
+				}
+				balance, err = nft.BalanceOfToken(ctx, owner, parseTokenID(args[2]))
+			} else {
+				if len(args) == 3 {
+					fmt.Println("Error: ERC-721 balances count all tokens; use nft owner for a single token")
+					os.Exit(1)
+				}
+				balance, err = nft.BalanceOf(ctx, owner)
+			}
+			if err != nil {
+				fmt.Printf("Error getting balance: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== NFT BALANCE ===")
+			fmt.Printf("Contract: %s (%s)\n", nft.Address.Hex(), standard)
+			fmt.Printf("Address:  %s\n", owner.Hex())
+			if len(args) == 3 {
+				fmt.Printf("Token ID: %s\n", args[2])
+			}
+			fmt.Printf("Balance:  %s\n", balance.String())
+		},
+	}
+}
+
+func newNFTBalanceBatchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "balance-batch <contract> <address> <tokenId>...",
+		Short: "Show an address's balances of several ERC-1155 token IDs",
+		Long: `Show the balances of several ERC-1155 token IDs with a single balanceOfBatch
+call. Use address:tokenId to query a different address for an ID.`,
+		Args: cobra.MinimumNArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			defaultOwner := parseAddressArg("owner", args[1])
+
+			var accounts []common.Address
+			var ids []*big.Int
+			for _, arg := range args[2:] {
+				owner, idArg := defaultOwner, arg
+				if address, id, found := strings.Cut(arg, ":"); found {
+					owner, idArg = parseAddressArg("owner", address), id
+				}
+				accounts = append(accounts, owner)
+				ids = append(ids, parseTokenID(idArg))
+			}
+
+			ctx := context.Background()
+			_, _, nft, standard := loadNFT(ctx, args[0])
+			if standard != ethereum.ERC1155 {
+				fmt.Println("Error: balanceOfBatch is only available on ERC-1155 contracts")
+				os.Exit(1)
+			}
+
+			balances, err := nft.BalanceOfBatch(ctx, accounts, ids)
+			if err != nil {
+				fmt.Printf("Error getting balances: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== NFT BALANCES ===")
+			fmt.Printf("Contract: %s (%s)\n", nft.Address.Hex(), standard)
+			for i := range ids {
+				fmt.Printf("%s  token %s: %s\n", accounts[i].Hex(), ids[i].String(), balances[i].String())
+			}
+		},
+	}
+}
+
+func newNFTMetadataCmd() *cobra.Command {
+	var printJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "metadata <contract> <tokenId>",
+		Short: "Fetch and display token metadata",
+		Long: `Read tokenURI (ERC-721) or uri (ERC-1155) and fetch the metadata JSON.
+data: URIs are decoded locally; ipfs:// URIs are fetched through IPFS_GATEWAY
+(default https://ipfs.io/ipfs/).`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			tokenID := parseTokenID(args[1])
+
+			ctx := context.Background()
+			_, _, nft, standard := loadNFT(ctx, args[0])
+
+			uri, err := nft.TokenURI(ctx, standard, tokenID)
+			if err != nil {
+				fmt.Printf("Error getting token URI: %v\n", err)
+				printRevertReason(err, nil)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== NFT METADATA ===")
+			fmt.Printf("Contract: %s (%s)\n", nft.Address.Hex(), standard)
+			fmt.Printf("Token ID: %s\n", tokenID.String())
+			fmt.Printf("URI:      %s\n", shorten(uri))
+
+			metadata, err := ethereum.FetchNFTMetadata(ctx, uri)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if printJSON {
+				var out bytes.Buffer
+				if err := json.Indent(&out, metadata.Raw, "", "  "); err != nil {
+					fmt.Println(string(metadata.Raw))
+					return
+				}
+				fmt.Println(out.String())
+				return
+			}
+
+			fmt.Printf("Name:        %s\n", metadata.Name)
+			if metadata.Description != "" {
+				fmt.Printf("Description: %s\n", metadata.Description)
+			}
+			if metadata.Image != "" {
+				fmt.Printf("Image:       %s\n", shorten(metadata.Image))
+			}
+			if metadata.ExternalURL != "" {
+				fmt.Printf("External:    %s\n", metadata.ExternalURL)
+			}
+			if len(metadata.Attributes) > 0 {
+				fmt.Println("Attributes:")
+				for _, attr := range metadata.Attributes {
+					fmt.Printf("  %s: %v\n", attr.TraitType, attr.Value)
+				}
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&printJSON, "json", false, "Print the metadata JSON document")
+	return cmd
+}
+
+// shorten truncates long URIs such as base64 data: URIs for display
+func shorten(s string) string {
+	const limit = 120
+	if len(s) <= limit {
+		return s
+	}
+	return fmt.Sprintf("%s... (%d bytes)", s[:limit], len(s))
+}
+
+func newNFTTransferCmd() *cobra.Command {
+	var flags txFlags
+	var amountArg string
+	var fromArg string
+	var dataHex string
+
+	cmd := &cobra.Command{
+		Use:   "transfer <privateKey> <contract> <to> <tokenId>",
+		Short: "Transfer an NFT with safeTransferFrom",
+		Long: `Transfer an ERC-721 token, or an --amount of an ERC-1155 token, with
+safeTransferFrom. Contract recipients must implement the receiver hook or the
+transfer reverts. With --env or --keystore the private key argument is omitted.`,
+		Args: cobra.RangeArgs(3, 4),
+		Run: func(cmd *cobra.Command, args []string) {
+			keyPair, args := flags.keyPair(args, 3, "contract, to and tokenId")
+			to := parseAddressArg("recipient", args[1])
+			tokenID := parseTokenID(args[2])
+
+			// An approved operator transfers on behalf of the owner
+			from := keyPair.Address
+			if fromArg != "" {
+				from = parseAddressArg("from", fromArg)
+			}
+
+			var data []byte
+			if dataHex != "" {
+				var err error
+				if data, err = ethereum.HexDecode(dataHex); err != nil {
+					fmt.Printf("Error: Invalid --data: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			ctx := context.Background()
+			network, client, nft, standard := loadNFT(ctx, args[0])
+
+			fmt.Println("\n=== NFT TRANSFER ===")
+			fmt.Printf("Contract: %s (%s)\n", nft.Address.Hex(), standard)
+			fmt.Printf("From:     %s\n", from.Hex())
+			fmt.Printf("To:       %s\n", to.Hex())
+			fmt.Printf("Token ID: %s\n", tokenID.String())
+
+			var calldata []byte
+			var encodeErr error
+			if standard == ethereum.ERC1155 {
+				amount, ok := new(big.Int).SetString(amountArg, 10)
+				if !ok || amount.Sign() <= 0 {
+					fmt.Println("Error: Invalid --amount. Please provide a positive integer.")
+					os.Exit(1)
+				}
+				balance, err := nft.BalanceOfToken(ctx, from, tokenID)
+				if err != nil {
+					fmt.Printf("Error getting balance: %v\n", err)
+					os.Exit(1)
+				}
+				if balance.Cmp(amount) < 0 {
+					fmt.Printf("Error: %s holds %s of token %s, cannot transfer %s\n", from.Hex(), balance, tokenID, amount)
+					os.Exit(1)
+				}
+				fmt.Printf("Amount:   %s (balance %s)\n", amount.String(), balance.String())
+				calldata, encodeErr = ethereum.SafeTransferFromERC1155Data(from, to, tokenID, amount, data)
+			} else {
+				if amountArg != "1" {
+					fmt.Println("Error: --amount only applies to ERC-1155 tokens")
+					os.Exit(1)
+				}
+				owner, err := nft.OwnerOf(ctx, tokenID)
+				if err != nil {
+					fmt.Printf("Error getting owner: %v\n", err)
+					printRevertReason(err, nil)
+					os.Exit(1)
+				}
+				if owner != from {
+					fmt.Printf("Error: token %s is owned by %s, not %s\n", tokenID, owner.Hex(), from.Hex())
+					os.Exit(1)
+				}
+				calldata, encodeErr = ethereum.SafeTransferFromERC721Data(from, to, tokenID, data)
+			}
+			if encodeErr != nil {
+				fmt.Printf("Error encoding transfer: %v\n", encodeErr)
+				os.Exit(1)
+			}
+
+			sendContractTx(ctx, &flags, network, client, keyPair, nft.Address, calldata, nil)
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().StringVar(&amountArg, "amount", "1", "Number of ERC-1155 tokens to transfer")
+	cmd.Flags().StringVar(&fromArg, "from", "", "Owner to transfer from when the signer is an approved operator (default: the signer)")
+	cmd.Flags().StringVar(&dataHex, "data", "", "Data passed to the recipient's receiver hook as 0x-prefixed hex")
+
+	return cmd
+}
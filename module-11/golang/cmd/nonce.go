@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// nonceManager opens the shared nonce file, so that sends from several terminals
+// or scripts do not collide. It returns nil, and the node's pending nonce is used,
+// when there is no nonce file location.
+func nonceManager() *ethereum.NonceManager {
+	path, err := ethereum.GetNonceFile()
+	if err != nil {
+		fmt.Printf("Warning: %v; using the node's pending nonce\n", err)
+		return nil
+	}
+	return ethereum.NewNonceManager(path)
+}
+
+// nonceOption returns the send option that takes nonces from nonces, which may be nil
+func nonceOption(nonces *ethereum.NonceManager) ethereum.SendOption {
+	if nonces == nil {
+		return ethereum.WithNonceSource(nil)
+	}
+	return ethereum.WithNonceSource(nonces)
+}
+
+// newTxNonceCmd creates the command that shows and resets the local nonce state
+func newTxNonceCmd() *cobra.Command {
+	var resync bool
+
+	cmd := &cobra.Command{
+		Use:   "nonce <address>",
+		Short: "Show the nonce of the next transaction",
+		Long: `Show the node's pending nonce, the nonce the next send will use and whether an
+earlier nonce was handed out but never reached the node (a gap that blocks every
+later transaction until it is filled).
+
+Nonces are reserved in the file given by ETHWALLET_NONCES, or nonces.json in the
+ethwallet user cache directory. Use --resync to discard the local state and
+start again from the node's pending nonce.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			network, client := connectNetwork(ctx)
+			account := resolveAddressArg(ctx, network, client, "account", args[0])
+
+			path, err := ethereum.GetNonceFile()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			nonces := ethereum.NewNonceManager(path)
+
+			if resync {
+				pending, err := nonces.Resync(ctx, client, account)
+				if err != nil {
+					fmt.Printf("Error resyncing nonce: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Printf("Local nonce state of %s reset to the node's pending nonce %d\n", account.Hex(), pending)
+				return
+			}
+
+			pending, err := client.GetNonce(ctx, account)
+			if err != nil {
+				fmt.Printf("Error getting nonce: %v\n", err)
+				os.Exit(1)
+			}
+			next, err := nonces.Next(network.ChainID, account, pending)
+			if err != nil {
+				fmt.Printf("Error reading nonce file: %v\n", err)
+				os.Exit(1)
+			}
+			gapNonce, gap, err := nonces.Gap(network.ChainID, account, pending)
+			if err != nil {
+				fmt.Printf("Error reading nonce file: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== NONCE ===")
+			fmt.Printf("Address:       %s\n", account.Hex())
+			fmt.Printf("Network:       %s (chain ID %d)\n", network.Name, network.ChainID)
+			fmt.Printf("Node pending:  %d\n", pending)
+			fmt.Printf("Local next:    %d\n", next)
+			if next > pending {
+				fmt.Printf("In flight:     %d reserved nonce(s) the node has not counted yet\n", next-pending)
+			}
+			if gap {
+				fmt.Printf("Gap:           nonce %d never reached the node; the next send fills it\n", gapNonce)
+			}
+			fmt.Printf("Nonce file:    %s\n", path)
+		},
+	}
+
+	cmd.Flags().BoolVar(&resync, "resync", false, "Discard the local nonce state and restart from the node's pending nonce")
+
+	return cmd
+}
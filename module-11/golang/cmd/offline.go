@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// newTxBuildCmd creates the command that prepares an unsigned transaction online
+func newTxBuildCmd() *cobra.Command {
+	var useLegacy bool
+	var fees feeFlags
+	var amountUnit string
+	var accessListArg string
+	var dataHex string
+	var methodSig string
+	var abiPath string
+	var nonce uint64
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "build <fromAddress> <toAddress> <amount> [methodArgs...]",
+		Short: "Prepare an unsigned transaction for offline signing",
+		Long: `Fetch the chain ID, nonce, fees and gas limit of a transaction from the node
+and write it, unsigned, to a JSON file that can be reviewed and then signed on a
+machine without network access with "tx sign".
+Addresses may be ENS names. The amount, --data, --method, --access-list and fee
+flags work as for send. An empty toAddress ("") deploys the --data init code.`,
+		Args: cobra.MinimumNArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			if methodSig == "" && len(args) > 3 {
+				fmt.Println("Error: too many arguments; method arguments require --method")
+				os.Exit(1)
+			}
+			if methodSig != "" && dataHex != "" {
+				fmt.Println("Error: --method and --data cannot be used together")
+				os.Exit(1)
+			}
+
+			amountWei := parseAmountArg("amount", args[2], amountUnit)
+
+			var data []byte
+			if dataHex != "" {
+				var err error
+				data, err = ethereum.HexDecode(dataHex)
+				if err != nil {
+					fmt.Printf("Error: Invalid --data: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			var method *abi.Method
+			if methodSig != "" {
+				method = resolveMethod(loadABI(abiPath), methodSig, len(args)-3)
+				data = packCall(method, args[3:])
+			}
+
+			ctx := context.Background()
+			network, client := connectNetwork(ctx)
+			if !useLegacy && !network.EIP1559 {
+				fmt.Printf("Network %s does not support EIP-1559, building a legacy transaction\n", network.Name)
+				useLegacy = true
+			}
+
+			from := resolveAddressArg(ctx, network, client, "sender", args[0])
+			toAddress := ""
+			if args[1] != "" {
+				toAddress = resolveAddressArg(ctx, network, client, "destination", args[1]).Hex()
+			} else if data == nil {
+				fmt.Println("Error: a contract creation needs its init code in --data")
+				os.Exit(1)
+			}
+
+			fee := fees.resolve(ctx, client, useLegacy)
+			buildOpts := fee.opts
+			if data != nil {
+				buildOpts = append(buildOpts, ethereum.WithData(data))
+			}
+			if accessListArg != "" {
+				accessList := resolveAccessList(ctx, client, from, toAddress, amountWei, data, accessListArg)
+				buildOpts = append(buildOpts, ethereum.WithAccessList(accessList))
+			}
+
+			var tx *ethereum.UnsignedTx
+			var err error
+			if useLegacy {
+				tx, err = client.BuildTransaction(ctx, from, toAddress, amountWei, buildOpts...)
+			} else {
+				tx, err = client.BuildEIP1559Transaction(ctx, from, toAddress, amountWei, fee.priorityFee, buildOpts...)
+			}
+			if err != nil {
+				fmt.Printf("Error building transaction: %v\n", err)
+				os.Exit(1)
+			}
+			tx.Network = network.Name
+			if cmd.Flags().Changed("nonce") {
+				tx.Nonce = nonce
+			}
+
+			fmt.Println("\n=== UNSIGNED TRANSACTION ===")
+			displayUnsignedTx(tx, network.Currency)
+			if method != nil {
+				fmt.Printf("Method:    %s\n", method.Sig())
+			}
+
+			if err := ethereum.SaveUnsignedTx(tx, outPath); err != nil {
+				fmt.Printf("Error saving unsigned transaction: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("\nUnsigned transaction written to %s\n", outPath)
+			fmt.Printf("Review it, then sign it offline with: ethwallet tx sign --keystore <file> %s\n", outPath)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&useLegacy, "legacy", "l", false, "Build a legacy transaction instead of EIP-1559")
+	fees.register(cmd)
+	cmd.Flags().StringVarP(&amountUnit, "unit", "u", "wei", "Unit of an amount without suffix: wei, gwei or ether")
+	cmd.Flags().StringVar(&accessListArg, "access-list", "", "Attach an EIP-2930 access list: \"auto\" to generate it with eth_createAccessList, or a JSON file")
+	cmd.Flags().StringVarP(&dataHex, "data", "d", "", "Transaction calldata as 0x-prefixed hex")
+	cmd.Flags().StringVarP(&methodSig, "method", "m", "", "Contract method to call, e.g. \"transfer(address,uint256)\"; its arguments follow the amount")
+	cmd.Flags().StringVar(&abiPath, "abi", "", "JSON ABI or build artifact used to resolve --method by name")
+	cmd.Flags().Uint64Var(&nonce, "nonce", 0, "Use this nonce instead of the node's pending nonce")
+	cmd.Flags().StringVarP(&outPath, "out", "o", "unsigned-tx.json", "File to write the unsigned transaction to")
+
+	return cmd
+}
+
+// newTxSignCmd creates the command that signs an unsigned transaction without network access
+func newTxSignCmd() *cobra.Command {
+	var signer signerFlags
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "sign <privateKey> <unsignedTxFile>",
+		Short: "Sign an unsigned transaction offline",
+		Long: `Sign a transaction file written by "tx build" and write the raw signed
+transaction as hex, ready for "tx broadcast". This command makes no network
+requests, so it can run on an air-gapped machine. The key must belong to the
+transaction's from address.
+With --env or --keystore the private key argument is omitted.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var privateKeyHex string
+			if signer.keyFromArgs() {
+				if len(args) != 2 {
+					fmt.Println("Error: privateKey and unsignedTxFile are required")
+					os.Exit(1)
+				}
+				privateKeyHex = args[0]
+				args = args[1:]
+			} else if len(args) != 1 {
+				fmt.Println("Error: unsignedTxFile is required")
+				os.Exit(1)
+			}
+
+			tx, err := ethereum.LoadUnsignedTx(args[0])
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== TRANSACTION TO SIGN ===")
+			displayUnsignedTx(tx, currencyForChain(tx.ChainID.Uint64()))
+
+			keyPair := signer.keyPair(privateKeyHex)
+			rawTx, err := tx.Sign(keyPair.PrivateKey)
+			if err != nil {
+				fmt.Printf("Error signing transaction: %v\n", err)
+				os.Exit(1)
+			}
+
+			rawHex := fmt.Sprintf("0x%x", rawTx)
+			if err := os.WriteFile(outPath, []byte(rawHex+"\n"), 0644); err != nil {
+				fmt.Printf("Error saving signed transaction: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== SIGNED TRANSACTION ===")
+			fmt.Printf("Transaction hash: 0x%x\n", ethereum.Keccak256(rawTx))
+			fmt.Printf("Raw transaction:  %s\n", rawHex)
+			fmt.Printf("\nSigned transaction written to %s\n", outPath)
+			fmt.Printf("Broadcast it from an online machine with: ethwallet tx broadcast %s\n", outPath)
+		},
+	}
+
+	signer.register(cmd)
+	cmd.Flags().StringVarP(&outPath, "out", "o", "signed-tx.txt", "File to write the raw signed transaction to")
+
+	return cmd
+}
+
+// newTxBroadcastCmd creates the command that submits a signed transaction
+func newTxBroadcastCmd() *cobra.Command {
+	var confirmations uint64
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "broadcast <rawTxHex|signedTxFile>",
+		Short: "Broadcast a signed transaction",
+		Long: `Submit a raw signed transaction, given as 0x-prefixed hex or as a file written by
+"tx sign", with eth_sendRawTransaction and wait for its receipt.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			rawTx := readRawTx(args[0])
+
+			ctx := context.Background()
+			network, client := connectNetwork(ctx)
+
+			fmt.Println("\n=== BROADCASTING TRANSACTION ===")
+			txHash, err := client.SendRawTransaction(ctx, rawTx)
+			if err != nil {
+				fmt.Printf("Error broadcasting transaction: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Transaction hash: %s\n", txHash)
+			if network.Explorer != "" {
+				fmt.Printf("View on explorer: %s\n", ethereum.FormatTransactionURL(txHash, network.Explorer))
+			}
+
+			fmt.Printf("\nWaiting for %d confirmation(s) (timeout %s)...\n", confirmations, timeout)
+			waitOpts := ethereum.DefaultWaitOptions()
+			waitOpts.Confirmations = confirmations
+			waitOpts.Timeout = timeout
+			// The sender and nonce let a transaction the node never returns be told
+			// apart from one that is still propagating
+			if tx, err := ethereum.DecodeTransaction(rawTx); err == nil && tx.Known() {
+				waitOpts.From, waitOpts.Nonce = &tx.From, tx.Nonce
+			}
+
+			result, err := client.WaitForTransaction(ctx, txHash, waitOpts)
+			displayTxResult(network, result, err)
+
+			if result.Status != ethereum.TxStatusSuccess {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().Uint64VarP(&confirmations, "confirmations", "c", 1, "Number of block confirmations to wait for")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "t", 2*time.Minute, "Maximum time to wait for the transaction to be mined")
+
+	return cmd
+}
+
+// displayUnsignedTx shows every field of an unsigned transaction for review
+func displayUnsignedTx(tx *ethereum.UnsignedTx, currency string) {
+	if tx.Network != "" {
+		fmt.Printf("Network:   %s (chain ID %s)\n", tx.Network, tx.ChainID)
+	} else {
+		fmt.Printf("Chain ID:  %s\n", tx.ChainID)
+	}
+	fmt.Printf("Type:      %s\n", txTypeName(tx.Type))
+	fmt.Printf("From:      %s\n", tx.From.Hex())
+	if tx.To != nil {
+		fmt.Printf("To:        %s\n", tx.To.Hex())
+	} else {
+		fmt.Printf("To:        (contract creation at %s)\n", ethereum.CreateAddress(tx.From, tx.Nonce).Hex())
+	}
+	fmt.Printf("Value:     %s wei (%s %s)\n", tx.Value, ethereum.WeiToEth(tx.Value), currency)
+	fmt.Printf("Nonce:     %d\n", tx.Nonce)
+	fmt.Printf("Gas limit: %d\n", tx.GasLimit)
+	if tx.Type == ethereum.TxTypeDynamicFee {
+		fmt.Printf("Priority fee: %s gwei\n", formatGwei(tx.MaxPriorityFeePerGas))
+		fmt.Printf("Max fee:      %s gwei\n", formatGwei(tx.MaxFeePerGas))
+	} else {
+		fmt.Printf("Gas price:    %s gwei\n", formatGwei(tx.GasPrice))
+	}
+	if tx.AccessList != nil {
+		fmt.Printf("Access list: %d address(es), %d storage key(s)\n", len(tx.AccessList), tx.AccessList.StorageKeyCount())
+	}
+	if len(tx.Data) > 0 {
+		fmt.Printf("Data:      %d bytes\n", len(tx.Data))
+	}
+	maxCost := tx.MaxCost()
+	fmt.Printf("Max cost:  %s wei (%s %s)\n", maxCost, ethereum.WeiToEth(maxCost), currency)
+}
+
+// readRawTx reads a signed transaction given as 0x-prefixed hex or as a file
+// holding the hex, exiting on error
+func readRawTx(arg string) []byte {
+	rawHex := arg
+	if !strings.HasPrefix(rawHex, "0x") {
+		content, err := os.ReadFile(rawHex)
+		if err != nil {
+			fmt.Printf("Error reading signed transaction: %v\n", err)
+			os.Exit(1)
+		}
+		rawHex = strings.TrimSpace(string(content))
+	}
+	rawTx, err := ethereum.HexDecode(rawHex)
+	if err != nil || len(rawTx) == 0 {
+		fmt.Println("Error: Invalid signed transaction; expected 0x-prefixed hex")
+		os.Exit(1)
+	}
+	return rawTx
+}
+
+// txTypeName names a transaction type
+func txTypeName(txType uint64) string {
+	switch txType {
+	case ethereum.TxTypeLegacy:
+		return "Legacy"
+	case ethereum.TxTypeAccessList:
+		return "EIP-2930"
+	case ethereum.TxTypeDynamicFee:
+		return "EIP-1559"
+	}
+	return fmt.Sprintf("type %d", txType)
+}
+
+// networkForChain returns the first network profile, by name, with chainID, or nil
+// when there is none. It reads only local configuration.
+func networkForChain(chainID uint64) *ethereum.Network {
+	path := networksFile
+	if path == "" {
+		path = ethereum.GetNetworksFile()
+	}
+	networks, err := ethereum.LoadNetworks(path)
+	if err != nil {
+		return nil
+	}
+	for _, name := range ethereum.NetworkNames(networks) {
+		if networks[name].ChainID == chainID {
+			return networks[name]
+		}
+	}
+	return nil
+}
+
+// currencyForChain returns the currency of the network profile with chainID, or
+// ETH when there is none
+func currencyForChain(chainID uint64) string {
+	if network := networkForChain(chainID); network != nil && network.Currency != "" {
+		return network.Currency
+	}
+	return "ETH"
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/eip712"
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// permitFlags are the flags of the permit commands, which sign an approval but
+// send no transaction
+type permitFlags struct {
+	signer   signerFlags
+	raw      bool
+	deadline string
+	compact  bool
+}
+
+// register adds the signer and permit flags to a command
+func (f *permitFlags) register(cmd *cobra.Command) {
+	f.signer.register(cmd)
+	cmd.Flags().BoolVar(&f.raw, "raw", false, "Amounts are in base units instead of token units")
+	cmd.Flags().StringVar(&f.deadline, "deadline", "30m", "Signature deadline: a duration from now (e.g. 30m, 24h) or a Unix timestamp")
+	cmd.Flags().BoolVar(&f.compact, "compact", false, "Print the 64-byte EIP-2098 compact signature instead of v, r and s")
+}
+
+func newTokenPermitCmd() *cobra.Command {
+	var flags permitFlags
+
+	cmd := &cobra.Command{
+		Use:   "permit <privateKey> <token> <spender> <amount|max>",
+		Short: "Sign an EIP-2612 permit (gasless approval)",
+		Long: `Sign an EIP-2612 permit that lets the spender set its own allowance over the
+signing account's tokens by submitting the signature, so the owner pays no gas.
+The owner's nonce and the token's name, version and DOMAIN_SEPARATOR are read
+from the chain; the signature is only made when the rebuilt domain matches the
+token's DOMAIN_SEPARATOR. Nothing is sent. "max" permits an unlimited amount.
+The signature is printed as v, r and s, or with --compact as an EIP-2098
+compact signature, along with permit() calldata for a relayer.
+With --env or --keystore the private key argument is omitted.`,
+		Args: cobra.RangeArgs(3, 4),
+		Run: func(cmd *cobra.Command, args []string) {
+			keyPair, args := flags.signer.keyPairFromArgs(args, 3, "token, spender and amount")
+			spender := parseAddressArg("spender", args[1])
+			deadline := parseDeadline("deadline", flags.deadline)
+
+			ctx := context.Background()
+			_, _, token, info := loadToken(ctx, args[0])
+
+			amount := ethereum.MaxUint256
+			if args[2] != "max" {
+				amount = parseTokenAmountArg(args[2], flags.raw, info)
+			}
+
+			td, err := token.Permit(ctx, keyPair.Address, spender, amount, deadline)
+			if err != nil {
+				fmt.Printf("Error preparing permit: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== TOKEN PERMIT (EIP-2612) ===")
+			fmt.Printf("Token:     %s (%s)\n", info.Symbol, info.Address.Hex())
+			fmt.Printf("Owner:     %s\n", keyPair.Address.Hex())
+			fmt.Printf("Spender:   %s\n", spender.Hex())
+			fmt.Printf("Allowance: %s\n", formatToken(amount, info))
+			fmt.Printf("Nonce:     %s\n", td.Message["nonce"])
+			fmt.Printf("Deadline:  %s\n", formatTimestamp(deadline))
+			if amount.Cmp(ethereum.MaxUint256) == 0 {
+				fmt.Println("Warning: the spender may transfer all of your tokens of this kind")
+			}
+			displayTypedData(td)
+
+			signature := signPermit(keyPair, td)
+			displayPermitSignature(signature, flags.compact)
+
+			data, err := ethereum.PermitData(keyPair.Address, spender, amount, deadline, signature)
+			if err != nil {
+				fmt.Printf("Error encoding permit: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("\nCalldata for %s, permit(owner, spender, value, deadline, v, r, s):\n0x%x\n", info.Address.Hex(), data)
+		},
+	}
+
+	flags.register(cmd)
+	return cmd
+}
+
+func newTokenPermit2Cmd() *cobra.Command {
+	var flags permitFlags
+	var expiration string
+	var permit2Arg string
+
+	cmd := &cobra.Command{
+		Use:   "permit2 <privateKey> <spender> <token:amount>...",
+		Short: "Sign a Uniswap Permit2 PermitSingle or PermitBatch",
+		Long: `Sign a Permit2 (AllowanceTransfer) permit giving the spender an allowance over
+one token (PermitSingle) or several tokens (PermitBatch), each written as
+token:amount with "max" for an unlimited amount. Permit2 can only move tokens
+the owner has approved to the Permit2 contract; a missing approval is reported.
+The nonce of each token is read from the Permit2 contract. Nothing is sent.
+--deadline limits when the signature can be submitted and --expiration when
+the allowance it grants ends.
+With --env or --keystore the private key argument is omitted.`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var privateKeyHex string
+			if flags.signer.keyFromArgs() {
+				if len(args) < 3 {
+					fmt.Println("Error: privateKey, spender and at least one token:amount are required")
+					os.Exit(1)
+				}
+				privateKeyHex = args[0]
+				args = args[1:]
+			}
+			keyPair := flags.signer.keyPair(privateKeyHex)
+
+			spender := parseAddressArg("spender", args[0])
+			permit2 := ethereum.Permit2Address
+			if permit2Arg != "" {
+				permit2 = parseAddressArg("Permit2", permit2Arg)
+			}
+			deadline := parseDeadline("deadline", flags.deadline)
+			expires := parseDeadline("expiration", expiration)
+
+			ctx := context.Background()
+			_, client := connectNetwork(ctx)
+
+			fmt.Println("\n=== PERMIT2 ALLOWANCES ===")
+			fmt.Printf("Owner:      %s\n", keyPair.Address.Hex())
+			fmt.Printf("Spender:    %s\n", spender.Hex())
+			fmt.Printf("Expiration: %s\n", formatTimestamp(expires))
+			fmt.Printf("Deadline:   %s\n", formatTimestamp(deadline))
+
+			details := make([]ethereum.Permit2Details, len(args)-1)
+			for i, arg := range args[1:] {
+				tokenArg, amountArg, ok := strings.Cut(arg, ":")
+				if !ok {
+					fmt.Printf("Error: Invalid allowance %q; expected token:amount\n", arg)
+					os.Exit(1)
+				}
+				token := ethereum.NewToken(client, parseAddressArg("token", tokenArg))
+				info, err := token.Info(ctx)
+				if err != nil {
+					fmt.Printf("Error reading token: %v\n", err)
+					os.Exit(1)
+				}
+
+				amount := ethereum.MaxUint160
+				if amountArg != "max" {
+					amount = parseTokenAmountArg(amountArg, flags.raw, info)
+				}
+				details[i] = ethereum.Permit2Details{Token: info.Address, Amount: amount, Expiration: expires}
+
+				formatted := formatToken(amount, info)
+				if amount.Cmp(ethereum.MaxUint160) == 0 {
+					formatted = "unlimited"
+				}
+				fmt.Printf("Token:      %s (%s): %s\n", info.Symbol, info.Address.Hex(), formatted)
+
+				approved, err := token.Allowance(ctx, keyPair.Address, permit2)
+				if err == nil && approved.Cmp(amount) < 0 {
+					fmt.Printf("Note: only %s is approved to Permit2; approve it with: ethwallet token approve %s %s max\n",
+						formatToken(approved, info), info.Address.Hex(), permit2.Hex())
+				}
+			}
+
+			td, err := client.Permit2(ctx, permit2, keyPair.Address, spender, details, deadline)
+			if err != nil {
+				fmt.Printf("Error preparing Permit2 permit: %v\n", err)
+				os.Exit(1)
+			}
+			displayTypedData(td)
+
+			signature := signPermit(keyPair, td)
+			displayPermitSignature(signature, flags.compact)
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().StringVar(&expiration, "expiration", "720h", "When the allowance expires: a duration from now or a Unix timestamp")
+	cmd.Flags().StringVar(&permit2Arg, "permit2", "", "Permit2 contract address (default the canonical deployment)")
+	return cmd
+}
+
+// parseDeadline parses a duration from now such as "30m" or a Unix timestamp
+// into a Unix timestamp, exiting when it is invalid or in the past
+func parseDeadline(name, s string) *big.Int {
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			fmt.Printf("Error: Invalid %s %q: the duration must be positive\n", name, s)
+			os.Exit(1)
+		}
+		return big.NewInt(time.Now().Add(d).Unix())
+	}
+
+	timestamp, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		fmt.Printf("Error: Invalid %s %q: expected a duration such as 30m or a Unix timestamp\n", name, s)
+		os.Exit(1)
+	}
+	if timestamp.Cmp(big.NewInt(time.Now().Unix())) <= 0 {
+		fmt.Printf("Error: Invalid %s %s: the time is in the past\n", name, s)
+		os.Exit(1)
+	}
+	return timestamp
+}
+
+// formatTimestamp formats a Unix timestamp with its UTC time
+func formatTimestamp(timestamp *big.Int) string {
+	if !timestamp.IsInt64() || timestamp.Cmp(ethereum.MaxUint48) > 0 {
+		return timestamp.String()
+	}
+	return fmt.Sprintf("%s (%s)", timestamp, time.Unix(timestamp.Int64(), 0).UTC().Format(time.RFC3339))
+}
+
+// signPermit signs permit typed data, exiting on error
+func signPermit(keyPair *ethereum.KeyPair, td *eip712.TypedData) []byte {
+	signature, err := ethereum.SignTypedData(keyPair.PrivateKey, td)
+	if err != nil {
+		fmt.Printf("Error signing permit: %v\n", err)
+		os.Exit(1)
+	}
+	return signature
+}
+
+// displayPermitSignature shows a signature as v, r and s, or as an EIP-2098
+// compact signature when compact is set
+func displayPermitSignature(signature []byte, compact bool) {
+	fmt.Println("\n=== PERMIT SIGNATURE ===")
+	fmt.Printf("Signature: 0x%x\n", signature)
+	if compact {
+		short, err := ethereum.CompactSignature(signature)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Compact:   0x%x (EIP-2098)\n", short)
+		return
+	}
+	fmt.Printf("v: %d\n", signature[64])
+	fmt.Printf("r: %s\n", common.BytesToHash(signature[:32]).Hex())
+	fmt.Printf("s: %s\n", common.BytesToHash(signature[32:64]).Hex())
+}
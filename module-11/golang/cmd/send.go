@@ -7,164 +7,153 @@ import (
 	"os"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
 	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+	"github.com/metana-bootcamp/ethwallet/internal/units"
 )
 
 // NewSendCmd creates a new send command
 func NewSendCmd() *cobra.Command {
-	var useEnvVar bool
+	var signer signerFlags
 	var verbose bool
 	var useLegacy bool
-	var useMnemonic bool
-	var priorityFeeGwei float64
+	var fees feeFlags
+	var amountUnit string
+	var confirmations uint64
+	var timeout time.Duration
+	var accessListArg string
+	var dataHex string
+	var methodSig string
+	var abiPath string
 
 	cmd := &cobra.Command{
-		Use:   "send <privateKey> <toAddress> <amountWei>",
+		Use:   "send <privateKey> <toAddress> <amount> [methodArgs...]",
 		Short: "Send Ethereum transaction",
 		Long: `Send an Ethereum transaction with the specified parameters.
-Amount must be specified in wei. Uses EIP-1559 transaction by default.`,
-		Args: cobra.RangeArgs(2, 3),
+The destination may be an ENS name such as vitalik.eth.
+The amount is in wei unless --unit is given or it has a unit suffix such as
+0.05ether or 12gwei; amounts are converted exactly. Uses EIP-1559 transaction by default.
+With --env or --keystore the private key argument is omitted.
+Use --data to call a contract with ABI-encoded calldata, or --method with a
+signature such as "transfer(address,uint256)" followed by its arguments.`,
+		Args: cobra.MinimumNArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
-			var privateKeyHex string
-			var toAddress string
-			var amountWei *big.Int
-			var fromAddress string
-			var keyPair *ethereum.KeyPair
-			var hdKeyPair *ethereum.HDKeyPair
-
-			envLoaded := ethereum.LoadEnvVariables()
-
 			// Get arguments
-			if useEnvVar {
-				// If using private key from environment variable
-				if useMnemonic {
-					// Use mnemonic from environment
-					mnemonic := os.Getenv("HD_MNEMONIC")
-					hdPath := os.Getenv("HD_PATH")
-
-					if mnemonic == "" {
-						fmt.Println("Error: HD_MNEMONIC not set in environment variables")
-						os.Exit(1)
-					}
-
-					if hdPath == "" {
-						hdPath = ethereum.DefaultHDPath
-					}
-
-					// Import HD wallet
-					var err error
-					hdKeyPair, err = ethereum.ImportHDWallet(mnemonic, hdPath)
-					if err != nil {
-						fmt.Printf("Error importing HD wallet: %v\n", err)
-						os.Exit(1)
-					}
-
-					privateKeyHex = ethereum.ExportPrivateKey(hdKeyPair.KeyPair)
-					keyPair = hdKeyPair.KeyPair
-					fromAddress = keyPair.Address.Hex()
-				} else {
-					// Use private key from environment
-					privateKeyHex = os.Getenv("TEST_PRIVATE_KEY")
-					if privateKeyHex == "" {
-						if !envLoaded {
-							fmt.Println("Error: No .env file found and TEST_PRIVATE_KEY environment variable not set")
-						} else {
-							fmt.Println("Error: TEST_PRIVATE_KEY not set in .env or environment variables")
-						}
-						os.Exit(1)
-					}
-
-					// Import key
-					var err error
-					keyPair, err = ethereum.ImportPrivateKey(privateKeyHex)
-					if err != nil {
-						fmt.Printf("Error importing private key: %v\n", err)
-						os.Exit(1)
-					}
-					fromAddress = keyPair.Address.Hex()
-				}
-
-				// Get destination and amount from args
-				if len(args) < 2 {
-					fmt.Println("Error: toAddress and amountWei are required")
-					os.Exit(1)
-				}
-				toAddress = args[0]
-
-				// Parse amount
-				amount, success := new(big.Int).SetString(args[1], 10)
-				if !success {
-					fmt.Println("Error: Invalid amount format. Please provide a decimal value in wei.")
-					os.Exit(1)
-				}
-				amountWei = amount
-			} else {
+			var privateKeyHex string
+			if signer.keyFromArgs() {
 				// If specifying private key directly
 				if len(args) < 3 {
-					fmt.Println("Error: privateKey, toAddress and amountWei are required")
+					fmt.Println("Error: privateKey, toAddress and amount are required")
 					os.Exit(1)
 				}
 				privateKeyHex = args[0]
-				toAddress = args[1]
+				args = args[1:]
+			} else if len(args) < 2 {
+				fmt.Println("Error: toAddress and amount are required")
+				os.Exit(1)
+			}
 
-				// Parse amount
-				amount, success := new(big.Int).SetString(args[2], 10)
-				if !success {
-					fmt.Println("Error: Invalid amount format. Please provide a decimal value in wei.")
-					os.Exit(1)
-				}
-				amountWei = amount
+			if methodSig == "" && len(args) > 2 {
+				fmt.Println("Error: too many arguments; method arguments require --method")
+				os.Exit(1)
+			}
+			if methodSig != "" && dataHex != "" {
+				fmt.Println("Error: --method and --data cannot be used together")
+				os.Exit(1)
+			}
+
+			keyPair := signer.keyPair(privateKeyHex)
+			fromAddress := keyPair.Address.Hex()
+			toAddress := args[0]
 
-				// Import key
+			// Parse amount
+			amountWei := parseAmountArg("amount", args[1], amountUnit)
+
+			// Parse calldata
+			var data []byte
+			if dataHex != "" {
 				var err error
-				keyPair, err = ethereum.ImportPrivateKey(privateKeyHex)
+				data, err = ethereum.HexDecode(dataHex)
 				if err != nil {
-					fmt.Printf("Error importing private key: %v\n", err)
+					fmt.Printf("Error: Invalid --data: %v\n", err)
 					os.Exit(1)
 				}
-				fromAddress = keyPair.Address.Hex()
 			}
 
-			// Validate inputs
-			if !isValidAddress(toAddress) {
-				fmt.Println("Error: Invalid destination address. Must be in format 0x...")
-				os.Exit(1)
+			// Encode a contract call from its signature and arguments
+			contractABI := loadABI(abiPath)
+			var method *abi.Method
+			if methodSig != "" {
+				method = resolveMethod(contractABI, methodSig, len(args)-2)
+				data = packCall(method, args[2:])
+			}
+
+			// Select the network and check its node serves the expected chain
+			ctx := context.Background()
+			network, client := connectNetwork(ctx)
+			if !useLegacy && !network.EIP1559 {
+				fmt.Printf("Network %s does not support EIP-1559, sending a legacy transaction\n", network.Name)
+				useLegacy = true
+			}
+
+			// Choose the fees before anything is signed
+			fee := fees.resolve(ctx, client, useLegacy)
+
+			// Resolve an ENS name and validate the destination
+			to := resolveAddressArg(ctx, network, client, "destination", toAddress)
+			toAddress = to.Hex()
+
+			// Resolve the access list, generating it from the node with "auto"
+			sendOpts := append(fee.opts, nonceOption(nonceManager()))
+			if data != nil {
+				sendOpts = append(sendOpts, ethereum.WithData(data))
+			}
+			var accessList ethereum.AccessList
+			if accessListArg != "" {
+				accessList = resolveAccessList(ctx, client, keyPair.Address, toAddress, amountWei, data, accessListArg)
+				sendOpts = append(sendOpts, ethereum.WithAccessList(accessList))
 			}
 
 			// Display transaction info
 			fmt.Println("\n=== TRANSACTION DETAILS ===")
-			fmt.Printf("From:   %s\n", fromAddress)
-			fmt.Printf("To:     %s\n", toAddress)
-			fmt.Printf("Amount: %s wei (%s ETH)\n", amountWei.String(), ethereum.WeiToEth(amountWei))
-			if useLegacy {
+			fmt.Printf("Network: %s (chain ID %d)\n", network.Name, network.ChainID)
+			fmt.Printf("From:   %s%s\n", fromAddress, ensNameSuffix(ctx, network, client, keyPair.Address))
+			fmt.Printf("To:     %s%s\n", toAddress, ensNameSuffix(ctx, network, client, to))
+			fmt.Printf("Amount: %s wei (%s %s)\n", amountWei.String(), ethereum.WeiToEth(amountWei), network.Currency)
+			if useLegacy && accessList != nil {
+				fmt.Printf("Type:   EIP-2930\n")
+			} else if useLegacy {
 				fmt.Printf("Type:   Legacy\n")
 			} else {
 				fmt.Printf("Type:   EIP-1559\n")
 			}
-
-			if !useLegacy {
-				fmt.Printf("Priority Fee: %.2f Gwei\n", priorityFeeGwei)
+			if accessList != nil {
+				fmt.Printf("Access list: %d address(es), %d storage key(s)\n", len(accessList), accessList.StorageKeyCount())
+			}
+			if method != nil {
+				fmt.Printf("Method: %s\n", method.Sig())
+			}
+			if data != nil {
+				fmt.Printf("Data:   %d bytes\n", len(data))
 			}
 
-			// Get RPC URL and block explorer URL
-			rpcURL := ethereum.GetRPCURL()
-			blockExplorer := ethereum.GetBlockExplorerURL()
-			ctx := context.Background()
+			fee.print()
 
 			// Check balance
-			balance, err := ethereum.GetBalance(ctx, keyPair.Address, rpcURL)
+			balance, err := client.GetBalance(ctx, keyPair.Address)
 			if err != nil {
 				fmt.Printf("Error checking balance: %v\n", err)
 				os.Exit(1)
 			}
 
-			fmt.Printf("Current Balance: %s ETH\n", ethereum.WeiToEth(balance))
+			fmt.Printf("Current Balance: %s %s\n", ethereum.WeiToEth(balance), network.Currency)
 
 			// Display verbose transaction info if requested
 			if verbose {
-				displayVerboseInfo(ctx, keyPair, toAddress, amountWei, rpcURL, useLegacy, priorityFeeGwei)
+				displayVerboseInfo(ctx, client, network, keyPair, toAddress, amountWei, fee, sendOpts)
 			}
 
 			// Send transaction
@@ -172,90 +161,96 @@ Amount must be specified in wei. Uses EIP-1559 transaction by default.`,
 			fmt.Println("Sending transaction to network...")
 
 			var txHash string
+			var nonce uint64
+			sendOpts = append(sendOpts, ethereum.WithSentNonce(&nonce))
 
 			if useLegacy {
-				// Send legacy transaction
-				txHash, err = ethereum.SendTransaction(ctx, keyPair, toAddress, amountWei, rpcURL)
+				// Send legacy transaction (EIP-2930 with an access list)
+				txHash, err = client.SendTransaction(ctx, keyPair, toAddress, amountWei, sendOpts...)
 			} else {
 				// Send EIP-1559 transaction
-				priorityFeeWei := big.NewInt(int64(priorityFeeGwei * 1e9))
-				txHash, err = ethereum.SendEIP1559Transaction(ctx, keyPair, toAddress, amountWei, rpcURL, priorityFeeWei)
+				txHash, err = client.SendEIP1559Transaction(ctx, keyPair, toAddress, amountWei, fee.priorityFee, sendOpts...)
 			}
 
 			if err != nil {
 				fmt.Printf("Error sending transaction: %v\n", err)
+				printRevertReason(err, contractABI)
 				os.Exit(1)
 			}
 
 			// Display success info
 			fmt.Println("\n✅ TRANSACTION SENT SUCCESSFULLY!")
 			fmt.Printf("Transaction hash: %s\n", txHash)
-			fmt.Printf("View on Etherscan: %s\n", ethereum.FormatTransactionURL(txHash, blockExplorer))
+			if network.Explorer != "" {
+				fmt.Printf("View on explorer: %s\n", ethereum.FormatTransactionURL(txHash, network.Explorer))
+			}
+
+			// Wait for the receipt
+			fmt.Printf("\nWaiting for %d confirmation(s) (timeout %s)...\n", confirmations, timeout)
+			waitOpts := ethereum.DefaultWaitOptions()
+			waitOpts.Confirmations = confirmations
+			waitOpts.Timeout = timeout
+			waitOpts.From, waitOpts.Nonce = &keyPair.Address, nonce
+
+			result, err := client.WaitForTransaction(ctx, txHash, waitOpts)
+			displayTxResult(network, result, err)
 
-			// Wait for confirmation
-			fmt.Println("\nWaiting for transaction confirmation (10 seconds)...")
-			time.Sleep(10 * time.Second)
+			if result.Status != ethereum.TxStatusSuccess {
+				os.Exit(1)
+			}
 
 			// Check new balance
-			newBalance, err := ethereum.GetBalance(ctx, keyPair.Address, rpcURL)
+			newBalance, err := client.GetBalance(ctx, keyPair.Address)
 			if err != nil {
 				fmt.Printf("Error getting updated balance: %v\n", err)
 				return
 			}
 
-			// Calculate difference
-			diff := new(big.Int).Sub(balance, newBalance)
-			fmt.Println("\n=== TRANSACTION COMPLETE ===")
-			fmt.Printf("New balance: %s ETH\n", ethereum.WeiToEth(newBalance))
-			fmt.Printf("Amount spent: %s ETH\n", ethereum.WeiToEth(diff))
+			fmt.Printf("New balance: %s %s\n", ethereum.WeiToEth(newBalance), network.Currency)
 		},
 	}
 
 	// Add flags
-	cmd.Flags().BoolVarP(&useEnvVar, "env", "e", false, "Use private key from TEST_PRIVATE_KEY environment variable")
+	signer.register(cmd)
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Display verbose transaction information")
 	cmd.Flags().BoolVarP(&useLegacy, "legacy", "l", false, "Use legacy transaction instead of EIP-1559")
-	cmd.Flags().BoolVarP(&useMnemonic, "hd", "", false, "Use HD wallet from HD_MNEMONIC environment variable")
-	cmd.Flags().Float64VarP(&priorityFeeGwei, "priority-fee", "f", 1.5, "Priority fee in Gwei for EIP-1559 transactions")
+	fees.register(cmd)
+	cmd.Flags().StringVarP(&amountUnit, "unit", "u", "wei", "Unit of an amount without suffix: wei, gwei or ether")
+	cmd.Flags().Uint64VarP(&confirmations, "confirmations", "c", 1, "Number of block confirmations to wait for")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "t", 2*time.Minute, "Maximum time to wait for the transaction to be mined")
+	cmd.Flags().StringVar(&accessListArg, "access-list", "", "Attach an EIP-2930 access list: \"auto\" to generate it with eth_createAccessList, or a JSON file")
+	cmd.Flags().StringVarP(&dataHex, "data", "d", "", "Transaction calldata as 0x-prefixed hex")
+	cmd.Flags().StringVarP(&methodSig, "method", "m", "", "Contract method to call, e.g. \"transfer(address,uint256)\"; its arguments follow the amount")
+	cmd.Flags().StringVar(&abiPath, "abi", "", "JSON ABI or build artifact used to resolve --method by name and decode custom errors")
 
 	return cmd
 }
 
 // Display verbose transaction information
-func displayVerboseInfo(ctx context.Context, keyPair *ethereum.KeyPair, toAddress string, amountWei *big.Int, rpcURL string, useLegacy bool, priorityFeeGwei float64) {
+func displayVerboseInfo(ctx context.Context, client *ethereum.Client, network *ethereum.Network, keyPair *ethereum.KeyPair, toAddress string, amountWei *big.Int, fee *feeChoice, sendOpts []ethereum.SendOption) {
 	fmt.Println("\n=== NETWORK INFORMATION ===")
-	fmt.Printf("RPC URL: %s\n", rpcURL)
-
-	// Get chain ID
-	chainID, err := ethereum.GetChainID(ctx, rpcURL)
-	if err != nil {
-		fmt.Printf("Error getting chainID: %v\n", err)
-		os.Exit(1)
-	}
-	fmt.Printf("Chain ID: %s\n", chainID.String())
+	fmt.Printf("Network: %s\n", network.Name)
+	fmt.Printf("RPC URL: %s\n", client.URL())
 
-	// Get nonce
-	nonce, err := ethereum.GetNonce(ctx, keyPair.Address, rpcURL)
+	// Get chain ID, nonce, gas estimate and base fee in one batch
+	preflight, err := client.Preflight(ctx, keyPair.Address, toAddress, amountWei, sendOpts...)
 	if err != nil {
-		fmt.Printf("Error getting nonce: %v\n", err)
-		os.Exit(1)
-	}
-	fmt.Printf("Nonce: %d\n", nonce)
-
-	// Estimate gas
-	gasLimit, err := ethereum.EstimateGas(ctx, keyPair.Address.Hex(), toAddress, amountWei, rpcURL)
-	if err != nil {
-		fmt.Printf("Error estimating gas: %v\n", err)
+		fmt.Printf("Error preparing transaction: %v\n", err)
 		os.Exit(1)
 	}
+	gasLimit := preflight.GasLimit
+	fmt.Printf("Chain ID: %s\n", preflight.ChainID.String())
+	fmt.Printf("Nonce: %d\n", preflight.Nonce)
 	fmt.Printf("Gas limit: %d\n", gasLimit)
 
-	if useLegacy {
-		// Get gas price for legacy transaction
-		gasPrice, err := ethereum.GetGasPrice(ctx, rpcURL)
-		if err != nil {
-			fmt.Printf("Error getting gas price: %v\n", err)
-			os.Exit(1)
+	if fee.useLegacy {
+		// Get gas price for legacy transaction unless --max-fee set it
+		gasPrice := fee.maxFee
+		if gasPrice == nil {
+			if gasPrice, err = client.GetGasPrice(ctx); err != nil {
+				fmt.Printf("Error getting gas price: %v\n", err)
+				os.Exit(1)
+			}
 		}
 		fmt.Printf("Gas price: %s wei (%s gwei)\n", gasPrice.String(), formatGwei(gasPrice))
 
@@ -263,54 +258,93 @@ func displayVerboseInfo(ctx context.Context, keyPair *ethereum.KeyPair, toAddres
 		gasCost := new(big.Int).Mul(gasPrice, big.NewInt(int64(gasLimit)))
 		totalCost := new(big.Int).Add(amountWei, gasCost)
 
-		fmt.Printf("Gas cost (estimated): %s wei (%s ETH)\n", gasCost.String(), ethereum.WeiToEth(gasCost))
-		fmt.Printf("Total cost: %s wei (%s ETH)\n", totalCost.String(), ethereum.WeiToEth(totalCost))
+		fmt.Printf("Gas cost (estimated): %s wei (%s %s)\n", gasCost.String(), ethereum.WeiToEth(gasCost), network.Currency)
+		fmt.Printf("Total cost: %s wei (%s %s)\n", totalCost.String(), ethereum.WeiToEth(totalCost), network.Currency)
 	} else {
-		// Get base fee for EIP-1559
-		baseFee, err := ethereum.GetBaseFee(ctx, rpcURL)
-		if err != nil {
-			fmt.Printf("Error getting base fee: %v\n", err)
-			baseFee = big.NewInt(30_000_000_000) // 30 gwei default
-		}
-
-		// Priority tip
-		priorityFeeWei := big.NewInt(int64(priorityFeeGwei * 1e9))
-
-		// Calculate max fee: baseFee * 2 + tip
-		maxFee := new(big.Int).Mul(baseFee, big.NewInt(2))
-		maxFee = new(big.Int).Add(maxFee, priorityFeeWei)
+		// Base fee of the latest block and the fees chosen for the transaction
+		baseFee, maxFee := preflight.BaseFee, fee.maxFee
 
-		fmt.Printf("Base fee: %s wei (%s gwei)\n", baseFee.String(), formatGwei(baseFee))
-		fmt.Printf("Priority tip: %s wei (%s gwei)\n", priorityFeeWei.String(), formatGwei(priorityFeeWei))
+		if baseFee != nil {
+			fmt.Printf("Base fee: %s wei (%s gwei)\n", baseFee.String(), formatGwei(baseFee))
+		}
+		fmt.Printf("Priority tip: %s wei (%s gwei)\n", fee.priorityFee.String(), formatGwei(fee.priorityFee))
 		fmt.Printf("Max fee: %s wei (%s gwei)\n", maxFee.String(), formatGwei(maxFee))
 
-		// Calculate total cost
+		// Calculate the worst-case total cost
 		gasCost := new(big.Int).Mul(maxFee, big.NewInt(int64(gasLimit)))
 		totalCost := new(big.Int).Add(amountWei, gasCost)
 
-		fmt.Printf("Gas cost (estimated): %s wei (%s ETH)\n", gasCost.String(), ethereum.WeiToEth(gasCost))
-		fmt.Printf("Total cost: %s wei (%s ETH)\n", totalCost.String(), ethereum.WeiToEth(totalCost))
+		fmt.Printf("Gas cost (estimated): %s wei (%s %s)\n", gasCost.String(), ethereum.WeiToEth(gasCost), network.Currency)
+		fmt.Printf("Total cost: %s wei (%s %s)\n", totalCost.String(), ethereum.WeiToEth(totalCost), network.Currency)
 	}
 }
 
-// Check if an address is valid
-func isValidAddress(address string) bool {
-	if len(address) != 42 {
-		return false
+// resolveAccessList loads an access list from a file, or generates it with
+// eth_createAccessList when arg is "auto"
+func resolveAccessList(ctx context.Context, client *ethereum.Client, from common.Address, toAddress string, amountWei *big.Int, data []byte, arg string) ethereum.AccessList {
+	if arg != "auto" {
+		accessList, err := ethereum.LoadAccessList(arg)
+		if err != nil {
+			fmt.Printf("Error loading access list: %v\n", err)
+			os.Exit(1)
+		}
+		return accessList
 	}
 
-	if address[:2] != "0x" {
-		return false
+	result, err := client.CreateAccessList(ctx, from, toAddress, amountWei, data)
+	if err != nil {
+		fmt.Printf("Error generating access list: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Check if address is valid hex
-	_, err := ethereum.HexDecode(address)
-	return err == nil
+	// Never send a nil list here, so the transaction is still typed
+	if result.AccessList == nil {
+		return ethereum.AccessList{}
+	}
+	return result.AccessList
+}
+
+// displayTxResult prints the outcome of waiting for a transaction on network
+func displayTxResult(network *ethereum.Network, result *ethereum.TxResult, waitErr error) {
+	switch result.Status {
+	case ethereum.TxStatusSuccess, ethereum.TxStatusReverted:
+		receipt := result.Receipt
+		if result.Status == ethereum.TxStatusSuccess {
+			fmt.Println("\n=== TRANSACTION CONFIRMED ===")
+		} else {
+			fmt.Println("\n❌ TRANSACTION REVERTED")
+		}
+		fmt.Printf("Status:              %s\n", result.Status)
+		fmt.Printf("Block number:        %d\n", receipt.BlockNumber)
+		fmt.Printf("Confirmations:       %d\n", result.Confirmations)
+		fmt.Printf("Gas used:            %d\n", receipt.GasUsed)
+		if receipt.EffectiveGasPrice != nil {
+			fmt.Printf("Effective gas price: %s wei (%s gwei)\n", receipt.EffectiveGasPrice.String(), formatGwei(receipt.EffectiveGasPrice))
+		}
+		fmt.Printf("Fee paid:            %s wei (%s %s)\n", receipt.Fee().String(), ethereum.WeiToEth(receipt.Fee()), network.Currency)
+		if waitErr != nil {
+			fmt.Printf("Stopped waiting for more confirmations: %v\n", waitErr)
+		}
+	case ethereum.TxStatusPending:
+		fmt.Println("\n⏳ TRANSACTION STILL PENDING")
+		if waitErr != nil {
+			fmt.Printf("Stopped waiting: %v\n", waitErr)
+		}
+		fmt.Println("The transaction has not been mined yet; check the block explorer later.")
+	case ethereum.TxStatusReplaced:
+		fmt.Println("\n⚠️  TRANSACTION REPLACED")
+		fmt.Println("Another transaction with the same nonce was mined instead.")
+	case ethereum.TxStatusDropped:
+		fmt.Println("\n❌ TRANSACTION DROPPED")
+		fmt.Println("The node no longer knows this transaction and its nonce is unused.")
+	case ethereum.TxStatusNotFound:
+		fmt.Println("\n❓ TRANSACTION NOT FOUND")
+		fmt.Println("The node has not returned this transaction; it may still be propagating or may have been dropped.")
+		fmt.Println("Check the block explorer later before sending it again.")
+	}
 }
 
-// Format a wei value to gwei
+// Format a wei value to gwei exactly
 func formatGwei(wei *big.Int) string {
-	gwei := new(big.Float).SetInt(wei)
-	gwei.Quo(gwei, new(big.Float).SetFloat64(1e9))
-	return fmt.Sprintf("%.6f", gwei)
+	return units.FormatGwei(wei)
 }
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// NewSignCmd creates the off-chain signing command group
+func NewSignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Sign messages and typed data off-chain",
+		Long: `Sign data that is not a transaction, to prove ownership of an address to an
+exchange or service without sending anything on-chain. Check a signature with
+"verify".`,
+	}
+
+	cmd.AddCommand(newSignMessageCmd())
+	cmd.AddCommand(newSignTypedCmd())
+
+	return cmd
+}
+
+// newSignMessageCmd creates the command that signs an EIP-191 personal message
+func newSignMessageCmd() *cobra.Command {
+	var signer signerFlags
+	var isHex bool
+
+	cmd := &cobra.Command{
+		Use:   "message <privateKey> <message>",
+		Short: "Sign a message (EIP-191 personal_sign)",
+		Long: `Sign a message the way personal_sign and eth_sign in wallets do: the keccak256
+hash of "\x19Ethereum Signed Message:\n", the message length in bytes and the
+message. The 65-byte signature is printed as r || s || v with v = 27 or 28.
+The message is signed as text; with --hex it is 0x-prefixed hex bytes.
+This command makes no network requests. With --env or --keystore the private
+key argument is omitted.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			keyPair, args := signer.keyPairFromArgs(args, 1, "message")
+			message := parseMessageArg(args[0], isHex)
+
+			signature, err := ethereum.SignMessage(keyPair.PrivateKey, message)
+			if err != nil {
+				fmt.Printf("Error signing message: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== SIGNED MESSAGE ===")
+			fmt.Printf("Address:   %s\n", keyPair.Address.Hex())
+			displayMessage(message)
+			fmt.Printf("Hash:      0x%x\n", ethereum.HashMessage(message))
+			fmt.Printf("Signature: 0x%x\n", signature)
+		},
+	}
+
+	signer.register(cmd)
+	cmd.Flags().BoolVar(&isHex, "hex", false, "Treat the message as 0x-prefixed hex bytes instead of text")
+
+	return cmd
+}
+
+// NewVerifyCmd creates the command that checks who signed a message or typed data
+func NewVerifyCmd() *cobra.Command {
+	var isHex bool
+	var typed bool
+
+	cmd := &cobra.Command{
+		Use:   "verify <address> <message|typedData.json> <signature>",
+		Short: "Verify a signed message (EIP-191) or typed data (EIP-712)",
+		Long: `Recover the address that signed a personal_sign message, or with --typed an
+EIP-712 typed data file, from its 65-byte signature (r || s || v, v = 27/28 or
+0/1) and compare it with the expected address. Exits with status 1 when the
+signature is invalid or was made by another address.
+The message is text; with --hex it is 0x-prefixed hex bytes.
+This command makes no network requests.`,
+		Args: cobra.ExactArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			if typed && isHex {
+				fmt.Println("Error: --typed and --hex cannot be used together")
+				os.Exit(1)
+			}
+			expected := parseAddressArg("expected", args[0])
+			signature := parseSignatureArg(args[2])
+
+			var signer common.Address
+			var err error
+			if typed {
+				td := loadTypedData(args[1])
+				displayTypedData(td)
+				fmt.Println("\n=== VERIFY TYPED DATA ===")
+				fmt.Printf("Expected:  %s\n", expected.Hex())
+				signer, err = ethereum.RecoverTypedDataSigner(td, signature)
+			} else {
+				message := parseMessageArg(args[1], isHex)
+				fmt.Println("\n=== VERIFY MESSAGE ===")
+				fmt.Printf("Expected:  %s\n", expected.Hex())
+				displayMessage(message)
+				fmt.Printf("Hash:      0x%x\n", ethereum.HashMessage(message))
+				signer, err = ethereum.RecoverMessageSigner(message, signature)
+			}
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			reportSigner(expected, signer)
+		},
+	}
+
+	cmd.Flags().BoolVar(&isHex, "hex", false, "Treat the message as 0x-prefixed hex bytes instead of text")
+	cmd.Flags().BoolVar(&typed, "typed", false, "Verify an EIP-712 typed data JSON file instead of a message")
+
+	return cmd
+}
+
+// parseMessageArg returns the bytes of a message argument, exiting on error
+func parseMessageArg(s string, isHex bool) []byte {
+	if !isHex {
+		return []byte(s)
+	}
+	message, err := ethereum.HexDecode(s)
+	if err != nil {
+		fmt.Printf("Error: Invalid hex message: %v\n", err)
+		os.Exit(1)
+	}
+	return message
+}
+
+// parseSignatureArg parses a 0x-prefixed 65-byte signature, exiting on error
+func parseSignatureArg(s string) []byte {
+	signature, err := ethereum.HexDecode(s)
+	if err != nil {
+		fmt.Printf("Error: Invalid signature: %v\n", err)
+		os.Exit(1)
+	}
+	if len(signature) != 65 {
+		fmt.Printf("Error: Invalid signature: expected 65 bytes, got %d\n", len(signature))
+		os.Exit(1)
+	}
+	return signature
+}
+
+// displayMessage shows a message as quoted text when it is printable, and as hex otherwise
+func displayMessage(message []byte) {
+	if isPrintable(message) {
+		fmt.Printf("Message:   %s (%d bytes)\n", strconv.Quote(string(message)), len(message))
+	} else {
+		fmt.Printf("Message:   0x%x (%d bytes)\n", message, len(message))
+	}
+}
+
+// isPrintable reports whether b is UTF-8 text without control characters other
+// than line breaks and tabs
+func isPrintable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// reportSigner prints the address recovered from a signature and whether it is
+// the expected one, exiting with status 1 when it is not
+func reportSigner(expected, signer common.Address) {
+	fmt.Printf("Signer:    %s (recovered from the signature)\n", signer.Hex())
+	if signer != expected {
+		fmt.Println("\n❌ Signature is NOT from the expected address")
+		os.Exit(1)
+	}
+	fmt.Println("\n✅ Signature is valid")
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// signerFlags select the key that signs a transaction: a private key argument,
+// TEST_PRIVATE_KEY, the HD wallet in HD_MNEMONIC, or an encrypted keystore
+type signerFlags struct {
+	useEnvVar    bool
+	useMnemonic  bool
+	keystorePath string
+	account      string
+}
+
+// register adds the signer flags to a command
+func (f *signerFlags) register(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&f.useEnvVar, "env", "e", false, "Use private key from TEST_PRIVATE_KEY environment variable")
+	cmd.Flags().BoolVarP(&f.useMnemonic, "hd", "", false, "Use HD wallet from HD_MNEMONIC environment variable")
+	cmd.Flags().StringVarP(&f.keystorePath, "keystore", "k", "", "Use encrypted keystore file or directory (prompts for passphrase)")
+	cmd.Flags().StringVarP(&f.account, "account", "a", "", "Account address to select from the keystore directory")
+}
+
+// keyFromArgs reports whether the private key is expected as the first argument
+func (f *signerFlags) keyFromArgs() bool {
+	return !f.useEnvVar && f.keystorePath == ""
+}
+
+// keyPairFromArgs loads the signing key, taking it from the first argument unless
+// --env or --keystore is set, and returns the remaining arguments. required is
+// the number of other arguments and usage names them in the error message; it
+// may be 0 for commands whose only argument is the key.
+func (f *signerFlags) keyPairFromArgs(args []string, required int, usage string) (*ethereum.KeyPair, []string) {
+	var privateKeyHex string
+	if f.keyFromArgs() {
+		if len(args) != required+1 {
+			switch required {
+			case 0:
+				fmt.Println("Error: privateKey is required")
+			case 1:
+				fmt.Printf("Error: privateKey and %s are required\n", usage)
+			default:
+				fmt.Printf("Error: privateKey, %s are required\n", usage)
+			}
+			os.Exit(1)
+		}
+		privateKeyHex = args[0]
+		args = args[1:]
+	} else if len(args) != required {
+		switch required {
+		case 0:
+			fmt.Println("Error: no arguments are expected with --env or --keystore")
+		case 1:
+			fmt.Printf("Error: %s is required\n", usage)
+		default:
+			fmt.Printf("Error: %s are required\n", usage)
+		}
+		os.Exit(1)
+	}
+	return f.keyPair(privateKeyHex), args
+}
+
+// keyPair loads the signing key, exiting on error. privateKeyHex is only used
+// when the key is passed as an argument.
+func (f *signerFlags) keyPair(privateKeyHex string) *ethereum.KeyPair {
+	envLoaded := ethereum.LoadEnvVariables()
+
+	if f.keystorePath != "" {
+		// Decrypt key from keystore, prompting for the passphrase
+		keyPair, err := loadKeystoreKeyPair(f.keystorePath, f.account)
+		if err != nil {
+			fmt.Printf("Error loading keystore: %v\n", err)
+			os.Exit(1)
+		}
+		return keyPair
+	}
+
+	if f.useEnvVar && f.useMnemonic {
+		// Use mnemonic from environment
+		mnemonic := os.Getenv("HD_MNEMONIC")
+		hdPath := os.Getenv("HD_PATH")
+
+		if mnemonic == "" {
+			fmt.Println("Error: HD_MNEMONIC not set in environment variables")
+			os.Exit(1)
+		}
+
+		if hdPath == "" {
+			hdPath = ethereum.DefaultHDPath
+		}
+
+		// Import HD wallet
+		return importHDWallet(mnemonic, hdPath, envUsesPassphrase(), false).KeyPair
+	}
+
+	if f.useEnvVar {
+		// Use private key from environment
+		privateKeyHex = os.Getenv("TEST_PRIVATE_KEY")
+		if privateKeyHex == "" {
+			if !envLoaded {
+				fmt.Println("Error: No .env file found and TEST_PRIVATE_KEY environment variable not set")
+			} else {
+				fmt.Println("Error: TEST_PRIVATE_KEY not set in .env or environment variables")
+			}
+			os.Exit(1)
+		}
+	}
+
+	// Import key
+	keyPair, err := ethereum.ImportPrivateKey(privateKeyHex)
+	if err != nil {
+		fmt.Printf("Error importing private key: %v\n", err)
+		os.Exit(1)
+	}
+	return keyPair
+}
+
+// envUsesPassphrase reports whether HD_USE_PASSPHRASE says the HD wallet in
+// HD_MNEMONIC has a BIP-39 passphrase, which is never stored in .env
+func envUsesPassphrase() bool {
+	usePassphrase, _ := strconv.ParseBool(os.Getenv("HD_USE_PASSPHRASE"))
+	return usePassphrase
+}
+
+// promptBIP39Passphrase reads a BIP-39 passphrase without echoing it. When confirm
+// is set it must be entered twice, since a mistyped passphrase silently gives a
+// different wallet.
+func promptBIP39Passphrase(confirm bool) (string, error) {
+	passphrase, err := promptPassphrase("BIP-39 passphrase: ", confirm)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("passphrase must not be empty")
+	}
+	return passphrase, nil
+}
+
+// importHDWallet imports an HD wallet, prompting for its BIP-39 passphrase when
+// usePassphrase is set, and exits on error
+func importHDWallet(mnemonic, hdPath string, usePassphrase, confirm bool) *ethereum.HDKeyPair {
+	var opts []ethereum.HDOption
+	if usePassphrase {
+		passphrase, err := promptBIP39Passphrase(confirm)
+		if err != nil {
+			fmt.Printf("Error reading passphrase: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, ethereum.WithPassphrase(passphrase))
+	}
+
+	hdKeyPair, err := ethereum.ImportHDWallet(mnemonic, hdPath, opts...)
+	if err != nil {
+		fmt.Printf("Error importing HD wallet: %v\n", err)
+		os.Exit(1)
+	}
+	return hdKeyPair
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+	"github.com/metana-bootcamp/ethwallet/internal/siwe"
+)
+
+// NewSIWECmd creates the Sign-In with Ethereum command group
+func NewSIWECmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "siwe",
+		Short: "Sign in with Ethereum (EIP-4361)",
+		Long: `Build and sign Sign-In with Ethereum (EIP-4361) login messages, and verify a
+signed message: its fields, its validity window and the address that signed it.`,
+	}
+
+	cmd.AddCommand(newSIWESignCmd())
+	cmd.AddCommand(newSIWEVerifyCmd())
+
+	return cmd
+}
+
+func newSIWESignCmd() *cobra.Command {
+	var signer signerFlags
+	var domain string
+	var uri string
+	var statement string
+	var nonce string
+	var expires time.Duration
+	var notBefore string
+	var requestID string
+	var resources []string
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "sign <privateKey>",
+		Short: "Build and sign a Sign-In with Ethereum message",
+		Long: `Build an EIP-4361 message for --domain with the chain ID of the selected
+network, a random nonce unless --nonce is given and the current time as
+issued-at, then sign it as an EIP-191 personal message. The message is
+written to a file for the service (or "siwe verify") to check.
+The URI defaults to https://<domain>. This command makes no network requests.
+With --env or --keystore the private key argument is omitted.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			keyPair, _ := signer.keyPairFromArgs(args, 0, "")
+
+			if nonce == "" {
+				var err error
+				if nonce, err = siwe.GenerateNonce(); err != nil {
+					fmt.Printf("Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			now := time.Now().UTC()
+			msg := &siwe.Message{
+				Domain:    domain,
+				Statement: statement,
+				URI:       uri,
+				Version:   siwe.Version,
+				ChainID:   selectedNetwork().ChainID,
+				Nonce:     nonce,
+				IssuedAt:  now.Format(time.RFC3339),
+				NotBefore: notBefore,
+				RequestID: requestID,
+				Resources: resources,
+			}
+			if scheme, host, ok := strings.Cut(domain, "://"); ok {
+				msg.Scheme, msg.Domain = scheme, host
+			}
+			if msg.URI == "" {
+				msg.URI = "https://" + msg.Domain
+			}
+			if expires > 0 {
+				msg.ExpirationTime = now.Add(expires).Format(time.RFC3339)
+			}
+
+			msg.Address = keyPair.Address
+			if err := msg.Validate(); err != nil {
+				fmt.Printf("Error: Invalid sign-in message: %v\n", err)
+				os.Exit(1)
+			}
+
+			text := msg.String()
+			signature, err := ethereum.SignMessage(keyPair.PrivateKey, []byte(text))
+			if err != nil {
+				fmt.Printf("Error signing message: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== SIGN-IN WITH ETHEREUM ===")
+			fmt.Println(text)
+			fmt.Println("\n=== SIGNATURE ===")
+			fmt.Printf("Address:   %s\n", keyPair.Address.Hex())
+			fmt.Printf("Signature: 0x%x\n", signature)
+
+			if err := os.WriteFile(outPath, []byte(text+"\n"), 0644); err != nil {
+				fmt.Printf("Error saving message: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("\nMessage written to %s\n", outPath)
+		},
+	}
+
+	signer.register(cmd)
+	cmd.Flags().StringVar(&domain, "domain", "", "Domain requesting the sign-in, e.g. dashboard.example.com (required)")
+	cmd.Flags().StringVar(&uri, "uri", "", "URI the sign-in is for (default https://<domain>)")
+	cmd.Flags().StringVar(&statement, "statement", "", "Single-line statement shown to the user")
+	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce issued by the service, at least 8 alphanumeric characters (default random)")
+	cmd.Flags().DurationVar(&expires, "expires", 0, "Make the message expire after this duration, e.g. 10m")
+	cmd.Flags().StringVar(&notBefore, "not-before", "", "RFC 3339 time before which the message is not valid")
+	cmd.Flags().StringVar(&requestID, "request-id", "", "Request ID chosen by the service")
+	cmd.Flags().StringArrayVar(&resources, "resource", nil, "Resource URI to include (repeatable)")
+	cmd.Flags().StringVarP(&outPath, "out", "o", "siwe-message.txt", "File to write the signed message to")
+	cmd.MarkFlagRequired("domain")
+
+	return cmd
+}
+
+func newSIWEVerifyCmd() *cobra.Command {
+	var domain string
+	var nonce string
+	var chainID uint64
+
+	cmd := &cobra.Command{
+		Use:   "verify <messageFile|-> <signature>",
+		Short: "Verify a signed Sign-In with Ethereum message",
+		Long: `Parse a Sign-In with Ethereum message from a file, or standard input with "-",
+check every field against EIP-4361 and that the current time is within its
+expiration and not-before window, then recover the signer from the signature
+and compare it with the address in the message. --domain, --nonce and
+--chain-id also require those fields to have the values the service expects.
+Exits with status 1 when any check fails. Smart contract wallets (EIP-1271)
+are not supported. This command makes no network requests.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			text := readSIWEMessage(args[0])
+			signature := parseSignatureArg(args[1])
+
+			msg, err := siwe.Parse(text)
+			if err != nil {
+				fmt.Printf("Error: Invalid sign-in message: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== SIGN-IN WITH ETHEREUM ===")
+			fmt.Printf("Domain:     %s\n", msg.Domain)
+			fmt.Printf("Address:    %s\n", msg.Address.Hex())
+			if msg.Statement != "" {
+				fmt.Printf("Statement:  %s\n", msg.Statement)
+			}
+			fmt.Printf("URI:        %s\n", msg.URI)
+			if network := networkForChain(msg.ChainID); network != nil {
+				fmt.Printf("Chain ID:   %d (%s)\n", msg.ChainID, network.Name)
+			} else {
+				fmt.Printf("Chain ID:   %d\n", msg.ChainID)
+			}
+			fmt.Printf("Nonce:      %s\n", msg.Nonce)
+			fmt.Printf("Issued at:  %s\n", msg.IssuedAt)
+			if msg.ExpirationTime != "" {
+				fmt.Printf("Expires:    %s\n", msg.ExpirationTime)
+			}
+			if msg.NotBefore != "" {
+				fmt.Printf("Not before: %s\n", msg.NotBefore)
+			}
+			if msg.RequestID != "" {
+				fmt.Printf("Request ID: %s\n", msg.RequestID)
+			}
+			for _, resource := range msg.Resources {
+				fmt.Printf("Resource:   %s\n", resource)
+			}
+
+			var failures []string
+			if domain != "" && msg.Domain != domain {
+				failures = append(failures, fmt.Sprintf("domain is %s, expected %s", msg.Domain, domain))
+			}
+			if nonce != "" && msg.Nonce != nonce {
+				failures = append(failures, fmt.Sprintf("nonce is %s, expected %s", msg.Nonce, nonce))
+			}
+			if chainID != 0 && msg.ChainID != chainID {
+				failures = append(failures, fmt.Sprintf("chain ID is %d, expected %d", msg.ChainID, chainID))
+			}
+			if err := msg.CheckTime(time.Now()); err != nil {
+				failures = append(failures, err.Error())
+			}
+
+			signer, err := ethereum.RecoverMessageSigner([]byte(text), signature)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Signer:     %s (recovered from the signature)\n", signer.Hex())
+			if signer != msg.Address {
+				failures = append(failures, "signature is not from the address in the message")
+			}
+
+			if len(failures) > 0 {
+				fmt.Println("\n❌ Sign-in is NOT valid:")
+				for _, failure := range failures {
+					fmt.Printf("  - %s\n", failure)
+				}
+				os.Exit(1)
+			}
+			fmt.Println("\n✅ Sign-in is valid")
+		},
+	}
+
+	cmd.Flags().StringVar(&domain, "domain", "", "Require this domain")
+	cmd.Flags().StringVar(&nonce, "nonce", "", "Require this nonce")
+	cmd.Flags().Uint64Var(&chainID, "chain-id", 0, "Require this chain ID")
+
+	return cmd
+}
+
+// readSIWEMessage reads a message from a file, or from standard input for "-",
+// dropping the line break that ends the file
+func readSIWEMessage(path string) string {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		fmt.Printf("Error reading message: %v\n", err)
+		os.Exit(1)
+	}
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	return strings.TrimSuffix(text, "\n")
+}
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// NewTokenCmd creates the ERC-20 token command group
+func NewTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Read and transfer ERC-20 tokens",
+		Long: `Read ERC-20 token metadata, balances and allowances, and send transfer,
+approve and transferFrom transactions. Sign gasless EIP-2612 and Permit2
+approvals for a relayer or contract to submit. Amounts are in token units
+(e.g. 1.5) using the token's decimals, or in base units with --raw.`,
+	}
+
+	cmd.AddCommand(newTokenInfoCmd())
+	cmd.AddCommand(newTokenBalanceCmd())
+	cmd.AddCommand(newTokenAllowanceCmd())
+	cmd.AddCommand(newTokenTransferCmd())
+	cmd.AddCommand(newTokenApproveCmd())
+	cmd.AddCommand(newTokenTransferFromCmd())
+	cmd.AddCommand(newTokenPermitCmd())
+	cmd.AddCommand(newTokenPermit2Cmd())
+
+	return cmd
+}
+
+// tokenTxFlags are the flags of the token commands that send transactions
+type tokenTxFlags struct {
+	txFlags
+	raw bool
+}
+
+// register adds the transaction flags to a command
+func (f *tokenTxFlags) register(cmd *cobra.Command) {
+	f.txFlags.register(cmd)
+	cmd.Flags().BoolVar(&f.raw, "raw", false, "Amounts are in base units instead of token units")
+}
+
+// parseAmount parses a token amount in token or base units, exiting on error
+func (f *tokenTxFlags) parseAmount(s string, info *ethereum.TokenInfo) *big.Int {
+	return parseTokenAmountArg(s, f.raw, info)
+}
+
+// parseTokenAmountArg parses a token amount in token units, or in base units
+// when raw is set, exiting on error
+func parseTokenAmountArg(s string, raw bool, info *ethereum.TokenInfo) *big.Int {
+	if raw {
+		amount, ok := new(big.Int).SetString(s, 10)
+		if !ok || amount.Sign() < 0 {
+			fmt.Println("Error: Invalid amount format. Please provide a decimal value in base units.")
+			os.Exit(1)
+		}
+		return amount
+	}
+
+	amount, err := ethereum.ParseTokenAmount(s, info.Decimals)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return amount
+}
+
+// loadToken connects to the network and reads the token metadata, exiting on error
+func loadToken(ctx context.Context, address string) (*ethereum.Network, *ethereum.Client, *ethereum.Token, *ethereum.TokenInfo) {
+	tokenAddress := parseAddressArg("token", address)
+	network, client := connectNetwork(ctx)
+
+	token := ethereum.NewToken(client, tokenAddress)
+	info, err := token.Info(ctx)
+	if err != nil {
+		fmt.Printf("Error reading token: %v\n", err)
+		os.Exit(1)
+	}
+	return network, client, token, info
+}
+
+// formatToken formats an amount with the token's decimals and symbol
+func formatToken(amount *big.Int, info *ethereum.TokenInfo) string {
+	if amount.Cmp(ethereum.MaxUint256) == 0 {
+		return "unlimited"
+	}
+	formatted := ethereum.FormatTokenAmount(amount, info.Decimals)
+	if info.Symbol != "" {
+		formatted += " " + info.Symbol
+	}
+	return formatted
+}
+
+func newTokenInfoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "info <token>",
+		Short: "Show token name, symbol, decimals and total supply",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := context.Background()
+			network, _, _, info := loadToken(ctx, args[0])
+
+			fmt.Println("\n=== TOKEN INFO ===")
+			fmt.Printf("Network:      %s (chain ID %d)\n", network.Name, network.ChainID)
+			fmt.Printf("Address:      %s\n", info.Address.Hex())
+			fmt.Printf("Name:         %s\n", info.Name)
+			fmt.Printf("Symbol:       %s\n", info.Symbol)
+			fmt.Printf("Decimals:     %d\n", info.Decimals)
+			if info.TotalSupply != nil {
+				fmt.Printf("Total supply: %s\n", formatToken(info.TotalSupply, info))
+			}
+			if network.Explorer != "" {
+				fmt.Printf("View on explorer: %s/token/%s\n", network.Explorer, info.Address.Hex())
+			}
+		},
+	}
+}
+
+func newTokenBalanceCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "balance <token> <address>",
+		Short: "Show the token balance of an address",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			owner := parseAddressArg("owner", args[1])
+
+			ctx := context.Background()
+			_, _, token, info := loadToken(ctx, args[0])
+
+			balance, err := token.BalanceOf(ctx, owner)
+			if err != nil {
+				fmt.Printf("Error getting balance: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== TOKEN BALANCE ===")
+			fmt.Printf("Token:   %s (%s)\n", info.Symbol, info.Address.Hex())
+			fmt.Printf("Address: %s\n", owner.Hex())
+			fmt.Printf("Balance: %s\n", formatToken(balance, info))
+			fmt.Printf("Raw:     %s\n", balance.String())
+		},
+	}
+}
+
+func newTokenAllowanceCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "allowance <token> <owner> <spender>",
+		Short: "Show how much a spender may transfer from an owner",
+		Args:  cobra.ExactArgs(3),
+		Run: func(cmd *cobra.Command, args []string) {
+			owner := parseAddressArg("owner", args[1])
+			spender := parseAddressArg("spender", args[2])
+
+			ctx := context.Background()
+			_, _, token, info := loadToken(ctx, args[0])
+
+			allowance, err := token.Allowance(ctx, owner, spender)
+			if err != nil {
+				fmt.Printf("Error getting allowance: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== TOKEN ALLOWANCE ===")
+			fmt.Printf("Token:     %s (%s)\n", info.Symbol, info.Address.Hex())
+			fmt.Printf("Owner:     %s\n", owner.Hex())
+			fmt.Printf("Spender:   %s\n", spender.Hex())
+			fmt.Printf("Allowance: %s\n", formatToken(allowance, info))
+			fmt.Printf("Raw:       %s\n", allowance.String())
+		},
+	}
+}
+
+func newTokenTransferCmd() *cobra.Command {
+	var flags tokenTxFlags
+
+	cmd := &cobra.Command{
+		Use:   "transfer <privateKey> <token> <to> <amount>",
+		Short: "Transfer tokens",
+		Long: `Transfer tokens from the signing account. With --env or --keystore the
+private key argument is omitted.`,
+		Args: cobra.RangeArgs(3, 4),
+		Run: func(cmd *cobra.Command, args []string) {
+			keyPair, args := flags.keyPair(args, 3, "token, to and amount")
+			to := parseAddressArg("recipient", args[1])
+
+			ctx := context.Background()
+			network, client, token, info := loadToken(ctx, args[0])
+			amount := flags.parseAmount(args[2], info)
+
+			// Refuse transfers that would revert for lack of balance
+			balance, err := token.BalanceOf(ctx, keyPair.Address)
+			if err != nil {
+				fmt.Printf("Error getting balance: %v\n", err)
+				os.Exit(1)
+			}
+			if balance.Cmp(amount) < 0 {
+				fmt.Printf("Error: insufficient token balance: have %s, need %s\n", formatToken(balance, info), formatToken(amount, info))
+				os.Exit(1)
+			}
+
+			data, err := ethereum.TransferData(to, amount)
+			if err != nil {
+				fmt.Printf("Error encoding transfer: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== TOKEN TRANSFER ===")
+			fmt.Printf("Token:   %s (%s)\n", info.Symbol, info.Address.Hex())
+			fmt.Printf("From:    %s\n", keyPair.Address.Hex())
+			fmt.Printf("To:      %s\n", to.Hex())
+			fmt.Printf("Amount:  %s (%s base units)\n", formatToken(amount, info), amount.String())
+			fmt.Printf("Balance: %s\n", formatToken(balance, info))
+
+			sendContractTx(ctx, &flags.txFlags, network, client, keyPair, info.Address, data, nil)
+		},
+	}
+
+	flags.register(cmd)
+	return cmd
+}
+
+func newTokenApproveCmd() *cobra.Command {
+	var flags tokenTxFlags
+
+	cmd := &cobra.Command{
+		Use:   "approve <privateKey> <token> <spender> <amount|max>",
+		Short: "Approve a spender to transfer tokens",
+		Long: `Set the allowance of a spender over the signing account's tokens.
+"max" approves an unlimited amount. With --env or --keystore the private key
+argument is omitted.`,
+		Args: cobra.RangeArgs(3, 4),
+		Run: func(cmd *cobra.Command, args []string) {
+			keyPair, args := flags.keyPair(args, 3, "token, spender and amount")
+			spender := parseAddressArg("spender", args[1])
+
+			ctx := context.Background()
+			network, client, token, info := loadToken(ctx, args[0])
+
+			amount := ethereum.MaxUint256
+			if args[2] != "max" {
+				amount = flags.parseAmount(args[2], info)
+			}
+
+			current, err := token.Allowance(ctx, keyPair.Address, spender)
+			if err != nil {
+				fmt.Printf("Error getting allowance: %v\n", err)
+				os.Exit(1)
+			}
+
+			data, err := ethereum.ApproveData(spender, amount)
+			if err != nil {
+				fmt.Printf("Error encoding approve: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== TOKEN APPROVAL ===")
+			fmt.Printf("Token:     %s (%s)\n", info.Symbol, info.Address.Hex())
+			fmt.Printf("Owner:     %s\n", keyPair.Address.Hex())
+			fmt.Printf("Spender:   %s\n", spender.Hex())
+			fmt.Printf("Allowance: %s -> %s\n", formatToken(current, info), formatToken(amount, info))
+			if amount.Cmp(ethereum.MaxUint256) == 0 {
+				fmt.Println("Warning: the spender may transfer all of your tokens of this kind")
+			}
+			if current.Sign() > 0 && amount.Sign() > 0 {
+				// Changing a non-zero allowance can be front-run, and tokens such as USDT reject it
+				fmt.Println("Note: the current allowance is not zero; some tokens require approving 0 first")
+			}
+
+			sendContractTx(ctx, &flags.txFlags, network, client, keyPair, info.Address, data, nil)
+		},
+	}
+
+	flags.register(cmd)
+	return cmd
+}
+
+func newTokenTransferFromCmd() *cobra.Command {
+	var flags tokenTxFlags
+
+	cmd := &cobra.Command{
+		Use:   "transfer-from <privateKey> <token> <from> <to> <amount>",
+		Short: "Transfer tokens from another account using an allowance",
+		Long: `Transfer tokens from an account that approved the signing account as a
+spender. With --env or --keystore the private key argument is omitted.`,
+		Args: cobra.RangeArgs(4, 5),
+		Run: func(cmd *cobra.Command, args []string) {
+			keyPair, args := flags.keyPair(args, 4, "token, from, to and amount")
+			from := parseAddressArg("owner", args[1])
+			to := parseAddressArg("recipient", args[2])
+
+			ctx := context.Background()
+			network, client, token, info := loadToken(ctx, args[0])
+			amount := flags.parseAmount(args[3], info)
+
+			// Both the allowance and the owner's balance must cover the amount
+			allowance, err := token.Allowance(ctx, from, keyPair.Address)
+			if err != nil {
+				fmt.Printf("Error getting allowance: %v\n", err)
+				os.Exit(1)
+			}
+			if allowance.Cmp(amount) < 0 {
+				fmt.Printf("Error: insufficient allowance: have %s, need %s\n", formatToken(allowance, info), formatToken(amount, info))
+				os.Exit(1)
+			}
+			balance, err := token.BalanceOf(ctx, from)
+			if err != nil {
+				fmt.Printf("Error getting balance: %v\n", err)
+				os.Exit(1)
+			}
+			if balance.Cmp(amount) < 0 {
+				fmt.Printf("Error: insufficient token balance: have %s, need %s\n", formatToken(balance, info), formatToken(amount, info))
+				os.Exit(1)
+			}
+
+			data, err := ethereum.TransferFromData(from, to, amount)
+			if err != nil {
+				fmt.Printf("Error encoding transferFrom: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== TOKEN TRANSFER FROM ===")
+			fmt.Printf("Token:     %s (%s)\n", info.Symbol, info.Address.Hex())
+			fmt.Printf("Spender:   %s\n", keyPair.Address.Hex())
+			fmt.Printf("From:      %s\n", from.Hex())
+			fmt.Printf("To:        %s\n", to.Hex())
+			fmt.Printf("Amount:    %s (%s base units)\n", formatToken(amount, info), amount.String())
+			fmt.Printf("Allowance: %s\n", formatToken(allowance, info))
+
+			sendContractTx(ctx, &flags.txFlags, network, client, keyPair, info.Address, data, nil)
+		},
+	}
+
+	flags.register(cmd)
+	return cmd
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// NewTxCmd creates the transaction management command group
+func NewTxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx",
+		Short: "Manage sent transactions",
+		Long: `Speed up or cancel a pending transaction by replacing it with a transaction
+that has the same nonce and higher fees, and inspect the nonce of the next one.
+Build, sign and broadcast a transaction in separate steps to keep the signing
+key on a machine without network access, and decode a signed transaction
+before it is broadcast.`,
+	}
+
+	cmd.AddCommand(newTxReplaceCmd(false))
+	cmd.AddCommand(newTxReplaceCmd(true))
+	cmd.AddCommand(newTxNonceCmd())
+	cmd.AddCommand(newTxBuildCmd())
+	cmd.AddCommand(newTxSignCmd())
+	cmd.AddCommand(newTxBroadcastCmd())
+	cmd.AddCommand(newTxDecodeCmd())
+
+	return cmd
+}
+
+// newTxReplaceCmd creates the speedup command, or the cancel command when cancel is set
+func newTxReplaceCmd(cancel bool) *cobra.Command {
+	var signer signerFlags
+	var fees feeFlags
+	var confirmations uint64
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "speedup <privateKey> <txHash>",
+		Short: "Speed up a pending transaction",
+		Long: fmt.Sprintf(`Re-send a pending transaction with the same nonce, recipient, value and data
+and higher fees.
+%s
+With --env or --keystore the private key argument is omitted.`, replaceFeesHelp),
+		Args: cobra.RangeArgs(1, 2),
+	}
+	if cancel {
+		cmd.Use = "cancel <privateKey> <txHash>"
+		cmd.Short = "Cancel a pending transaction"
+		cmd.Long = fmt.Sprintf(`Replace a pending transaction with a 0-value transfer to the sender itself
+using the same nonce, so the original can no longer be mined.
+%s
+With --env or --keystore the private key argument is omitted.`, replaceFeesHelp)
+	}
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		keyPair, args := signer.keyPairFromArgs(args, 1, "txHash")
+
+		txHash := args[0]
+		if hash, err := ethereum.HexDecode(txHash); err != nil || len(hash) != common.HashLength {
+			fmt.Printf("Error: Invalid transaction hash %s\n", txHash)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		network, client := connectNetwork(ctx)
+		priorityFee, sendOpts := fees.options(!network.EIP1559)
+
+		fmt.Println("\n=== SENDING REPLACEMENT ===")
+		var replacement *ethereum.Replacement
+		var err error
+		if network.EIP1559 {
+			replacement, err = client.ReplaceTransaction(ctx, keyPair, txHash, cancel, priorityFee, sendOpts...)
+		} else {
+			// Networks without EIP-1559 only accept a higher gas price
+			replacement, err = client.ReplaceLegacyTransaction(ctx, keyPair, txHash, cancel, sendOpts...)
+		}
+		if err != nil {
+			fmt.Printf("Error replacing transaction: %v\n", err)
+			os.Exit(1)
+		}
+		displayReplacement(network, replacement)
+
+		// The original and the replacement compete for the same nonce
+		fmt.Printf("\nWaiting for either transaction with %d confirmation(s) (timeout %s)...\n", confirmations, timeout)
+		waitOpts := ethereum.DefaultWaitOptions()
+		waitOpts.Confirmations = confirmations
+		waitOpts.Timeout = timeout
+
+		hashes := []string{replacement.Hash, replacement.Original.Hash}
+		winner, result, err := client.WaitForReplacement(ctx, keyPair.Address, replacement.Tx.Nonce, hashes, waitOpts)
+		switch winner {
+		case replacement.Hash:
+			fmt.Printf("\nThe replacement %s was mined\n", winner)
+		case replacement.Original.Hash:
+			fmt.Printf("\nThe original %s was mined before the replacement\n", winner)
+		}
+		displayTxResult(network, result, err)
+
+		if winner != replacement.Hash || result.Status != ethereum.TxStatusSuccess {
+			os.Exit(1)
+		}
+	}
+
+	signer.register(cmd)
+	fees.register(cmd)
+	cmd.Flags().Uint64VarP(&confirmations, "confirmations", "c", 1, "Number of block confirmations to wait for")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "t", 2*time.Minute, "Maximum time to wait for a transaction to be mined")
+
+	return cmd
+}
+
+// replaceFeesHelp describes how replacement fees are chosen
+const replaceFeesHelp = `Both fees are raised by at least 10% over the original, which nodes require
+to accept a replacement, or to the --speed preset when that is higher. On
+networks without EIP-1559 the gas price is raised instead, to at least 10% over
+the original or the node's gas price when that is higher; --max-fee sets it.`
+
+// displayReplacement shows the original transaction and the replacement sent for it
+func displayReplacement(network *ethereum.Network, r *ethereum.Replacement) {
+	original := r.Original
+	fmt.Printf("Original:     %s\n", original.Hash)
+	fmt.Printf("Nonce:        %d\n", original.Nonce)
+	if original.MaxFeePerGas != nil && original.MaxPriorityFeePerGas != nil {
+		fmt.Printf("Old fees:     %s gwei priority, %s gwei max\n", formatGwei(original.MaxPriorityFeePerGas), formatGwei(original.MaxFeePerGas))
+	} else if original.GasPrice != nil {
+		fmt.Printf("Old fees:     %s gwei gas price\n", formatGwei(original.GasPrice))
+	}
+
+	if r.Cancel {
+		fmt.Printf("Replacement:  cancel (0 %s to %s)\n", network.Currency, original.From.Hex())
+	} else {
+		fmt.Printf("Replacement:  speed-up\n")
+	}
+	if r.Tx.Type == ethereum.TxTypeDynamicFee {
+		fmt.Printf("Priority fee: %s gwei (minimum %s)\n", formatGwei(r.Tx.MaxPriorityFeePerGas), formatGwei(r.MinPriorityFee))
+		fmt.Printf("Max fee:      %s gwei (minimum %s)\n", formatGwei(r.Tx.MaxFeePerGas), formatGwei(r.MinMaxFeePerGas))
+	} else {
+		fmt.Printf("Gas price:    %s gwei (minimum %s)\n", formatGwei(r.Tx.GasPrice), formatGwei(r.MinMaxFeePerGas))
+	}
+	fmt.Printf("Transaction hash: %s\n", r.Hash)
+	if network.Explorer != "" {
+		fmt.Printf("View on explorer: %s\n", ethereum.FormatTransactionURL(r.Hash, network.Explorer))
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+	"github.com/metana-bootcamp/ethwallet/internal/units"
+)
+
+// txFlags are the flags shared by commands that send a contract transaction
+type txFlags struct {
+	signer        signerFlags
+	useLegacy     bool
+	fees          feeFlags
+	confirmations uint64
+	timeout       time.Duration
+}
+
+// register adds the signer and transaction flags to a command
+func (f *txFlags) register(cmd *cobra.Command) {
+	f.signer.register(cmd)
+	cmd.Flags().BoolVarP(&f.useLegacy, "legacy", "l", false, "Use legacy transaction instead of EIP-1559")
+	f.fees.register(cmd)
+	cmd.Flags().Uint64VarP(&f.confirmations, "confirmations", "c", 1, "Number of block confirmations to wait for")
+	cmd.Flags().DurationVarP(&f.timeout, "timeout", "t", 2*time.Minute, "Maximum time to wait for the transaction to be mined")
+}
+
+// parseAmountArg parses an amount such as "0.05ether" or "12gwei" into wei,
+// using defaultUnit when the amount has no unit suffix, and exits on error
+func parseAmountArg(name, s, defaultUnit string) *big.Int {
+	amount, err := units.ParseAmount(s, defaultUnit)
+	if err != nil {
+		fmt.Printf("Error: Invalid %s: %v\n", name, err)
+		os.Exit(1)
+	}
+	return amount
+}
+
+// keyPair takes the private key from the arguments when needed and loads the
+// signing key, returning the remaining arguments. required is the number of
+// arguments besides the key, described by usage.
+func (f *txFlags) keyPair(args []string, required int, usage string) (*ethereum.KeyPair, []string) {
+	return f.signer.keyPairFromArgs(args, required, usage)
+}
+
+// sendContractTx sends calldata to a contract without attaching ETH and waits for
+// the receipt, exiting on failure. Gas is estimated with the calldata; contractABI
+// decodes custom revert errors and may be nil.
+func sendContractTx(ctx context.Context, flags *txFlags, network *ethereum.Network, client *ethereum.Client, keyPair *ethereum.KeyPair, contract common.Address, data []byte, contractABI *abi.ABI) {
+	useLegacy := flags.useLegacy
+	if !useLegacy && !network.EIP1559 {
+		fmt.Printf("Network %s does not support EIP-1559, sending a legacy transaction\n", network.Name)
+		useLegacy = true
+	}
+
+	fee := flags.fees.resolve(ctx, client, useLegacy)
+	var nonce uint64
+	sendOpts := append(fee.opts, ethereum.WithData(data), nonceOption(nonceManager()), ethereum.WithSentNonce(&nonce))
+
+	fmt.Println("\n=== SENDING TRANSACTION ===")
+	fee.print()
+	var txHash string
+	var err error
+	if useLegacy {
+		txHash, err = client.SendTransaction(ctx, keyPair, contract.Hex(), big.NewInt(0), sendOpts...)
+	} else {
+		txHash, err = client.SendEIP1559Transaction(ctx, keyPair, contract.Hex(), big.NewInt(0), fee.priorityFee, sendOpts...)
+	}
+	if err != nil {
+		fmt.Printf("Error sending transaction: %v\n", err)
+		printRevertReason(err, contractABI)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Transaction hash: %s\n", txHash)
+	if network.Explorer != "" {
+		fmt.Printf("View on explorer: %s\n", ethereum.FormatTransactionURL(txHash, network.Explorer))
+	}
+
+	fmt.Printf("\nWaiting for %d confirmation(s) (timeout %s)...\n", flags.confirmations, flags.timeout)
+	waitOpts := ethereum.DefaultWaitOptions()
+	waitOpts.Confirmations = flags.confirmations
+	waitOpts.Timeout = flags.timeout
+	waitOpts.From, waitOpts.Nonce = &keyPair.Address, nonce
+
+	result, err := client.WaitForTransaction(ctx, txHash, waitOpts)
+	displayTxResult(network, result, err)
+
+	if result.Status != ethereum.TxStatusSuccess {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/metana-bootcamp/ethwallet/internal/eip712"
+	"github.com/metana-bootcamp/ethwallet/internal/ethereum"
+)
+
+// newSignTypedCmd creates the command that signs EIP-712 typed structured data
+func newSignTypedCmd() *cobra.Command {
+	var signer signerFlags
+
+	cmd := &cobra.Command{
+		Use:   "typed <privateKey> <typedData.json>",
+		Short: "Sign EIP-712 typed structured data",
+		Long: `Sign typed structured data in the eth_signTypedData_v4 JSON format (types,
+primaryType, domain and message), as used for permits, exchange orders and
+governance votes. Every field of the domain and message is shown before the
+signature, since a permit or order signature can move funds without a
+transaction from this address.
+When types has no EIP712Domain entry it is derived from the domain fields.
+This command makes no network requests. With --env or --keystore the private
+key argument is omitted.`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			var privateKeyHex string
+			if signer.keyFromArgs() {
+				if len(args) != 2 {
+					fmt.Println("Error: privateKey and typedData.json are required")
+					os.Exit(1)
+				}
+				privateKeyHex = args[0]
+				args = args[1:]
+			} else if len(args) != 1 {
+				fmt.Println("Error: typedData.json is required")
+				os.Exit(1)
+			}
+
+			td := loadTypedData(args[0])
+			displayTypedData(td)
+
+			keyPair := signer.keyPair(privateKeyHex)
+			signature, err := ethereum.SignTypedData(keyPair.PrivateKey, td)
+			if err != nil {
+				fmt.Printf("Error signing typed data: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("\n=== SIGNED TYPED DATA ===")
+			fmt.Printf("Address:   %s\n", keyPair.Address.Hex())
+			fmt.Printf("Signature: 0x%x\n", signature)
+		},
+	}
+
+	signer.register(cmd)
+
+	return cmd
+}
+
+// loadTypedData reads and validates an eth_signTypedData_v4 JSON file, exiting on error
+func loadTypedData(path string) *eip712.TypedData {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Error reading typed data: %v\n", err)
+		os.Exit(1)
+	}
+	td, err := eip712.Parse(data)
+	if err != nil {
+		fmt.Printf("Error: Invalid typed data: %v\n", err)
+		os.Exit(1)
+	}
+	return td
+}
+
+// displayTypedData shows the domain and message of typed data field by field and
+// the hashes that are signed, exiting when the values do not match the types
+func displayTypedData(td *eip712.TypedData) {
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		fmt.Printf("Error: Invalid typed data: %v\n", err)
+		os.Exit(1)
+	}
+	hash, err := td.Hash()
+	if err != nil {
+		fmt.Printf("Error: Invalid typed data: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== TYPED DATA (EIP-712) ===")
+	fmt.Println("Domain:")
+	for _, field := range td.Types[eip712.DomainType] {
+		value := formatTypedValue(field.Type, td.Domain[field.Name])
+		if field.Name == "chainId" {
+			if chainID := td.ChainID(); chainID != nil && chainID.IsUint64() {
+				if network := networkForChain(chainID.Uint64()); network != nil {
+					value += " (" + network.Name + ")"
+				}
+			}
+		}
+		fmt.Printf("  %s: %s\n", field.Name, value)
+	}
+
+	fmt.Printf("Primary type: %s\n", td.PrimaryType)
+	if td.PrimaryType != eip712.DomainType {
+		fmt.Println("Message:")
+		displayTypedStruct(td, td.PrimaryType, td.Message, "  ")
+	}
+
+	fmt.Printf("\nType:             %s\n", td.EncodeType(td.PrimaryType))
+	fmt.Printf("Domain separator: 0x%x\n", domainSeparator)
+	if td.PrimaryType != eip712.DomainType {
+		messageHash, _ := td.MessageHash()
+		fmt.Printf("Message hash:     0x%x\n", messageHash)
+	}
+	fmt.Printf("Signing hash:     0x%x\n", hash)
+}
+
+// displayTypedStruct shows the fields of a struct value, nesting structs and arrays
+func displayTypedStruct(td *eip712.TypedData, typ string, data map[string]interface{}, indent string) {
+	for _, field := range td.Types[typ] {
+		displayTypedField(td, field.Name, field.Type, data[field.Name], indent)
+	}
+}
+
+// displayTypedField shows one named value of type typ
+func displayTypedField(td *eip712.TypedData, name, typ string, value interface{}, indent string) {
+	if items, ok := value.([]interface{}); ok && strings.HasSuffix(typ, "]") {
+		elem := typ[:strings.LastIndex(typ, "[")]
+		fmt.Printf("%s%s (%s): %d item(s)\n", indent, name, typ, len(items))
+		for i, item := range items {
+			displayTypedField(td, fmt.Sprintf("[%d]", i), elem, item, indent+"  ")
+		}
+		return
+	}
+	if data, ok := value.(map[string]interface{}); ok && td.IsStruct(typ) {
+		fmt.Printf("%s%s (%s):\n", indent, name, typ)
+		displayTypedStruct(td, typ, data, indent+"  ")
+		return
+	}
+	fmt.Printf("%s%s (%s): %s\n", indent, name, typ, formatTypedValue(typ, value))
+}
+
+// formatTypedValue formats an atomic value, quoting strings
+func formatTypedValue(typ string, value interface{}) string {
+	if s, ok := value.(string); ok && typ == "string" {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,490 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Argument is a named, typed function parameter, return value, error field or event field
+type Argument struct {
+	Name    string
+	Type    Type
+	Indexed bool // event fields only
+}
+
+// Arguments is an ordered parameter list
+type Arguments []Argument
+
+// Types returns the argument types
+func (args Arguments) Types() []Type {
+	types := make([]Type, len(args))
+	for i, arg := range args {
+		types[i] = arg.Type
+	}
+	return types
+}
+
+// typeList returns the comma-separated canonical types
+func (args Arguments) typeList() string {
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = arg.Type.String()
+	}
+	return strings.Join(names, ",")
+}
+
+// Pack encodes values for the arguments
+func (args Arguments) Pack(values ...interface{}) ([]byte, error) {
+	if len(values) != len(args) {
+		return nil, fmt.Errorf("expected %d argument(s), got %d", len(args), len(values))
+	}
+	return encodeSequence(args.Types(), values)
+}
+
+// Unpack decodes values for the arguments
+func (args Arguments) Unpack(data []byte) ([]interface{}, error) {
+	return decodeSequence(args.Types(), data)
+}
+
+// Method is a contract function or constructor
+type Method struct {
+	Name            string
+	Inputs          Arguments
+	Outputs         Arguments
+	StateMutability string // pure, view, nonpayable or payable
+}
+
+// Sig returns the canonical signature, e.g. "transfer(address,uint256)"
+func (m *Method) Sig() string {
+	return m.Name + "(" + m.Inputs.typeList() + ")"
+}
+
+// ID returns the 4-byte function selector
+func (m *Method) ID() []byte {
+	return crypto.Keccak256([]byte(m.Sig()))[:4]
+}
+
+// IsConstant reports whether the method does not modify state
+func (m *Method) IsConstant() bool {
+	return m.StateMutability == "view" || m.StateMutability == "pure"
+}
+
+// Pack encodes a call: the selector followed by the encoded arguments
+func (m *Method) Pack(values ...interface{}) ([]byte, error) {
+	encoded, err := m.Inputs.Pack(values...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.Sig(), err)
+	}
+	return append(m.ID(), encoded...), nil
+}
+
+// Unpack decodes the return data of a call
+func (m *Method) Unpack(data []byte) ([]interface{}, error) {
+	values, err := m.Outputs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.Sig(), err)
+	}
+	return values, nil
+}
+
+// UnpackInput decodes the arguments of calldata that starts with the method's selector
+func (m *Method) UnpackInput(data []byte) ([]interface{}, error) {
+	if len(data) < 4 || !bytes.Equal(data[:4], m.ID()) {
+		return nil, fmt.Errorf("%s: calldata does not start with selector 0x%x", m.Sig(), m.ID())
+	}
+	values, err := m.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.Sig(), err)
+	}
+	return values, nil
+}
+
+// Error is a custom Solidity error
+type Error struct {
+	Name   string
+	Inputs Arguments
+}
+
+// Sig returns the canonical signature
+func (e *Error) Sig() string {
+	return e.Name + "(" + e.Inputs.typeList() + ")"
+}
+
+// ID returns the 4-byte error selector
+func (e *Error) ID() []byte {
+	return crypto.Keccak256([]byte(e.Sig()))[:4]
+}
+
+// Event is a contract event
+type Event struct {
+	Name      string
+	Inputs    Arguments
+	Anonymous bool
+}
+
+// Sig returns the canonical signature
+func (e *Event) Sig() string {
+	return e.Name + "(" + e.Inputs.typeList() + ")"
+}
+
+// ID returns the event topic, the keccak256 hash of the signature
+func (e *Event) ID() []byte {
+	return crypto.Keccak256([]byte(e.Sig()))
+}
+
+// ABI is a parsed contract interface
+type ABI struct {
+	Constructor *Method
+	Methods     []*Method
+	Errors      []*Error
+	Events      []*Event
+}
+
+// jsonArgument is an argument as written in a JSON ABI
+type jsonArgument struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Indexed    bool           `json:"indexed"`
+	Components []jsonArgument `json:"components"`
+}
+
+// jsonEntry is an entry of a JSON ABI
+type jsonEntry struct {
+	Type            string         `json:"type"`
+	Name            string         `json:"name"`
+	Inputs          []jsonArgument `json:"inputs"`
+	Outputs         []jsonArgument `json:"outputs"`
+	StateMutability string         `json:"stateMutability"`
+	Constant        bool           `json:"constant"` // pre-0.5 ABIs
+	Payable         bool           `json:"payable"`  // pre-0.5 ABIs
+	Anonymous       bool           `json:"anonymous"`
+}
+
+// ParseJSON parses a Solidity JSON ABI. A build artifact with an "abi" field is accepted too.
+func ParseJSON(data []byte) (*ABI, error) {
+	var entries []jsonEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		var artifact struct {
+			ABI json.RawMessage `json:"abi"`
+		}
+		if json.Unmarshal(data, &artifact) != nil || len(artifact.ABI) == 0 {
+			return nil, fmt.Errorf("failed to parse ABI: %v", err)
+		}
+
+		// solc --combined-json stores the ABI as a JSON string
+		var inner string
+		if json.Unmarshal(artifact.ABI, &inner) == nil {
+			artifact.ABI = json.RawMessage(inner)
+		}
+		if err := json.Unmarshal(artifact.ABI, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse ABI: %v", err)
+		}
+	}
+
+	abi := &ABI{}
+	for _, entry := range entries {
+		inputs, err := convertJSONArguments(entry.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", entry.Type, entry.Name, err)
+		}
+
+		switch entry.Type {
+		case "function", "":
+			outputs, err := convertJSONArguments(entry.Outputs)
+			if err != nil {
+				return nil, fmt.Errorf("function %s: %w", entry.Name, err)
+			}
+			abi.Methods = append(abi.Methods, &Method{
+				Name:            entry.Name,
+				Inputs:          inputs,
+				Outputs:         outputs,
+				StateMutability: stateMutability(entry),
+			})
+		case "constructor":
+			abi.Constructor = &Method{Inputs: inputs, StateMutability: stateMutability(entry)}
+		case "error":
+			abi.Errors = append(abi.Errors, &Error{Name: entry.Name, Inputs: inputs})
+		case "event":
+			abi.Events = append(abi.Events, &Event{Name: entry.Name, Inputs: inputs, Anonymous: entry.Anonymous})
+		}
+	}
+
+	return abi, nil
+}
+
+// stateMutability derives the state mutability, including from pre-0.5 fields
+func stateMutability(entry jsonEntry) string {
+	switch {
+	case entry.StateMutability != "":
+		return entry.StateMutability
+	case entry.Constant:
+		return "view"
+	case entry.Payable:
+		return "payable"
+	}
+	return "nonpayable"
+}
+
+// convertJSONArguments parses the types of JSON ABI arguments
+func convertJSONArguments(args []jsonArgument) (Arguments, error) {
+	converted := make(Arguments, len(args))
+	for i, arg := range args {
+		components, err := convertJSONArguments(arg.Components)
+		if err != nil {
+			return nil, err
+		}
+		typ, err := ParseType(arg.Type, components...)
+		if err != nil {
+			return nil, err
+		}
+		converted[i] = Argument{Name: arg.Name, Type: typ, Indexed: arg.Indexed}
+	}
+	return converted, nil
+}
+
+// ParseHumanReadable parses an ABI from human-readable signatures such as
+// "function balanceOf(address owner) view returns (uint256)",
+// "error InsufficientBalance(uint256 available, uint256 required)",
+// "event Transfer(address indexed from, address indexed to, uint256 value)"
+// and "constructor(string name)". A signature without a keyword is a function.
+func ParseHumanReadable(signatures ...string) (*ABI, error) {
+	abi := &ABI{}
+	for _, sig := range signatures {
+		sig = strings.TrimSpace(sig)
+		keyword, rest := "function", sig
+		if i := strings.IndexAny(sig, " ("); i > 0 {
+			switch word := sig[:i]; word {
+			case "function", "constructor", "error", "event":
+				keyword = word
+				rest = strings.TrimSpace(sig[len(word):])
+			}
+		}
+
+		switch keyword {
+		case "function":
+			method, err := ParseSignature(rest)
+			if err != nil {
+				return nil, err
+			}
+			abi.Methods = append(abi.Methods, method)
+		case "constructor":
+			method, err := ParseSignature("constructor" + rest)
+			if err != nil {
+				return nil, err
+			}
+			method.Name = ""
+			abi.Constructor = method
+		case "error":
+			method, err := ParseSignature(rest)
+			if err != nil {
+				return nil, err
+			}
+			abi.Errors = append(abi.Errors, &Error{Name: method.Name, Inputs: method.Inputs})
+		case "event":
+			anonymous := strings.HasSuffix(rest, " anonymous")
+			method, err := ParseSignature(strings.TrimSuffix(rest, " anonymous"))
+			if err != nil {
+				return nil, err
+			}
+			abi.Events = append(abi.Events, &Event{Name: method.Name, Inputs: method.Inputs, Anonymous: anonymous})
+		}
+	}
+	return abi, nil
+}
+
+// ParseSignature parses a human-readable function signature such as
+// "transfer(address,uint256)" or
+// "function balanceOf(address owner) external view returns (uint256 balance)"
+func ParseSignature(sig string) (*Method, error) {
+	sig = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(sig), "function "))
+
+	open := strings.Index(sig, "(")
+	if open <= 0 {
+		return nil, fmt.Errorf("invalid signature %q: expected name(types)", sig)
+	}
+	closing := matchingParen(sig, open)
+	if closing < 0 {
+		return nil, fmt.Errorf("invalid signature %q: unbalanced parentheses", sig)
+	}
+
+	method := &Method{Name: strings.TrimSpace(sig[:open]), StateMutability: "nonpayable"}
+	if strings.ContainsAny(method.Name, " \t,") {
+		return nil, fmt.Errorf("invalid signature %q: bad function name", sig)
+	}
+
+	inputs, err := parseParams(sig[open+1 : closing])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %q: %w", sig, err)
+	}
+	method.Inputs = inputs
+
+	// Modifiers and return values
+	rest := strings.TrimSpace(sig[closing+1:])
+	for rest != "" {
+		if strings.HasPrefix(rest, "returns") {
+			rest = strings.TrimSpace(rest[len("returns"):])
+			if !strings.HasPrefix(rest, "(") {
+				return nil, fmt.Errorf("invalid signature %q: expected returns (...)", sig)
+			}
+			end := matchingParen(rest, 0)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid signature %q: unbalanced parentheses", sig)
+			}
+			if method.Outputs, err = parseParams(rest[1:end]); err != nil {
+				return nil, fmt.Errorf("invalid signature %q: %w", sig, err)
+			}
+			rest = strings.TrimSpace(rest[end+1:])
+			continue
+		}
+
+		word := rest
+		if i := strings.IndexAny(rest, " \t"); i >= 0 {
+			word = rest[:i]
+		}
+		switch word {
+		case "view", "pure", "payable", "nonpayable":
+			method.StateMutability = word
+		case "external", "public", "virtual", "override":
+		default:
+			return nil, fmt.Errorf("invalid signature %q: unexpected %q", sig, word)
+		}
+		rest = strings.TrimSpace(rest[len(word):])
+	}
+
+	return method, nil
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseParams parses a comma-separated parameter list such as
+// "address to, uint256 amount" or "(address a, uint256 b)[] items"
+func parseParams(list string) (Arguments, error) {
+	parts, err := splitTopLevel(list)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make(Arguments, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty parameter in %q", list)
+		}
+
+		// The type is the leading tuple (with array suffixes) or the first word
+		typeEnd := strings.IndexAny(part, " \t")
+		if strings.HasPrefix(part, "(") || strings.HasPrefix(part, "tuple(") {
+			open := strings.Index(part, "(")
+			end := matchingParen(part, open)
+			if end < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in %q", part)
+			}
+			typeEnd = end + 1
+			for typeEnd < len(part) && part[typeEnd] == '[' {
+				closing := strings.Index(part[typeEnd:], "]")
+				if closing < 0 {
+					return nil, fmt.Errorf("unbalanced brackets in %q", part)
+				}
+				typeEnd += closing + 1
+			}
+		}
+		if typeEnd < 0 {
+			typeEnd = len(part)
+		}
+
+		typ, err := ParseType(part[:typeEnd])
+		if err != nil {
+			return nil, err
+		}
+
+		arg := Argument{Type: typ}
+		for _, word := range strings.Fields(part[typeEnd:]) {
+			switch word {
+			case "indexed":
+				arg.Indexed = true
+			case "memory", "calldata", "storage", "payable":
+			default:
+				arg.Name = word
+			}
+		}
+		args = append(args, arg)
+	}
+
+	return args, nil
+}
+
+// MethodByName returns the method with the given name, or with the given
+// signature when name contains parentheses. Overloads are told apart by the
+// number of arguments; pass -1 to accept any count.
+func (a *ABI) MethodByName(name string, argCount int) (*Method, error) {
+	if strings.Contains(name, "(") {
+		parsed, err := ParseSignature(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range a.Methods {
+			if m.Sig() == parsed.Sig() {
+				return m, nil
+			}
+		}
+		return nil, fmt.Errorf("no method %s in ABI", parsed.Sig())
+	}
+
+	var candidates []*Method
+	for _, m := range a.Methods {
+		if m.Name == name && (argCount < 0 || len(m.Inputs) == argCount) {
+			candidates = append(candidates, m)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no method %s with %d argument(s) in ABI", name, argCount)
+	case 1:
+		return candidates[0], nil
+	}
+
+	sigs := make([]string, len(candidates))
+	for i, m := range candidates {
+		sigs[i] = m.Sig()
+	}
+	return nil, fmt.Errorf("method %s is overloaded, use the full signature: %s", name, strings.Join(sigs, ", "))
+}
+
+// MethodByID returns the method with the given 4-byte selector
+func (a *ABI) MethodByID(selector []byte) (*Method, bool) {
+	for _, m := range a.Methods {
+		if len(selector) >= 4 && string(m.ID()) == string(selector[:4]) {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// ErrorByID returns the custom error with the given 4-byte selector
+func (a *ABI) ErrorByID(selector []byte) (*Error, bool) {
+	for _, e := range a.Errors {
+		if len(selector) >= 4 && string(e.ID()) == string(selector[:4]) {
+			return e, true
+		}
+	}
+	return nil, false
+}
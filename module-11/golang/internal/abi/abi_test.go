@@ -0,0 +1,318 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestEncodeVectors checks calldata encoding against the examples from the Solidity ABI specification
+func TestEncodeVectors(t *testing.T) {
+	vectors := []struct {
+		sig  string
+		args []string
+		want string
+	}{
+		{
+			sig:  "baz(uint32 x, bool y)",
+			args: []string{"69", "true"},
+			want: "cdcd77c0" +
+				"0000000000000000000000000000000000000000000000000000000000000045" +
+				"0000000000000000000000000000000000000000000000000000000000000001",
+		},
+		{
+			sig:  "bar(bytes3[2])",
+			args: []string{"[0x616263,0x646566]"},
+			want: "fce353f6" +
+				"6162630000000000000000000000000000000000000000000000000000000000" +
+				"6465660000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			sig:  "function sam(bytes memory, bool, uint[] memory)",
+			args: []string{"0x64617665", "true", "[1,2,3]"},
+			want: "a5643bf2" +
+				"0000000000000000000000000000000000000000000000000000000000000060" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"00000000000000000000000000000000000000000000000000000000000000a0" +
+				"0000000000000000000000000000000000000000000000000000000000000004" +
+				"6461766500000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000003" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000003",
+		},
+		{
+			sig:  "f(uint,uint32[],bytes10,bytes)",
+			args: []string{"0x123", "[0x456,0x789]", "0x31323334353637383930", "0x48656c6c6f2c20776f726c6421"},
+			want: "8be65246" +
+				"0000000000000000000000000000000000000000000000000000000000000123" +
+				"0000000000000000000000000000000000000000000000000000000000000080" +
+				"3132333435363738393000000000000000000000000000000000000000000000" +
+				"00000000000000000000000000000000000000000000000000000000000000e0" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000456" +
+				"0000000000000000000000000000000000000000000000000000000000000789" +
+				"000000000000000000000000000000000000000000000000000000000000000d" +
+				"48656c6c6f2c20776f726c642100000000000000000000000000000000000000",
+		},
+		{
+			sig:  "g(uint[][],string[])",
+			args: []string{"[[1,2],[3]]", `["one","two","three"]`},
+			want: "2289b18c" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000140" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"00000000000000000000000000000000000000000000000000000000000000a0" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000003" +
+				"0000000000000000000000000000000000000000000000000000000000000003" +
+				"0000000000000000000000000000000000000000000000000000000000000060" +
+				"00000000000000000000000000000000000000000000000000000000000000a0" +
+				"00000000000000000000000000000000000000000000000000000000000000e0" +
+				"0000000000000000000000000000000000000000000000000000000000000003" +
+				"6f6e650000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000003" +
+				"74776f0000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000005" +
+				"7468726565000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			sig:  "h((address a, string b, int256[2] c) t, int8)",
+			args: []string{"(0xde9ca654aE5a3673d894eba15b63603Fa00F8504,hi,[-1,256])", "-128"},
+			want: "9758aa10" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff80" +
+				"000000000000000000000000de9ca654ae5a3673d894eba15b63603fa00f8504" +
+				"0000000000000000000000000000000000000000000000000000000000000080" +
+				"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+				"0000000000000000000000000000000000000000000000000000000000000100" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"6869000000000000000000000000000000000000000000000000000000000000",
+		},
+	}
+
+	for _, v := range vectors {
+		method, err := ParseSignature(v.sig)
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", v.sig, err)
+		}
+		args, err := method.Inputs.ParseArgs(v.args)
+		if err != nil {
+			t.Fatalf("%s: failed to parse arguments: %v", method.Sig(), err)
+		}
+		data, err := method.Pack(args...)
+		if err != nil {
+			t.Fatalf("%s: failed to encode: %v", method.Sig(), err)
+		}
+		if got := hex.EncodeToString(data); got != v.want {
+			t.Fatalf("%s: calldata mismatch\n got: %s\nwant: %s", method.Sig(), got, v.want)
+		}
+
+		// Decoding the arguments gives back the same encoding
+		decoded, err := method.Inputs.Unpack(data[4:])
+		if err != nil {
+			t.Fatalf("%s: failed to decode: %v", method.Sig(), err)
+		}
+		reencoded, err := method.Pack(decoded...)
+		if err != nil || !bytes.Equal(reencoded, data) {
+			t.Fatalf("%s: round trip mismatch: %v", method.Sig(), err)
+		}
+	}
+}
+
+// TestEncodeGoValues tests encoding from native Go values
+func TestEncodeGoValues(t *testing.T) {
+	method, err := ParseSignature("transfer(address,uint256)")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if got := hex.EncodeToString(method.ID()); got != "a9059cbb" {
+		t.Fatalf("Selector %s, expected a9059cbb", got)
+	}
+
+	to := common.HexToAddress("0xde9ca654aE5a3673d894eba15b63603Fa00F8504")
+	for _, amount := range []interface{}{big.NewInt(1000), uint64(1000), 1000} {
+		data, err := method.Pack(to, amount)
+		if err != nil {
+			t.Fatalf("Failed to encode %T: %v", amount, err)
+		}
+		want := "a9059cbb000000000000000000000000de9ca654ae5a3673d894eba15b63603fa00f850400000000000000000000000000000000000000000000000000000000000003e8"
+		if got := hex.EncodeToString(data); got != want {
+			t.Fatalf("Calldata mismatch for %T: %s", amount, got)
+		}
+	}
+
+	// Calldata decodes back to the arguments
+	data, _ := method.Pack(to, 1000)
+	values, err := method.UnpackInput(data)
+	if err != nil {
+		t.Fatalf("Failed to decode calldata: %v", err)
+	}
+	if values[0] != to || values[1].(*big.Int).Int64() != 1000 {
+		t.Fatalf("Decoded %v", values)
+	}
+	if _, err := method.UnpackInput(append([]byte{0, 0, 0, 0}, data[4:]...)); err == nil {
+		t.Fatalf("Expected an error for another selector")
+	}
+
+	// Out of range and mistyped values are rejected
+	bad := []struct {
+		sig   string
+		value interface{}
+	}{
+		{"f(uint8)", 256},
+		{"f(uint256)", -1},
+		{"f(int8)", -129},
+		{"f(bytes4)", []byte{1, 2, 3}},
+		{"f(address)", "0x1234"},
+		{"f(bool)", 1},
+		{"f(uint256[2])", []int{1}},
+	}
+	for _, b := range bad {
+		m, _ := ParseSignature(b.sig)
+		if _, err := m.Pack(b.value); err == nil {
+			t.Fatalf("%s: expected an error for %v", b.sig, b.value)
+		}
+	}
+
+	// Command-line addresses with a broken EIP-55 checksum are rejected
+	if _, err := ParseArg(Type{Kind: AddressKind}, "0xDe9ca654aE5a3673d894eba15b63603Fa00F8504"); err == nil {
+		t.Fatalf("Expected an error for a bad checksum")
+	}
+}
+
+// TestParseSignature tests human-readable signature parsing
+func TestParseSignature(t *testing.T) {
+	sigs := map[string]string{
+		"transfer(address,uint256)":                                         "transfer(address,uint256)",
+		"function balanceOf(address owner) external view returns (uint256)": "balanceOf(address)",
+		"swap((address,uint)[] calldata path, bytes32 salt) payable":        "swap((address,uint256)[],bytes32)",
+		"exec(tuple(address to, bytes data)[2] calls)":                      "exec((address,bytes)[2])",
+		"noop()": "noop()",
+	}
+	for sig, want := range sigs {
+		method, err := ParseSignature(sig)
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", sig, err)
+		}
+		if got := method.Sig(); got != want {
+			t.Fatalf("%s: signature %s, expected %s", sig, got, want)
+		}
+	}
+
+	method, _ := ParseSignature("function balanceOf(address owner) view returns (uint256 balance)")
+	if !method.IsConstant() || len(method.Outputs) != 1 || method.Outputs[0].Name != "balance" {
+		t.Fatalf("Unexpected method %+v", method)
+	}
+
+	for _, sig := range []string{"transfer", "f(uint7)", "f(address", "f(bytes33)", "f(uint256) returns", "f() nonsense"} {
+		if _, err := ParseSignature(sig); err == nil {
+			t.Fatalf("%s: expected an error", sig)
+		}
+	}
+}
+
+// TestParseJSON tests JSON ABI parsing, overload resolution and output decoding
+func TestParseJSON(t *testing.T) {
+	abi, err := ParseJSON([]byte(`{"contractName":"Token","abi":[
+		{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}],"stateMutability":"nonpayable"},
+		{"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+		{"type":"function","name":"safeTransferFrom","inputs":[{"type":"address"},{"type":"address"},{"type":"uint256"}],"outputs":[]},
+		{"type":"function","name":"safeTransferFrom","inputs":[{"type":"address"},{"type":"address"},{"type":"uint256"},{"type":"bytes"}],"outputs":[]},
+		{"type":"function","name":"info","inputs":[],"outputs":[{"name":"meta","type":"tuple","components":[{"name":"name","type":"string"},{"name":"ids","type":"uint256[]"}]}],"constant":true},
+		{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]},
+		{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}]}
+	]}`))
+	if err != nil {
+		t.Fatalf("Failed to parse ABI: %v", err)
+	}
+
+	if abi.Constructor == nil || len(abi.Constructor.Inputs) != 1 {
+		t.Fatalf("Unexpected constructor %+v", abi.Constructor)
+	}
+	if got := hex.EncodeToString(abi.Events[0].ID()); got != "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Fatalf("Transfer topic %s", got)
+	}
+
+	// Overloads resolve by argument count or full signature
+	if m, err := abi.MethodByName("safeTransferFrom", 4); err != nil || m.Sig() != "safeTransferFrom(address,address,uint256,bytes)" {
+		t.Fatalf("Unexpected overload %v: %v", m, err)
+	}
+	if m, err := abi.MethodByName("safeTransferFrom(address,address,uint256)", -1); err != nil || len(m.Inputs) != 3 {
+		t.Fatalf("Unexpected method by signature %v: %v", m, err)
+	}
+	if _, err := abi.MethodByName("safeTransferFrom", -1); err == nil || !strings.Contains(err.Error(), "overloaded") {
+		t.Fatalf("Expected an overload error, got %v", err)
+	}
+	if m, ok := abi.MethodByID([]byte{0x70, 0xa0, 0x82, 0x31}); !ok || m.Name != "balanceOf" {
+		t.Fatalf("balanceOf not found by selector")
+	}
+
+	// Decode a tuple with dynamic fields: ("Token", [7, 9])
+	info, _ := abi.MethodByName("info", 0)
+	if !info.IsConstant() {
+		t.Fatalf("Pre-0.5 constant function should be a view")
+	}
+	encoded, err := info.Outputs.Pack([]interface{}{"Token", []*big.Int{big.NewInt(7), big.NewInt(9)}})
+	if err != nil {
+		t.Fatalf("Failed to encode outputs: %v", err)
+	}
+	values, err := info.Unpack(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode outputs: %v", err)
+	}
+	if got := FormatValue(info.Outputs[0].Type, values[0]); got != `("Token", [7, 9])` {
+		t.Fatalf("Decoded %s", got)
+	}
+
+	// Truncated and malicious data is rejected rather than panicking
+	for _, data := range [][]byte{encoded[:40], encoded[:len(encoded)-1], bytes.Repeat([]byte{0xff}, 64)} {
+		if _, err := info.Unpack(data); err == nil {
+			t.Fatalf("Expected an error decoding %x", data)
+		}
+	}
+}
+
+// TestDecodeRevert tests revert reasons, panic codes and custom errors
+func TestDecodeRevert(t *testing.T) {
+	abi, err := ParseHumanReadable(
+		"error InsufficientBalance(uint256 available, uint256 required)",
+		"function transfer(address to, uint256 amount) returns (bool)",
+	)
+	if err != nil {
+		t.Fatalf("Failed to parse ABI: %v", err)
+	}
+
+	reason, _ := hex.DecodeString("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000001a" +
+		"4e6f7420656e6f7567682045746865722070726f76696465642e000000000000")
+	overflow, _ := hex.DecodeString("4e487b71" +
+		"0000000000000000000000000000000000000000000000000000000000000011")
+	custom, _ := abi.Errors[0].Inputs.Pack(big.NewInt(5), big.NewInt(10))
+	custom = append(abi.Errors[0].ID(), custom...)
+
+	cases := []struct {
+		data []byte
+		want string
+	}{
+		{reason, "execution reverted: Not enough Ether provided."},
+		{overflow, "panic 0x11: arithmetic overflow or underflow"},
+		{custom, "execution reverted: InsufficientBalance(available=5, required=10)"},
+		{nil, "execution reverted without a reason"},
+		{[]byte{0xde, 0xad, 0xbe, 0xef}, "execution reverted with custom error 0xdeadbeef"},
+	}
+	for _, c := range cases {
+		if got := DecodeRevert(c.data, abi); got != c.want {
+			t.Fatalf("Revert %x: got %q, want %q", c.data, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/metana-bootcamp/ethwallet/internal/address"
+)
+
+var (
+	// tt256 is 2^256, used for two's complement encoding
+	tt256 = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// encodeSequence encodes values as a tuple of types: static values and offsets
+// in the head, dynamic values in the tail
+func encodeSequence(types []Type, values []interface{}) ([]byte, error) {
+	if len(types) != len(values) {
+		return nil, fmt.Errorf("expected %d value(s), got %d", len(types), len(values))
+	}
+
+	headSize := 0
+	for _, t := range types {
+		headSize += t.headSize()
+	}
+
+	var head, tail []byte
+	for i, t := range types {
+		encoded, err := encode(t, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %w", i, t, err)
+		}
+		if t.IsDynamic() {
+			head = append(head, encodeUint(big.NewInt(int64(headSize+len(tail))))...)
+			tail = append(tail, encoded...)
+		} else {
+			head = append(head, encoded...)
+		}
+	}
+
+	return append(head, tail...), nil
+}
+
+// encode encodes a single value of type t
+func encode(t Type, value interface{}) ([]byte, error) {
+	switch t.Kind {
+	case UintKind, IntKind:
+		n, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkIntRange(t, n); err != nil {
+			return nil, err
+		}
+		if n.Sign() < 0 {
+			n = new(big.Int).Add(tt256, n)
+		}
+		return encodeUint(n), nil
+
+	case AddressKind:
+		var addr common.Address
+		switch v := value.(type) {
+		case common.Address:
+			addr = v
+		case *common.Address:
+			addr = *v
+		case string:
+			if !common.IsHexAddress(v) {
+				return nil, fmt.Errorf("invalid address %q", v)
+			}
+			addr = common.HexToAddress(v)
+		default:
+			return nil, fmt.Errorf("cannot use %T as address", value)
+		}
+		return common.LeftPadBytes(addr.Bytes(), 32), nil
+
+	case BoolKind:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("cannot use %T as bool", value)
+		}
+		if b {
+			return encodeUint(big.NewInt(1)), nil
+		}
+		return encodeUint(big.NewInt(0)), nil
+
+	case FixedBytesKind:
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("expected %d bytes, got %d", t.Size, len(b))
+		}
+		return common.RightPadBytes(b, 32), nil
+
+	case BytesKind, StringKind:
+		var b []byte
+		if s, ok := value.(string); ok && t.Kind == StringKind {
+			b = []byte(s)
+		} else {
+			var err error
+			if b, err = toBytes(value); err != nil {
+				return nil, err
+			}
+		}
+		out := encodeUint(big.NewInt(int64(len(b))))
+		return append(out, common.RightPadBytes(b, (len(b)+31)/32*32)...), nil
+
+	case ArrayKind, SliceKind:
+		elems, err := toSlice(value)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind == ArrayKind && len(elems) != t.Size {
+			return nil, fmt.Errorf("expected %d elements, got %d", t.Size, len(elems))
+		}
+		types := make([]Type, len(elems))
+		for i := range types {
+			types[i] = *t.Elem
+		}
+		encoded, err := encodeSequence(types, elems)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind == SliceKind {
+			encoded = append(encodeUint(big.NewInt(int64(len(elems)))), encoded...)
+		}
+		return encoded, nil
+
+	case TupleKind:
+		fields, err := toSlice(value)
+		if err != nil {
+			return nil, err
+		}
+		return encodeSequence(Arguments(t.Components).Types(), fields)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// encodeUint encodes a non-negative integer as a 32-byte word
+func encodeUint(n *big.Int) []byte {
+	return common.LeftPadBytes(n.Bytes(), 32)
+}
+
+// checkIntRange checks that n fits the integer type t
+func checkIntRange(t Type, n *big.Int) error {
+	if t.Kind == UintKind {
+		if n.Sign() < 0 || n.BitLen() > t.Size {
+			return fmt.Errorf("value %s out of range for %s", n, t)
+		}
+		return nil
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+	minimum := new(big.Int).Neg(limit)
+	if n.Cmp(minimum) < 0 || n.Cmp(limit) >= 0 {
+		return fmt.Errorf("value %s out of range for %s", n, t)
+	}
+	return nil
+}
+
+// toBigInt converts Go integer values to a big integer
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("nil integer")
+		}
+		return v, nil
+	case big.Int:
+		return &v, nil
+	case string:
+		n, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+		return n, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+	return nil, fmt.Errorf("cannot use %T as integer", value)
+}
+
+// toBytes converts byte slices, byte arrays such as common.Hash and hex strings
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return decodeHex(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return b, nil
+	}
+	return nil, fmt.Errorf("cannot use %T as bytes", value)
+}
+
+// toSlice converts a Go slice or array to a list of values
+func toSlice(value interface{}) ([]interface{}, error) {
+	if values, ok := value.([]interface{}); ok {
+		return values, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot use %T as array or tuple", value)
+	}
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, nil
+}
+
+// decodeHex decodes a 0x-prefixed hex string
+func decodeHex(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return nil, fmt.Errorf("hex string %q must start with 0x", s)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex %q: %v", s, err)
+	}
+	return b, nil
+}
+
+// ParseArg parses a command-line argument into a value that can be encoded as t.
+// Integers are decimal or 0x hex, bytes are 0x hex, arrays are written as
+// [a,b] and tuples as (a,b). Strings inside arrays and tuples may be quoted.
+func ParseArg(t Type, s string) (interface{}, error) {
+	s = strings.TrimSpace(s)
+
+	switch t.Kind {
+	case UintKind, IntKind:
+		n, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %q", t, s)
+		}
+		if err := checkIntRange(t, n); err != nil {
+			return nil, err
+		}
+		return n, nil
+
+	case AddressKind:
+		// Mixed-case addresses must carry a valid EIP-55 checksum
+		return address.Parse(s, 0)
+
+	case BoolKind:
+		switch s {
+		case "true", "1":
+			return true, nil
+		case "false", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("invalid bool %q", s)
+
+	case FixedBytesKind, BytesKind:
+		b, err := decodeHex(s)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind == FixedBytesKind && len(b) != t.Size {
+			return nil, fmt.Errorf("%s needs %d bytes, got %d", t, t.Size, len(b))
+		}
+		return b, nil
+
+	case StringKind:
+		return unquote(s), nil
+
+	case ArrayKind, SliceKind, TupleKind:
+		open, closing := "[", "]"
+		if t.Kind == TupleKind && strings.HasPrefix(s, "(") {
+			open, closing = "(", ")"
+		}
+		if !strings.HasPrefix(s, open) || !strings.HasSuffix(s, closing) {
+			return nil, fmt.Errorf("%s must be written as %s...%s", t, open, closing)
+		}
+		parts, err := splitTopLevel(s[1 : len(s)-1])
+		if err != nil {
+			return nil, err
+		}
+
+		types := make([]Type, len(parts))
+		if t.Kind == TupleKind {
+			if len(parts) != len(t.Components) {
+				return nil, fmt.Errorf("%s needs %d fields, got %d", t, len(t.Components), len(parts))
+			}
+			types = Arguments(t.Components).Types()
+		} else {
+			if t.Kind == ArrayKind && len(parts) != t.Size {
+				return nil, fmt.Errorf("%s needs %d elements, got %d", t, t.Size, len(parts))
+			}
+			for i := range types {
+				types[i] = *t.Elem
+			}
+		}
+
+		values := make([]interface{}, len(parts))
+		for i, part := range parts {
+			if values[i], err = ParseArg(types[i], part); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// ParseArgs parses command-line arguments for a parameter list
+func (args Arguments) ParseArgs(values []string) ([]interface{}, error) {
+	if len(values) != len(args) {
+		return nil, fmt.Errorf("expected %d argument(s) (%s), got %d", len(args), args.typeList(), len(values))
+	}
+	parsed := make([]interface{}, len(values))
+	for i, arg := range args {
+		v, err := ParseArg(arg.Type, values[i])
+		if err != nil {
+			name := arg.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("argument %s: %w", name, err)
+		}
+		parsed[i] = v
+	}
+	return parsed, nil
+}
+
+// unquote strips matching double quotes
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,77 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	// errorSelector is the selector of Error(string), used by require and revert("...")
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// panicSelector is the selector of Panic(uint256), used by failed asserts and checks
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicReasons describes the Solidity panic codes
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to uninitialized internal function",
+}
+
+// DecodeRevert describes revert data returned by a failed call: Error(string)
+// reasons, Panic(uint256) codes and, when abi is not nil, its custom errors
+func DecodeRevert(data []byte, abi *ABI) string {
+	if len(data) == 0 {
+		return "execution reverted without a reason"
+	}
+	if len(data) < 4 {
+		return fmt.Sprintf("execution reverted with invalid data 0x%x", data)
+	}
+
+	selector, payload := data[:4], data[4:]
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		values, err := decodeSequence([]Type{{Kind: StringKind}}, payload)
+		if err == nil {
+			return fmt.Sprintf("execution reverted: %s", values[0])
+		}
+
+	case bytes.Equal(selector, panicSelector):
+		values, err := decodeSequence([]Type{{Kind: UintKind, Size: 256}}, payload)
+		if err == nil {
+			code := values[0].(*big.Int)
+			reason, ok := panicReasons[code.Uint64()]
+			if !ok || !code.IsUint64() {
+				reason = "unknown panic code"
+			}
+			return fmt.Sprintf("panic 0x%02x: %s", code, reason)
+		}
+
+	case abi != nil:
+		if customErr, ok := abi.ErrorByID(selector); ok {
+			values, err := customErr.Inputs.Unpack(payload)
+			if err == nil {
+				parts := make([]string, len(values))
+				for i, v := range values {
+					parts[i] = FormatValue(customErr.Inputs[i].Type, v)
+					if name := customErr.Inputs[i].Name; name != "" {
+						parts[i] = name + "=" + parts[i]
+					}
+				}
+				return fmt.Sprintf("execution reverted: %s(%s)", customErr.Name, strings.Join(parts, ", "))
+			}
+		}
+	}
+
+	return fmt.Sprintf("execution reverted with custom error 0x%x", data)
+}
@@ -0,0 +1,225 @@
+// Package abi implements the Solidity contract ABI: parsing JSON ABIs and
+// human-readable signatures, encoding calldata and decoding return data and
+// revert reasons.
+package abi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind is the category of an ABI type
+type Kind int
+
+const (
+	UintKind       Kind = iota // uint8 ... uint256
+	IntKind                    // int8 ... int256
+	AddressKind                // address
+	BoolKind                   // bool
+	FixedBytesKind             // bytes1 ... bytes32
+	BytesKind                  // bytes
+	StringKind                 // string
+	ArrayKind                  // T[N]
+	SliceKind                  // T[]
+	TupleKind                  // (T1,T2,...)
+)
+
+// Type is a parsed ABI type
+type Type struct {
+	Kind Kind
+	// Size is the bit size for integers, the byte size for fixed bytes and the
+	// length for fixed arrays
+	Size int
+	// Elem is the element type of arrays and slices
+	Elem *Type
+	// Components are the fields of a tuple
+	Components []Argument
+}
+
+// ParseType parses a canonical or human-readable type such as "uint256",
+// "bytes32[]" or "(address,uint256)[2]". Tuples written as "tuple" take
+// their fields from components, as in JSON ABIs.
+func ParseType(s string, components ...Argument) (Type, error) {
+	s = strings.TrimSpace(s)
+
+	// Array suffixes bind last: T[2][] is a slice of T[2]
+	if strings.HasSuffix(s, "]") {
+		open := strings.LastIndex(s, "[")
+		if open < 0 {
+			return Type{}, fmt.Errorf("invalid type %q", s)
+		}
+		elem, err := ParseType(s[:open], components...)
+		if err != nil {
+			return Type{}, err
+		}
+
+		length := s[open+1 : len(s)-1]
+		if length == "" {
+			return Type{Kind: SliceKind, Elem: &elem}, nil
+		}
+		n, err := strconv.Atoi(length)
+		if err != nil || n <= 0 {
+			return Type{}, fmt.Errorf("invalid array length in %q", s)
+		}
+		return Type{Kind: ArrayKind, Size: n, Elem: &elem}, nil
+	}
+
+	// Tuples, either inline or with components from a JSON ABI
+	if strings.HasPrefix(s, "tuple(") {
+		s = s[len("tuple"):]
+	}
+	if strings.HasPrefix(s, "(") {
+		if !strings.HasSuffix(s, ")") {
+			return Type{}, fmt.Errorf("invalid tuple type %q", s)
+		}
+		fields, err := parseParams(s[1 : len(s)-1])
+		if err != nil {
+			return Type{}, err
+		}
+		return Type{Kind: TupleKind, Components: fields}, nil
+	}
+	if s == "tuple" {
+		return Type{Kind: TupleKind, Components: components}, nil
+	}
+
+	switch s {
+	case "address":
+		return Type{Kind: AddressKind}, nil
+	case "bool":
+		return Type{Kind: BoolKind}, nil
+	case "string":
+		return Type{Kind: StringKind}, nil
+	case "bytes":
+		return Type{Kind: BytesKind}, nil
+	case "uint":
+		return Type{Kind: UintKind, Size: 256}, nil
+	case "int":
+		return Type{Kind: IntKind, Size: 256}, nil
+	case "byte":
+		return Type{Kind: FixedBytesKind, Size: 1}, nil
+	case "function":
+		// An address followed by a selector
+		return Type{Kind: FixedBytesKind, Size: 24}, nil
+	}
+
+	for _, prefix := range []struct {
+		name string
+		kind Kind
+	}{{"uint", UintKind}, {"int", IntKind}, {"bytes", FixedBytesKind}} {
+		if !strings.HasPrefix(s, prefix.name) {
+			continue
+		}
+		n, err := strconv.Atoi(s[len(prefix.name):])
+		if err != nil {
+			break
+		}
+		if prefix.kind == FixedBytesKind {
+			if n < 1 || n > 32 {
+				return Type{}, fmt.Errorf("invalid fixed bytes size in %q", s)
+			}
+		} else if n < 8 || n > 256 || n%8 != 0 {
+			return Type{}, fmt.Errorf("invalid integer size in %q", s)
+		}
+		return Type{Kind: prefix.kind, Size: n}, nil
+	}
+
+	return Type{}, fmt.Errorf("unsupported type %q", s)
+}
+
+// String returns the canonical type name used in signatures
+func (t Type) String() string {
+	switch t.Kind {
+	case UintKind:
+		return fmt.Sprintf("uint%d", t.Size)
+	case IntKind:
+		return fmt.Sprintf("int%d", t.Size)
+	case AddressKind:
+		return "address"
+	case BoolKind:
+		return "bool"
+	case FixedBytesKind:
+		return fmt.Sprintf("bytes%d", t.Size)
+	case BytesKind:
+		return "bytes"
+	case StringKind:
+		return "string"
+	case ArrayKind:
+		return fmt.Sprintf("%s[%d]", t.Elem, t.Size)
+	case SliceKind:
+		return t.Elem.String() + "[]"
+	case TupleKind:
+		return "(" + Arguments(t.Components).typeList() + ")"
+	}
+	return "unknown"
+}
+
+// IsDynamic reports whether the encoding of t has a variable length and is
+// therefore placed in the tail with an offset in the head
+func (t Type) IsDynamic() bool {
+	switch t.Kind {
+	case BytesKind, StringKind, SliceKind:
+		return true
+	case ArrayKind:
+		return t.Elem.IsDynamic()
+	case TupleKind:
+		for _, c := range t.Components {
+			if c.Type.IsDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize returns the number of bytes t occupies in the head of a sequence
+func (t Type) headSize() int {
+	if t.IsDynamic() {
+		return 32
+	}
+	switch t.Kind {
+	case ArrayKind:
+		return t.Size * t.Elem.headSize()
+	case TupleKind:
+		size := 0
+		for _, c := range t.Components {
+			size += c.Type.headSize()
+		}
+		return size
+	}
+	return 32
+}
+
+// splitTopLevel splits s on commas that are not nested in brackets, parentheses or quotes
+func splitTopLevel(s string) ([]string, error) {
+	var parts []string
+	depth := 0
+	inQuote := false
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced brackets in %q", s)
+			}
+		case c == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if depth != 0 || inQuote {
+		return nil, fmt.Errorf("unbalanced brackets or quotes in %q", s)
+	}
+
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	return append(parts, s[start:]), nil
+}
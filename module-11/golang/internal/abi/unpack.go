@@ -0,0 +1,175 @@
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// decodeSequence decodes a tuple of types. Values are returned as *big.Int,
+// common.Address, bool, []byte, string or []interface{} for arrays and tuples.
+func decodeSequence(types []Type, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(types))
+	offset := 0
+
+	for i, t := range types {
+		if offset+t.headSize() > len(data) {
+			return nil, fmt.Errorf("data too short for %s at offset %d", t, offset)
+		}
+
+		elem := data[offset:]
+		if t.IsDynamic() {
+			start, err := readOffset(data, offset)
+			if err != nil {
+				return nil, err
+			}
+			elem = data[start:]
+		}
+
+		value, err := decode(t, elem)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+		offset += t.headSize()
+	}
+
+	return values, nil
+}
+
+// decode decodes a value of type t starting at the beginning of data
+func decode(t Type, data []byte) (interface{}, error) {
+	switch t.Kind {
+	case UintKind, IntKind, AddressKind, BoolKind, FixedBytesKind:
+		if len(data) < 32 {
+			return nil, fmt.Errorf("data too short for %s", t)
+		}
+		word := data[:32]
+
+		switch t.Kind {
+		case UintKind:
+			n := new(big.Int).SetBytes(word)
+			if n.BitLen() > t.Size {
+				return nil, fmt.Errorf("value out of range for %s", t)
+			}
+			return n, nil
+		case IntKind:
+			n := new(big.Int).SetBytes(word)
+			if word[0]&0x80 != 0 {
+				n.Sub(n, tt256)
+			}
+			if err := checkIntRange(t, n); err != nil {
+				return nil, err
+			}
+			return n, nil
+		case AddressKind:
+			return common.BytesToAddress(word[12:]), nil
+		case BoolKind:
+			n := new(big.Int).SetBytes(word)
+			if n.BitLen() > 1 {
+				return nil, fmt.Errorf("invalid bool %x", word)
+			}
+			return n.Sign() == 1, nil
+		default:
+			return append([]byte(nil), word[:t.Size]...), nil
+		}
+
+	case BytesKind, StringKind:
+		length, err := readLength(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		if 32+length > len(data) {
+			return nil, fmt.Errorf("data too short for %s of length %d", t, length)
+		}
+		b := append([]byte(nil), data[32:32+length]...)
+		if t.Kind == StringKind {
+			return string(b), nil
+		}
+		return b, nil
+
+	case ArrayKind, SliceKind:
+		length := t.Size
+		if t.Kind == SliceKind {
+			var err error
+			if length, err = readLength(data, 0); err != nil {
+				return nil, err
+			}
+			data = data[32:]
+		}
+		if length*32 > len(data) {
+			// Every element takes at least a word in the head
+			return nil, fmt.Errorf("data too short for %s of length %d", t, length)
+		}
+		types := make([]Type, length)
+		for i := range types {
+			types[i] = *t.Elem
+		}
+		return decodeSequence(types, data)
+
+	case TupleKind:
+		return decodeSequence(Arguments(t.Components).Types(), data)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// readOffset reads the offset word at position pos and checks it points inside data
+func readOffset(data []byte, pos int) (int, error) {
+	n, err := readLength(data, pos)
+	if err != nil {
+		return 0, err
+	}
+	if n > len(data) {
+		return 0, fmt.Errorf("offset %d out of bounds", n)
+	}
+	return n, nil
+}
+
+// readLength reads the word at position pos as a small non-negative integer
+func readLength(data []byte, pos int) (int, error) {
+	if pos+32 > len(data) {
+		return 0, fmt.Errorf("data too short at offset %d", pos)
+	}
+	n := new(big.Int).SetBytes(data[pos : pos+32])
+	if !n.IsInt64() || n.Int64() > int64(len(data)) {
+		return 0, fmt.Errorf("length or offset %s out of bounds", n)
+	}
+	return int(n.Int64()), nil
+}
+
+// FormatValue formats a decoded value of type t for display
+func FormatValue(t Type, value interface{}) string {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case bool:
+		return fmt.Sprintf("%t", v)
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, elem := range v {
+			elemType := Type{}
+			switch {
+			case t.Kind == TupleKind && i < len(t.Components):
+				elemType = t.Components[i].Type
+			case t.Elem != nil:
+				elemType = *t.Elem
+			}
+			parts[i] = FormatValue(elemType, elem)
+		}
+		if t.Kind == TupleKind {
+			return "(" + strings.Join(parts, ", ") + ")"
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", value)
+}
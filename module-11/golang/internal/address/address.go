@@ -0,0 +1,82 @@
+// Package address parses and formats Ethereum addresses with EIP-55 mixed-case
+// checksums and their EIP-1191 chain-specific variant.
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrInvalid is returned for strings that are not 0x-prefixed 20-byte hex addresses
+	ErrInvalid = errors.New("invalid address")
+
+	// ErrChecksum is returned for mixed-case addresses whose checksum does not match
+	ErrChecksum = errors.New("invalid address checksum")
+)
+
+// IsHex reports whether s is a 0x-prefixed address of 40 hex digits, without
+// checking its checksum
+func IsHex(s string) bool {
+	if len(s) != 42 || !strings.HasPrefix(s, "0x") {
+		return false
+	}
+	_, err := hex.DecodeString(s[2:])
+	return err == nil
+}
+
+// HasChecksum reports whether s is written in mixed case and therefore carries a
+// checksum. All-lowercase and all-uppercase addresses have none.
+func HasChecksum(s string) bool {
+	digits := strings.TrimPrefix(s, "0x")
+	return digits != strings.ToLower(digits) && digits != strings.ToUpper(digits)
+}
+
+// Checksum returns the mixed-case encoding of addr. A chainID of 0 gives the
+// EIP-55 checksum; any other value gives the EIP-1191 checksum for that chain.
+func Checksum(addr common.Address, chainID uint64) string {
+	lower := hex.EncodeToString(addr.Bytes())
+
+	// EIP-1191 prefixes the hashed string with the chain ID
+	hashed := lower
+	if chainID != 0 {
+		hashed = strconv.FormatUint(chainID, 10) + "0x" + lower
+	}
+	hash := crypto.Keccak256([]byte(hashed))
+
+	result := []byte(lower)
+	for i, c := range result {
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c > '9' && nibble&0xf >= 8 {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
+
+// Parse parses a 0x-prefixed hex address. Mixed-case input must match the
+// checksum for chainID (see Checksum); lowercase and uppercase input is accepted
+// without a checksum, which callers should warn about.
+func Parse(s string, chainID uint64) (common.Address, error) {
+	if !IsHex(s) {
+		return common.Address{}, fmt.Errorf("%w %q: must be 0x followed by 40 hex digits", ErrInvalid, s)
+	}
+
+	addr := common.HexToAddress(s)
+	if HasChecksum(s) && Checksum(addr, chainID) != s {
+		if chainID != 0 {
+			return common.Address{}, fmt.Errorf("%w for chain %d (EIP-1191): %s", ErrChecksum, chainID, s)
+		}
+		return common.Address{}, fmt.Errorf("%w (EIP-55): %s", ErrChecksum, s)
+	}
+	return addr, nil
+}
@@ -0,0 +1,89 @@
+package address
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// checksumVectors are the test vectors of EIP-55 (chain 0) and EIP-1191 (RSK mainnet 30 and testnet 31)
+var checksumVectors = map[uint64][]string{
+	0: {
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	},
+	30: {
+		"0x5aaEB6053f3e94c9b9a09f33669435E7ef1bEAeD",
+		"0xFb6916095cA1Df60bb79ce92cE3EA74c37c5d359",
+		"0xDBF03B407c01E7CD3cBea99509D93F8Dddc8C6FB",
+		"0xD1220A0Cf47c7B9BE7a2e6ba89F429762E7B9adB",
+	},
+	31: {
+		"0x5aAeb6053F3e94c9b9A09F33669435E7EF1BEaEd",
+		"0xFb6916095CA1dF60bb79CE92ce3Ea74C37c5D359",
+		"0xdbF03B407C01E7cd3cbEa99509D93f8dDDc8C6fB",
+		"0xd1220a0CF47c7B9Be7A2E6Ba89f429762E7b9adB",
+	},
+}
+
+// TestChecksum tests the EIP-55 and EIP-1191 checksum vectors
+func TestChecksum(t *testing.T) {
+	for chainID, vectors := range checksumVectors {
+		for _, want := range vectors {
+			addr := common.HexToAddress(want)
+			if got := Checksum(addr, chainID); got != want {
+				t.Fatalf("Checksum(%s, %d) = %s, expected %s", strings.ToLower(want), chainID, got, want)
+			}
+
+			parsed, err := Parse(want, chainID)
+			if err != nil || parsed != addr {
+				t.Fatalf("Parse(%s, %d) = %s, %v", want, chainID, parsed.Hex(), err)
+			}
+		}
+	}
+
+	// The EIP-55 encoding matches go-ethereum's
+	addr := common.HexToAddress("0xde9ca654ae5a3673d894eba15b63603fa00f8504")
+	if got := Checksum(addr, 0); got != addr.Hex() {
+		t.Fatalf("Checksum = %s, expected %s", got, addr.Hex())
+	}
+}
+
+// TestParse tests rejecting malformed and badly checksummed addresses
+func TestParse(t *testing.T) {
+	valid := checksumVectors[0][0]
+
+	// Unchecksummed input is accepted
+	for _, s := range []string{strings.ToLower(valid), "0x" + strings.ToUpper(valid[2:])} {
+		if HasChecksum(s) {
+			t.Fatalf("HasChecksum(%s) should be false", s)
+		}
+		if _, err := Parse(s, 0); err != nil {
+			t.Fatalf("Parse(%s) failed: %v", s, err)
+		}
+	}
+
+	// Changing the case of one letter breaks the checksum
+	flipped := valid[:4] + strings.ToLower(valid[4:5]) + valid[5:]
+	if _, err := Parse(flipped, 0); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("Expected ErrChecksum for %s, got %v", flipped, err)
+	}
+
+	// An EIP-55 checksum is not valid as an EIP-1191 checksum and vice versa
+	if _, err := Parse(valid, 30); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("Expected ErrChecksum for an EIP-55 address on chain 30, got %v", err)
+	}
+	if _, err := Parse(checksumVectors[30][0], 0); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("Expected ErrChecksum for an EIP-1191 address without chain, got %v", err)
+	}
+
+	for _, bad := range []string{"", "0x", valid[2:], valid[:41], valid + "0", "0X" + valid[2:], "0x" + strings.Repeat("g", 40)} {
+		if _, err := Parse(bad, 0); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("Expected ErrInvalid for %q, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,500 @@
+// Package eip712 implements EIP-712 typed structured data: parsing the
+// eth_signTypedData_v4 JSON format and computing type hashes, struct hashes, the
+// domain separator and the hash that is signed.
+package eip712
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
+	"github.com/metana-bootcamp/ethwallet/internal/address"
+)
+
+// DomainType is the name of the struct type of the domain
+const DomainType = "EIP712Domain"
+
+// domainFields are the fields an EIP712Domain may have, in the order EIP-712 defines
+var domainFields = []Field{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+	{Name: "salt", Type: "bytes32"},
+}
+
+// Field is a member of a struct type
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Types maps struct type names to their fields
+type Types map[string][]Field
+
+// TypedData is a typed structured data message in the eth_signTypedData_v4 format.
+//
+// Domain and Message hold JSON values: strings, json.Number or decimal and 0x
+// hex strings for integers, 0x hex strings for bytes, bools, []interface{} for
+// arrays and map[string]interface{} for structs. Messages built in Go may also
+// use *big.Int, Go integers, common.Address and []byte.
+type TypedData struct {
+	Types       Types                  `json:"types"`
+	PrimaryType string                 `json:"primaryType"`
+	Domain      map[string]interface{} `json:"domain"`
+	Message     map[string]interface{} `json:"message"`
+}
+
+// Parse parses and validates typed data in the eth_signTypedData_v4 JSON format.
+// When types has no EIP712Domain entry it is derived from the domain fields.
+func Parse(data []byte) (*TypedData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var td TypedData
+	if err := decoder.Decode(&td); err != nil {
+		return nil, fmt.Errorf("invalid typed data JSON: %w", err)
+	}
+	if td.Types == nil {
+		return nil, fmt.Errorf("typed data has no types")
+	}
+	if _, ok := td.Types[DomainType]; !ok {
+		fields, err := DomainFields(td.Domain)
+		if err != nil {
+			return nil, err
+		}
+		td.Types[DomainType] = fields
+	}
+
+	if err := td.Validate(); err != nil {
+		return nil, err
+	}
+	return &td, nil
+}
+
+// DomainFields returns the EIP712Domain fields for the keys present in domain,
+// in the order EIP-712 defines
+func DomainFields(domain map[string]interface{}) ([]Field, error) {
+	var fields []Field
+	for _, field := range domainFields {
+		if _, ok := domain[field.Name]; ok {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) != len(domain) {
+		for name := range domain {
+			if !isDomainField(name) {
+				return nil, fmt.Errorf("unknown domain field %q", name)
+			}
+		}
+	}
+	return fields, nil
+}
+
+// isDomainField reports whether name is one of the EIP712Domain fields
+func isDomainField(name string) bool {
+	for _, field := range domainFields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the primary type exists and that every field type is an
+// atomic or dynamic Solidity type, a defined struct or an array of them
+func (td *TypedData) Validate() error {
+	if td.PrimaryType == "" {
+		return fmt.Errorf("typed data has no primaryType")
+	}
+	if _, ok := td.Types[td.PrimaryType]; !ok {
+		return fmt.Errorf("primary type %q is not defined in types", td.PrimaryType)
+	}
+	if _, ok := td.Types[DomainType]; !ok {
+		return fmt.Errorf("typed data has no %s type", DomainType)
+	}
+	if td.Domain == nil {
+		return fmt.Errorf("typed data has no domain")
+	}
+	if td.Message == nil && td.PrimaryType != DomainType {
+		return fmt.Errorf("typed data has no message")
+	}
+
+	for name, fields := range td.Types {
+		if !isIdentifier(name) {
+			return fmt.Errorf("invalid type name %q", name)
+		}
+		seen := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			if !isIdentifier(field.Name) {
+				return fmt.Errorf("type %s: invalid field name %q", name, field.Name)
+			}
+			if seen[field.Name] {
+				return fmt.Errorf("type %s: duplicate field %q", name, field.Name)
+			}
+			seen[field.Name] = true
+			if err := td.checkType(field.Type); err != nil {
+				return fmt.Errorf("type %s, field %s: %w", name, field.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkType checks that typ is an array, a defined struct or an atomic type
+// written in its canonical form
+func (td *TypedData) checkType(typ string) error {
+	if elem, _, ok := splitArray(typ); ok {
+		return td.checkType(elem)
+	}
+	if strings.HasSuffix(typ, "]") {
+		return fmt.Errorf("invalid array type %q", typ)
+	}
+	if _, ok := td.Types[typ]; ok {
+		return nil
+	}
+	_, err := atomicType(typ)
+	return err
+}
+
+// atomicType parses a non-struct, non-array type
+func atomicType(typ string) (abi.Type, error) {
+	t, err := abi.ParseType(typ)
+	if err != nil || typ == "function" || t.String() != typ {
+		return abi.Type{}, fmt.Errorf("undefined type %q", typ)
+	}
+	if t.Kind == abi.ArrayKind || t.Kind == abi.SliceKind || t.Kind == abi.TupleKind {
+		return abi.Type{}, fmt.Errorf("undefined type %q", typ)
+	}
+	return t, nil
+}
+
+// splitArray splits an array type into its element type and length, -1 for a
+// dynamic array. ok is false when typ is not an array.
+func splitArray(typ string) (elem string, length int, ok bool) {
+	if !strings.HasSuffix(typ, "]") {
+		return "", 0, false
+	}
+	open := strings.LastIndex(typ, "[")
+	if open <= 0 {
+		return "", 0, false
+	}
+	size := typ[open+1 : len(typ)-1]
+	if size == "" {
+		return typ[:open], -1, true
+	}
+	n, err := strconv.Atoi(size)
+	if err != nil || n <= 0 || strconv.Itoa(n) != size {
+		return "", 0, false
+	}
+	return typ[:open], n, true
+}
+
+// baseType strips every array suffix from typ
+func baseType(typ string) string {
+	for {
+		elem, _, ok := splitArray(typ)
+		if !ok {
+			return typ
+		}
+		typ = elem
+	}
+}
+
+// isIdentifier reports whether s is a valid Solidity identifier
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c == '_' || c == '$', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// IsStruct reports whether typ names a struct type
+func (td *TypedData) IsStruct(typ string) bool {
+	_, ok := td.Types[typ]
+	return ok
+}
+
+// EncodeType returns the type encoding of a struct: its own signature followed by
+// the signatures of every struct it references, sorted by name, e.g.
+// "Mail(Person from,Person to,string contents)Person(string name,address wallet)"
+func (td *TypedData) EncodeType(name string) string {
+	deps := map[string]bool{}
+	td.dependencies(name, deps)
+	delete(deps, name)
+
+	names := make([]string, 0, len(deps))
+	for dep := range deps {
+		names = append(names, dep)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, typ := range append([]string{name}, names...) {
+		b.WriteString(typ)
+		b.WriteByte('(')
+		for i, field := range td.Types[typ] {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(field.Type + " " + field.Name)
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// dependencies adds name and every struct type it references, directly or not, to deps
+func (td *TypedData) dependencies(name string, deps map[string]bool) {
+	if deps[name] || !td.IsStruct(name) {
+		return
+	}
+	deps[name] = true
+	for _, field := range td.Types[name] {
+		td.dependencies(baseType(field.Type), deps)
+	}
+}
+
+// TypeHash returns keccak256(EncodeType(name))
+func (td *TypedData) TypeHash(name string) []byte {
+	return crypto.Keccak256([]byte(td.EncodeType(name)))
+}
+
+// HashStruct returns keccak256(typeHash || encodeData(data)) for a struct of type name
+func (td *TypedData) HashStruct(name string, data map[string]interface{}) ([]byte, error) {
+	return td.hashStruct(name, data, name)
+}
+
+// DomainSeparator returns the struct hash of the domain
+func (td *TypedData) DomainSeparator() ([]byte, error) {
+	return td.hashStruct(DomainType, td.Domain, "domain")
+}
+
+// MessageHash returns the struct hash of the message
+func (td *TypedData) MessageHash() ([]byte, error) {
+	return td.hashStruct(td.PrimaryType, td.Message, "message")
+}
+
+// Hash returns the hash that is signed:
+// keccak256("\x19\x01" || domainSeparator || hashStruct(message)).
+// When the primary type is EIP712Domain the message hash is left out.
+func (td *TypedData) Hash() ([]byte, error) {
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		return nil, err
+	}
+	data := append([]byte{0x19, 0x01}, domainSeparator...)
+	if td.PrimaryType != DomainType {
+		messageHash, err := td.MessageHash()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, messageHash...)
+	}
+	return crypto.Keccak256(data), nil
+}
+
+// ChainID returns the chainId of the domain, or nil when it has none or it is invalid
+func (td *TypedData) ChainID() *big.Int {
+	value, ok := td.Domain["chainId"]
+	if !ok {
+		return nil
+	}
+	n, err := toBigInt(value)
+	if err != nil {
+		return nil
+	}
+	return n
+}
+
+// hashStruct hashes a struct value; path locates it in error messages
+func (td *TypedData) hashStruct(name string, data map[string]interface{}, path string) ([]byte, error) {
+	fields, ok := td.Types[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: undefined type %q", path, name)
+	}
+
+	for key := range data {
+		if !hasField(fields, key) {
+			return nil, fmt.Errorf("%s: field %q is not part of type %s", path, key, name)
+		}
+	}
+
+	encoded := td.TypeHash(name)
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s: missing field %q", path, field.Name)
+		}
+		word, err := td.encodeValue(field.Type, value, path+"."+field.Name)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, word...)
+	}
+	return crypto.Keccak256(encoded), nil
+}
+
+// hasField reports whether fields has a field called name
+func hasField(fields []Field, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeValue encodes a value of type typ as the 32-byte word used in encodeData:
+// structs by their struct hash, arrays, bytes and strings by the keccak256 of
+// their contents and atomic values by their ABI encoding
+func (td *TypedData) encodeValue(typ string, value interface{}, path string) ([]byte, error) {
+	if elem, length, ok := splitArray(typ); ok {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: expected an array for %s, got %T", path, typ, value)
+		}
+		if length >= 0 && len(items) != length {
+			return nil, fmt.Errorf("%s: expected %d elements for %s, got %d", path, length, typ, len(items))
+		}
+		var encoded []byte
+		for i, item := range items {
+			word, err := td.encodeValue(elem, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, word...)
+		}
+		return crypto.Keccak256(encoded), nil
+	}
+
+	if td.IsStruct(typ) {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: expected an object for %s, got %T", path, typ, value)
+		}
+		return td.hashStruct(typ, data, path)
+	}
+
+	t, err := atomicType(typ)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	switch t.Kind {
+	case abi.StringKind:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected a string, got %T", path, value)
+		}
+		return crypto.Keccak256([]byte(s)), nil
+	case abi.BytesKind:
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return crypto.Keccak256(b), nil
+	}
+
+	v, err := atomicValue(t, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	word, err := abi.Arguments{{Type: t}}.Pack(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return word, nil
+}
+
+// atomicValue converts a JSON value to the Go value the ABI encoder takes for t
+func atomicValue(t abi.Type, value interface{}) (interface{}, error) {
+	switch t.Kind {
+	case abi.UintKind, abi.IntKind:
+		return toBigInt(value)
+	case abi.AddressKind:
+		if s, ok := value.(string); ok {
+			return address.Parse(s, 0)
+		}
+		return value, nil
+	case abi.BoolKind:
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("expected a bool, got %T", value)
+		}
+		return value, nil
+	case abi.FixedBytesKind:
+		return toBytes(value)
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// toBigInt converts a JSON number, a decimal or 0x hex string or a Go integer
+func toBigInt(value interface{}) (*big.Int, error) {
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("nil integer")
+		}
+		return v, nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	default:
+		return nil, fmt.Errorf("expected an integer, got %T", value)
+	}
+
+	n, ok := new(big.Int), false
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		n, ok = n.SetString(s[2:], 16)
+	} else {
+		n, ok = n.SetString(s, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
+
+// toBytes converts a 0x hex string or a Go byte slice or array
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.HasPrefix(v, "0x") {
+			return nil, fmt.Errorf("bytes %q must start with 0x", v)
+		}
+		b, err := hex.DecodeString(v[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex bytes %q: %v", v, err)
+		}
+		return b, nil
+	case []byte:
+		return v, nil
+	case common.Hash:
+		return v.Bytes(), nil
+	}
+	return nil, fmt.Errorf("expected 0x hex bytes, got %T", value)
+}
@@ -0,0 +1,201 @@
+package eip712
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// mailJSON is the example from the EIP-712 specification
+const mailJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+// TestMailExample checks every intermediate hash of the EIP-712 specification example
+func TestMailExample(t *testing.T) {
+	td, err := Parse([]byte(mailJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if got := td.EncodeType("Mail"); got != "Mail(Person from,Person to,string contents)Person(string name,address wallet)" {
+		t.Fatalf("Unexpected type encoding: %s", got)
+	}
+	if got := hex.EncodeToString(td.TypeHash("Mail")); got != "a0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2" {
+		t.Fatalf("Unexpected type hash: %s", got)
+	}
+
+	checks := []struct {
+		name string
+		hash func() ([]byte, error)
+		want string
+	}{
+		{"domain separator", td.DomainSeparator, "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"},
+		{"message hash", td.MessageHash, "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"},
+		{"signing hash", td.Hash, "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"},
+	}
+	for _, check := range checks {
+		hash, err := check.hash()
+		if err != nil {
+			t.Fatalf("Failed to compute the %s: %v", check.name, err)
+		}
+		if got := hex.EncodeToString(hash); got != check.want {
+			t.Fatalf("Unexpected %s: %s", check.name, got)
+		}
+	}
+
+	if chainID := td.ChainID(); chainID == nil || chainID.Int64() != 1 {
+		t.Fatalf("Unexpected chain ID: %v", chainID)
+	}
+}
+
+// TestDerivedDomainType tests that a missing EIP712Domain type is derived from the domain
+func TestDerivedDomainType(t *testing.T) {
+	withoutDomainType := strings.Replace(mailJSON, `"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],`, "", 1)
+	// Integers may also be strings, in decimal or hex
+	withoutDomainType = strings.Replace(withoutDomainType, `"chainId": 1`, `"chainId": "0x1"`, 1)
+
+	td, err := Parse([]byte(withoutDomainType))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	hash, err := td.Hash()
+	if err != nil {
+		t.Fatalf("Failed to hash: %v", err)
+	}
+	if got := hex.EncodeToString(hash); got != "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2" {
+		t.Fatalf("Unexpected signing hash: %s", got)
+	}
+}
+
+// TestArrays tests that arrays of structs and atomic values are hashed as the
+// keccak256 of their concatenated encodings
+func TestArrays(t *testing.T) {
+	td, err := Parse([]byte(`{
+		"types": {
+			"Person": [{"name": "name", "type": "string"}, {"name": "wallets", "type": "address[]"}],
+			"Group": [{"name": "members", "type": "Person[2]"}, {"name": "tags", "type": "bytes32[]"}]
+		},
+		"primaryType": "Group",
+		"domain": {"name": "Groups"},
+		"message": {
+			"members": [
+				{"name": "Cow", "wallets": ["0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"]},
+				{"name": "Bob", "wallets": []}
+			],
+			"tags": ["0x0000000000000000000000000000000000000000000000000000000000000001"]
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if got := td.EncodeType("Group"); got != "Group(Person[2] members,bytes32[] tags)Person(string name,address[] wallets)" {
+		t.Fatalf("Unexpected type encoding: %s", got)
+	}
+
+	word := func(s string) []byte {
+		b, _ := hex.DecodeString(strings.Repeat("0", 64-len(s)) + s)
+		return b
+	}
+	person := func(name string, wallets ...string) []byte {
+		var encoded []byte
+		for _, wallet := range wallets {
+			encoded = append(encoded, word(wallet)...)
+		}
+		return crypto.Keccak256(td.TypeHash("Person"), crypto.Keccak256([]byte(name)), crypto.Keccak256(encoded))
+	}
+	members := crypto.Keccak256(person("Cow", "cd2a3d9f938e13cd947ec05abc7fe734df8dd826"), person("Bob"))
+	want := crypto.Keccak256(td.TypeHash("Group"), members, crypto.Keccak256(word("1")))
+
+	got, err := td.MessageHash()
+	if err != nil {
+		t.Fatalf("Failed to hash: %v", err)
+	}
+	if hex.EncodeToString(got) != hex.EncodeToString(want) {
+		t.Fatalf("Unexpected message hash: %x, expected %x", got, want)
+	}
+}
+
+// TestInvalidTypedData tests that malformed types and values are rejected
+func TestInvalidTypedData(t *testing.T) {
+	parseErrors := map[string]string{
+		"undefined type":     `"Mail": [{"name": "to", "type": "Persn"}]`,
+		"non-canonical type": `"Mail": [{"name": "amount", "type": "uint"}]`,
+		"tuple type":         `"Mail": [{"name": "pair", "type": "(uint256,uint256)"}]`,
+		"duplicate field":    `"Mail": [{"name": "a", "type": "bool"}, {"name": "a", "type": "bool"}]`,
+		"invalid field name": `"Mail": [{"name": "a b", "type": "bool"}]`,
+	}
+	for name, mail := range parseErrors {
+		data := `{"types": {` + mail + `}, "primaryType": "Mail", "domain": {}, "message": {}}`
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Fatalf("%s: expected a parse error", name)
+		}
+	}
+	if _, err := Parse([]byte(`{"types": {"Mail": []}, "primaryType": "Mail", "domain": {"chain": 1}, "message": {}}`)); err == nil {
+		t.Fatalf("Expected an error for an unknown domain field")
+	}
+
+	hashErrors := []struct {
+		name     string
+		old, new string
+	}{
+		{"missing field", `,
+		"contents": "Hello, Bob!"`, ""},
+		{"extra field", `"contents": "Hello, Bob!"`, `"contents": "Hello, Bob!", "bcc": "Eve"`},
+		{"number as a string", `"contents": "Hello, Bob!"`, `"contents": 42`},
+		{"string as a struct", `"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"}`, `"to": "Bob"`},
+		{"bad checksum", "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB", "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbb"},
+		{"address without 0x", "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB", "bBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		{"integer out of range", `"chainId": 1`, `"chainId": "0x1` + strings.Repeat("0", 64) + `"`},
+		{"negative uint", `"chainId": 1`, `"chainId": -1`},
+	}
+	for _, tc := range hashErrors {
+		data := strings.Replace(mailJSON, tc.old, tc.new, 1)
+		if data == mailJSON {
+			t.Fatalf("%s: replacement not found", tc.name)
+		}
+		td, err := Parse([]byte(data))
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", tc.name, err)
+		}
+		if _, err := td.Hash(); err == nil {
+			t.Fatalf("%s: expected a hashing error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// AccessTuple is one EIP-2930 access list entry: an address and the storage slots it touches
+type AccessTuple struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []common.Hash  `json:"storageKeys"`
+}
+
+// MarshalJSON always writes storageKeys as an array; nodes reject null
+func (t AccessTuple) MarshalJSON() ([]byte, error) {
+	type tuple AccessTuple
+	if t.StorageKeys == nil {
+		t.StorageKeys = []common.Hash{}
+	}
+	return json.Marshal(tuple(t))
+}
+
+// AccessList is an EIP-2930 access list
+type AccessList []AccessTuple
+
+// StorageKeyCount returns the total number of storage keys in the list
+func (al AccessList) StorageKeyCount() int {
+	count := 0
+	for _, tuple := range al {
+		count += len(tuple.StorageKeys)
+	}
+	return count
+}
+
+// LoadAccessList reads an access list from a JSON file. The file holds either the
+// list itself or an eth_createAccessList result with an "accessList" field.
+func LoadAccessList(path string) (AccessList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access list: %v", err)
+	}
+
+	var list AccessList
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var result struct {
+			AccessList AccessList `json:"accessList"`
+		}
+		if err := json.Unmarshal(trimmed, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse access list %s: %v", path, err)
+		}
+		list = result.AccessList
+	} else if err := json.Unmarshal(trimmed, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse access list %s: %v", path, err)
+	}
+
+	// An empty list still selects a typed transaction
+	if list == nil {
+		list = AccessList{}
+	}
+	return list, nil
+}
+
+// AccessListResult is the result of eth_createAccessList
+type AccessListResult struct {
+	AccessList AccessList
+	GasUsed    uint64 // gas used by the call when the access list is applied
+}
+
+// CreateAccessList asks the node to generate the access list for a call from the pending state.
+// An empty to generates it for a contract creation.
+func (c *Client) CreateAccessList(ctx context.Context, from common.Address, to string, value *big.Int, data []byte) (*AccessListResult, error) {
+	msg := map[string]string{
+		"from":  from.Hex(),
+		"value": fmt.Sprintf("0x%x", value),
+	}
+	if to != "" {
+		msg["to"] = to
+	}
+	if len(data) > 0 {
+		msg["data"] = fmt.Sprintf("0x%x", data)
+	}
+
+	result, err := c.Call(ctx, "eth_createAccessList", []interface{}{msg, "pending"})
+	if err != nil {
+		return nil, fmt.Errorf("error creating access list: %w", err)
+	}
+
+	var raw struct {
+		AccessList AccessList `json:"accessList"`
+		GasUsed    string     `json:"gasUsed"`
+		Error      string     `json:"error"`
+	}
+	if err := json.Unmarshal(result, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse access list: %v", err)
+	}
+	if raw.Error != "" {
+		return nil, fmt.Errorf("error creating access list: %s", raw.Error)
+	}
+
+	gasUsed, err := hexToUint64(raw.GasUsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse access list gas: %v", err)
+	}
+
+	return &AccessListResult{AccessList: raw.AccessList, GasUsed: gasUsed}, nil
+}
+
+// EIP-2930 access list transaction (type 1)
+type TX2930 struct {
+	ChainID    *big.Int   // S
+	Nonce      uint64     // S
+	GasPrice   *big.Int   // S
+	GasLimit   uint64     // S
+	To         *[20]byte  // S  (pointer lets RLP encode nil for contract creation)
+	Value      *big.Int   // S
+	Data       []byte     // S
+	AccessList AccessList // S
+	// after signing:
+	V uint8
+	R *big.Int
+	S *big.Int
+}
+
+// PayloadRLP returns the unsigned payload (no V,R,S) RLP-encoded
+func (t *TX2930) PayloadRLP() ([]byte, error) {
+	type unsigned struct {
+		ChainID    *big.Int
+		Nonce      uint64
+		GasPrice   *big.Int
+		GasLimit   uint64
+		To         *[20]byte
+		Value      *big.Int
+		Data       []byte
+		AccessList AccessList
+	}
+	return rlp.EncodeToBytes(unsigned{
+		t.ChainID, t.Nonce, t.GasPrice, t.GasLimit,
+		t.To, t.Value, t.Data, t.AccessList,
+	})
+}
+
+// Sign fills in V,R,S and returns the signed raw tx bytes (0x01||RLP)
+func (t *TX2930) Sign(priv *ecdsa.PrivateKey) ([]byte, error) {
+	payload, err := t.PayloadRLP()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	hash := Keccak256(append([]byte{0x01}, payload...))
+
+	// Sign the hash using Ethereum's crypto package
+	signature, err := crypto.Sign(hash, priv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	t.V = signature[64] // y-parity, 0 or 1
+	t.R = new(big.Int).SetBytes(signature[0:32])
+	t.S = new(big.Int).SetBytes(signature[32:64])
+
+	raw, err := rlp.EncodeToBytes(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signed transaction: %w", err)
+	}
+
+	return append([]byte{0x01}, raw...), nil
+}
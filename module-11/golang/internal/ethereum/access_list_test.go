@@ -0,0 +1,178 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testAccessList is the access list used by the signed transaction vectors
+func testAccessList() AccessList {
+	return AccessList{
+		{
+			Address: common.HexToAddress("0x1c7D4B196Cb0C7B01d743Fbc6116a902379C7238"),
+			StorageKeys: []common.Hash{
+				common.HexToHash("0x01"),
+				common.HexToHash("0x9c04773acff4c5c42718bd0120c72761f458e43068a3961eb935577d1ed4effb"),
+			},
+		},
+		{Address: common.HexToAddress("0x0000000000000000000000000000000000000004")},
+	}
+}
+
+// TestAccessListTxVectors checks type-1 and type-2 access list encodings against known signed transactions
+func TestAccessListTxVectors(t *testing.T) {
+	key, err := crypto.HexToECDSA(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Invalid key: %v", err)
+	}
+
+	type1 := &TX2930{
+		ChainID: big.NewInt(11155111), Nonce: 2, GasPrice: big.NewInt(2_000_000_000), GasLimit: 30000,
+		To: toPtr(t, vectorTo), Value: big.NewInt(1), AccessList: testAccessList(),
+	}
+	raw, err := type1.Sign(key)
+	if err != nil {
+		t.Fatalf("Failed to sign type-1 transaction: %v", err)
+	}
+	want := "01f8db83aa36a702847735940082753094de9ca654ae5a3673d894eba15b63603fa00f85040180f872f859941c7d4b196cb0c7b01d743fbc6116a902379c7238f842a00000000000000000000000000000000000000000000000000000000000000001a09c04773acff4c5c42718bd0120c72761f458e43068a3961eb935577d1ed4effbd6940000000000000000000000000000000000000004c080a05cdec978e4c2906c506d10fa67aca868a1244f70d05d2dd0a0bb1071a7537c42a06d08aa55d49b607d5945a1aafb89c0b1b1f53d8abea30612bfce3fa5ec745f12"
+	if got := hex.EncodeToString(raw); got != want {
+		t.Fatalf("Type-1 transaction mismatch\n got: %s\nwant: %s", got, want)
+	}
+	if got := fmt.Sprintf("0x%x", Keccak256(raw)); got != "0xc6b42c234114400902447c0775d2d2b96d0a38cefa98f2b505ba73cc81bea05d" {
+		t.Fatalf("Type-1 hash mismatch: %s", got)
+	}
+
+	type2 := &TX1559{
+		ChainID: big.NewInt(11155111), Nonce: 3,
+		MaxPriorityFeePerGas: big.NewInt(1_500_000_000), MaxFeePerGas: big.NewInt(31_500_000_000),
+		GasLimit: 30000, To: toPtr(t, vectorTo), Value: big.NewInt(1), AccessList: testAccessList(),
+	}
+	raw, err = type2.Sign(key)
+	if err != nil {
+		t.Fatalf("Failed to sign type-2 transaction: %v", err)
+	}
+	want = "02f8e183aa36a7038459682f008507558bdb0082753094de9ca654ae5a3673d894eba15b63603fa00f85040180f872f859941c7d4b196cb0c7b01d743fbc6116a902379c7238f842a00000000000000000000000000000000000000000000000000000000000000001a09c04773acff4c5c42718bd0120c72761f458e43068a3961eb935577d1ed4effbd6940000000000000000000000000000000000000004c001a06c245bb1270ff92ba35c6b547233fe61d532d140c82cdf6ed9ef6bb30a5ddd63a05eea8b7642536271369141c833972192f404a3049682630d79faa5f3733d0e10"
+	if got := hex.EncodeToString(raw); got != want {
+		t.Fatalf("Type-2 transaction mismatch\n got: %s\nwant: %s", got, want)
+	}
+	if got := fmt.Sprintf("0x%x", Keccak256(raw)); got != "0x374180dfbbbe5bcb859c621ddb8366d914881be2874860e67473d0a1db50a2cb" {
+		t.Fatalf("Type-2 hash mismatch: %s", got)
+	}
+}
+
+// TestLoadAccessList tests reading both a bare list and an eth_createAccessList result
+func TestLoadAccessList(t *testing.T) {
+	dir := t.TempDir()
+
+	bare := filepath.Join(dir, "list.json")
+	os.WriteFile(bare, []byte(`[{"address":"0x1c7D4B196Cb0C7B01d743Fbc6116a902379C7238","storageKeys":["0x0000000000000000000000000000000000000000000000000000000000000001"]}]`), 0600)
+	list, err := LoadAccessList(bare)
+	if err != nil {
+		t.Fatalf("Failed to load bare list: %v", err)
+	}
+	if len(list) != 1 || list.StorageKeyCount() != 1 || list[0].StorageKeys[0] != common.HexToHash("0x01") {
+		t.Fatalf("Unexpected access list: %+v", list)
+	}
+
+	result := filepath.Join(dir, "result.json")
+	os.WriteFile(result, []byte(`{"accessList":[{"address":"0x0000000000000000000000000000000000000004","storageKeys":[]}],"gasUsed":"0x5208"}`), 0600)
+	if list, err = LoadAccessList(result); err != nil || len(list) != 1 {
+		t.Fatalf("Failed to load createAccessList result: %+v (%v)", list, err)
+	}
+
+	empty := filepath.Join(dir, "empty.json")
+	os.WriteFile(empty, []byte(`{}`), 0600)
+	if list, err = LoadAccessList(empty); err != nil || list == nil || len(list) != 0 {
+		t.Fatalf("Expected an empty non-nil list, got %#v (%v)", list, err)
+	}
+
+	// Tuples without storage keys are written as empty arrays, never null
+	encoded, _ := json.Marshal(AccessList{{Address: common.HexToAddress("0x04")}})
+	if !strings.Contains(string(encoded), `"storageKeys":[]`) {
+		t.Fatalf("Unexpected JSON encoding: %s", encoded)
+	}
+}
+
+// TestCreateAccessListAndSend tests eth_createAccessList and sending type-1 and type-2 transactions with the list
+func TestCreateAccessListAndSend(t *testing.T) {
+	var rawTxs [][]byte
+	var estimateParams []json.RawMessage
+
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_createAccessList":
+			return map[string]interface{}{"accessList": testAccessList(), "gasUsed": "0x7530"}, nil
+		case "eth_chainId":
+			return "0xaa36a7", nil
+		case "eth_getTransactionCount":
+			return "0x2", nil
+		case "eth_estimateGas":
+			estimateParams = params
+			return "0x61a8", nil
+		case "eth_getBlockByNumber":
+			return map[string]string{"baseFeePerGas": "0x3b9aca00"}, nil
+		case "eth_feeHistory":
+			return mockFeeHistory(), nil
+		case "eth_gasPrice":
+			return "0x77359400", nil
+		case "eth_sendRawTransaction":
+			var rawHex string
+			json.Unmarshal(params[0], &rawHex)
+			raw, _ := HexDecode(rawHex)
+			rawTxs = append(rawTxs, raw)
+			return fmt.Sprintf("0x%x", Keccak256(raw)), nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+
+	result, err := client.CreateAccessList(ctx, keyPair.Address, vectorTo, big.NewInt(1), nil)
+	if err != nil {
+		t.Fatalf("Failed to create access list: %v", err)
+	}
+	if len(result.AccessList) != 2 || result.AccessList.StorageKeyCount() != 2 || result.GasUsed != 30000 {
+		t.Fatalf("Unexpected access list result: %+v", result)
+	}
+
+	if _, err := client.SendTransaction(ctx, keyPair, vectorTo, big.NewInt(1), WithAccessList(result.AccessList)); err != nil {
+		t.Fatalf("Failed to send type-1 transaction: %v", err)
+	}
+	if _, err := client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(1), nil, WithAccessList(result.AccessList)); err != nil {
+		t.Fatalf("Failed to send type-2 transaction: %v", err)
+	}
+
+	if len(rawTxs) != 2 || rawTxs[0][0] != 0x01 || rawTxs[1][0] != 0x02 {
+		t.Fatalf("Expected a type-1 then a type-2 transaction, got %d transactions", len(rawTxs))
+	}
+
+	// The gas estimate is made with the access list applied
+	if len(estimateParams) == 0 || !strings.Contains(string(estimateParams[0]), "0x1c7d4b196cb0c7b01d743fbc6116a902379c7238") {
+		t.Fatalf("Gas estimate did not include the access list: %s", estimateParams)
+	}
+
+	// The signed type-1 transaction carries the list
+	want := TX2930{
+		ChainID: big.NewInt(11155111), Nonce: 2, GasPrice: big.NewInt(2_000_000_000), GasLimit: 30000,
+		To: toPtr(t, vectorTo), Value: big.NewInt(1), Data: []byte{}, AccessList: result.AccessList,
+	}
+	expected, _ := want.Sign(keyPair.PrivateKey)
+	if hex.EncodeToString(rawTxs[0]) != hex.EncodeToString(expected) {
+		t.Fatalf("Type-1 transaction mismatch\n got: %x\nwant: %x", rawTxs[0], expected)
+	}
+}
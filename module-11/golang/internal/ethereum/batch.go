@@ -0,0 +1,219 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BatchElem is a single call inside a JSON-RPC batch. After BatchCall returns,
+// either Result or Error is set for every element.
+type BatchElem struct {
+	Method string
+	Params []interface{}
+	Result json.RawMessage
+	Error  error
+}
+
+// BatchCall sends several JSON-RPC calls in a single HTTP request and matches the
+// responses back to the calls by ID. Per-call failures are reported in each
+// element's Error; the returned error is only set when the batch as a whole failed.
+// Batches are retried and failed over like single calls but bypass the middleware chain.
+func (c *Client) BatchCall(ctx context.Context, elems []BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	reqs := make([]rpcRequest, len(elems))
+	byID := make(map[int]int, len(elems))
+	for i := range elems {
+		params := elems[i].Params
+		if params == nil {
+			params = []interface{}{}
+		}
+
+		id := int(c.nextID.Add(1))
+		reqs[i] = rpcRequest{JsonRPC: "2.0", Method: elems[i].Method, Params: params, ID: id}
+		byID[id] = i
+
+		elems[i].Result = nil
+		elems[i].Error = nil
+	}
+
+	reqBody, err := json.Marshal(reqs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch request: %v", err)
+	}
+
+	var resps []rpcResponse
+	err = c.withRetry(ctx, func(url string, attempt int) error {
+		body, err := c.post(ctx, url, reqBody)
+		if err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(body, &resps); err != nil {
+			// Some nodes answer a rejected batch with a single error object
+			var single rpcResponse
+			if json.Unmarshal(body, &single) == nil && single.Error != nil {
+				return &RPCError{Code: single.Error.Code, Message: single.Error.Message, Data: single.Error.Data}
+			}
+			return fmt.Errorf("failed to parse batch response: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	answered := make([]bool, len(elems))
+	for _, resp := range resps {
+		i, ok := byID[resp.ID]
+		if !ok || answered[i] {
+			continue
+		}
+		answered[i] = true
+
+		if resp.Error != nil {
+			elems[i].Error = &RPCError{Code: resp.Error.Code, Message: resp.Error.Message, Data: resp.Error.Data}
+		} else {
+			elems[i].Result = resp.Result
+		}
+	}
+
+	for i := range elems {
+		if !answered[i] {
+			elems[i].Error = fmt.Errorf("no response for batched %s call", elems[i].Method)
+		}
+	}
+
+	return nil
+}
+
+// AccountInfo holds the balance and pending nonce of an address
+type AccountInfo struct {
+	Address common.Address
+	Balance *big.Int
+	Nonce   uint64
+	Err     error
+}
+
+// GetAccounts fetches balances and pending nonces for many addresses in one batch
+func (c *Client) GetAccounts(ctx context.Context, addresses []common.Address) ([]AccountInfo, error) {
+	elems := make([]BatchElem, 0, 2*len(addresses))
+	for _, address := range addresses {
+		elems = append(elems,
+			BatchElem{Method: "eth_getBalance", Params: []interface{}{address.Hex(), "latest"}},
+			BatchElem{Method: "eth_getTransactionCount", Params: []interface{}{address.Hex(), "pending"}},
+		)
+	}
+
+	if err := c.BatchCall(ctx, elems); err != nil {
+		return nil, fmt.Errorf("error checking balances: %w", err)
+	}
+
+	accounts := make([]AccountInfo, len(addresses))
+	for i, address := range addresses {
+		balanceElem, nonceElem := elems[2*i], elems[2*i+1]
+		account := AccountInfo{Address: address}
+
+		switch {
+		case balanceElem.Error != nil:
+			account.Err = fmt.Errorf("error checking balance: %w", balanceElem.Error)
+		case nonceElem.Error != nil:
+			account.Err = fmt.Errorf("error getting nonce: %w", nonceElem.Error)
+		default:
+			var err error
+			if account.Balance, err = HexToBig(string(balanceElem.Result)); err != nil {
+				account.Err = err
+			} else if account.Nonce, err = hexToUint64(string(nonceElem.Result)); err != nil {
+				account.Err = err
+			}
+		}
+
+		accounts[i] = account
+	}
+
+	return accounts, nil
+}
+
+// TxPreflight holds the network values needed to build a transaction
+type TxPreflight struct {
+	ChainID  *big.Int
+	Nonce    uint64
+	GasLimit uint64
+	BaseFee  *big.Int // nil when the latest block has none or it could not be read
+}
+
+// Preflight fetches the chain ID, pending nonce, gas estimate and base fee for a
+// transaction in a single batch. An empty to estimates a contract creation.
+// BaseFee is left nil on networks without EIP-1559.
+// It fails with ErrChainIDMismatch when the node serves a chain other than the
+// one set with WithChainID.
+func (c *Client) Preflight(ctx context.Context, from common.Address, to string, value *big.Int, opts ...SendOption) (*TxPreflight, error) {
+	o := applySendOptions(opts)
+
+	// The gas estimate includes calldata and the access list, which change the intrinsic gas
+	msg := map[string]interface{}{
+		"from":  from.Hex(),
+		"value": fmt.Sprintf("0x%x", value),
+	}
+	if to != "" {
+		msg["to"] = to
+	}
+	if len(o.data) > 0 {
+		msg["data"] = fmt.Sprintf("0x%x", o.data)
+	}
+	if o.accessList != nil {
+		msg["accessList"] = o.accessList
+	}
+
+	elems := []BatchElem{
+		{Method: "eth_chainId"},
+		{Method: "eth_getTransactionCount", Params: []interface{}{from.Hex(), "pending"}},
+		{Method: "eth_estimateGas", Params: []interface{}{msg}},
+		{Method: "eth_getBlockByNumber", Params: []interface{}{"latest", false}},
+	}
+
+	if err := c.BatchCall(ctx, elems); err != nil {
+		return nil, fmt.Errorf("error preparing transaction: %w", err)
+	}
+
+	var p TxPreflight
+	var err error
+
+	if elems[0].Error != nil {
+		return nil, fmt.Errorf("error getting chainID: %w", elems[0].Error)
+	}
+	if p.ChainID, err = HexToBig(string(elems[0].Result)); err != nil {
+		return nil, fmt.Errorf("error getting chainID: %w", err)
+	}
+	if err := c.checkChainID(p.ChainID.Uint64()); err != nil {
+		return nil, err
+	}
+
+	if elems[1].Error != nil {
+		return nil, fmt.Errorf("error getting nonce: %w", elems[1].Error)
+	}
+	if p.Nonce, err = hexToUint64(string(elems[1].Result)); err != nil {
+		return nil, fmt.Errorf("error getting nonce: %w", err)
+	}
+
+	if elems[2].Error != nil {
+		return nil, fmt.Errorf("error estimating gas: %w", elems[2].Error)
+	}
+	if p.GasLimit, err = parseGasEstimate(elems[2].Result); err != nil {
+		return nil, err
+	}
+
+	if elems[3].Error == nil {
+		if baseFee, err := parseBaseFee(elems[3].Result); err == nil {
+			p.BaseFee = baseFee
+		}
+	}
+
+	return &p, nil
+}
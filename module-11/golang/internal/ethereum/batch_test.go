@@ -0,0 +1,152 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestBatchCallMatchesByID tests that out-of-order responses are matched to their calls
+func TestBatchCallMatchesByID(t *testing.T) {
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_chainId":
+			return "0xaa36a7", nil
+		case "eth_blockNumber":
+			return "0x10", nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "the method " + method + " does not exist"}
+	})
+
+	elems := []BatchElem{
+		{Method: "eth_chainId"},
+		{Method: "eth_blockNumber"},
+		{Method: "eth_bogus"},
+	}
+	if err := NewClient(rpc.URL).BatchCall(context.Background(), elems); err != nil {
+		t.Fatalf("Batch call failed: %v", err)
+	}
+
+	if string(elems[0].Result) != `"0xaa36a7"` || elems[0].Error != nil {
+		t.Fatalf("Unexpected chain ID result: %s (%v)", elems[0].Result, elems[0].Error)
+	}
+	if string(elems[1].Result) != `"0x10"` || elems[1].Error != nil {
+		t.Fatalf("Unexpected block number result: %s (%v)", elems[1].Result, elems[1].Error)
+	}
+
+	// Per-item errors are surfaced without failing the whole batch
+	var rpcErr *RPCError
+	if !errors.As(elems[2].Error, &rpcErr) || rpcErr.Code != -32601 {
+		t.Fatalf("Expected method-not-found error for eth_bogus, got %v", elems[2].Error)
+	}
+
+	if n := rpc.requestCount(); n != 1 {
+		t.Fatalf("Expected 1 HTTP request, got %d", n)
+	}
+}
+
+// TestGetAccountsBatch tests fetching balances and nonces for several addresses at once
+func TestGetAccountsBatch(t *testing.T) {
+	other := common.HexToAddress("0xde9ca654aE5a3673d894eba15b63603Fa00F8504")
+
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		var addr string
+		json.Unmarshal(params[0], &addr)
+		isTest := common.HexToAddress(addr) == HexToAddress(testAddress)
+
+		switch method {
+		case "eth_getBalance":
+			if isTest {
+				return "0xde0b6b3a7640000", nil // 1 ETH
+			}
+			return "0x0", nil
+		case "eth_getTransactionCount":
+			if isTest {
+				return "0x7", nil
+			}
+			return nil, &mockRPCError{Code: -32000, Message: "header not found"}
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	accounts, err := NewClient(rpc.URL).GetAccounts(context.Background(), []common.Address{HexToAddress(testAddress), other})
+	if err != nil {
+		t.Fatalf("Failed to get accounts: %v", err)
+	}
+
+	if accounts[0].Err != nil || accounts[0].Balance.String() != "1000000000000000000" || accounts[0].Nonce != 7 {
+		t.Fatalf("Unexpected first account: %+v", accounts[0])
+	}
+	if accounts[1].Err == nil {
+		t.Fatal("Expected an error for the second account's nonce")
+	}
+	if n := rpc.requestCount(); n != 1 {
+		t.Fatalf("Expected 1 HTTP request, got %d", n)
+	}
+}
+
+// TestPreflightBatch tests that transaction pre-flight values are fetched in one request
+func TestPreflightBatch(t *testing.T) {
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_chainId":
+			return "0xaa36a7", nil
+		case "eth_getTransactionCount":
+			return "0x3", nil
+		case "eth_estimateGas":
+			return "0x5208", nil
+		case "eth_getBlockByNumber":
+			return map[string]string{"baseFeePerGas": "0x3b9aca00"}, nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	p, err := NewClient(rpc.URL).Preflight(context.Background(), HexToAddress(testAddress), testAddress, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Preflight failed: %v", err)
+	}
+
+	if p.ChainID.Int64() != 11155111 || p.Nonce != 3 || p.BaseFee.Int64() != 1_000_000_000 {
+		t.Fatalf("Unexpected preflight values: %+v", p)
+	}
+	// 21000 plus the 20% buffer
+	if p.GasLimit != 25200 {
+		t.Fatalf("Gas limit %d, expected 25200", p.GasLimit)
+	}
+	if n := rpc.requestCount(); n != 1 {
+		t.Fatalf("Expected 1 HTTP request, got %d", n)
+	}
+}
+
+// TestPreflightNoBaseFee tests that a block without a base fee leaves BaseFee nil
+func TestPreflightNoBaseFee(t *testing.T) {
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_chainId":
+			return "0x38", nil
+		case "eth_getTransactionCount":
+			return "0x3", nil
+		case "eth_estimateGas":
+			return "0x5208", nil
+		case "eth_getBlockByNumber":
+			return map[string]string{"number": "0x10"}, nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	client := NewClient(rpc.URL)
+
+	p, err := client.Preflight(context.Background(), HexToAddress(testAddress), testAddress, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Preflight failed: %v", err)
+	}
+	if p.BaseFee != nil {
+		t.Fatalf("Expected no base fee, got %s", p.BaseFee)
+	}
+	if _, err := client.GetBaseFee(context.Background()); !errors.Is(err, ErrNoBaseFee) {
+		t.Fatalf("Expected ErrNoBaseFee, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRPCTimeout is the HTTP timeout used when none is configured
+const DefaultRPCTimeout = 10 * time.Second
+
+// sharedTransport is reused by every client that doesn't supply its own
+// http.Client, so connections are pooled across clients
+var sharedTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+// RPCHandler performs a single JSON-RPC call
+type RPCHandler func(ctx context.Context, method string, params []interface{}) (json.RawMessage, error)
+
+// Middleware wraps an RPCHandler, e.g. for logging, metrics or caching
+type Middleware func(next RPCHandler) RPCHandler
+
+// RPCError is an error returned by the node in a JSON-RPC response
+type RPCError struct {
+	Code    int
+	Message string
+	Data    json.RawMessage
+}
+
+// Error implements the error interface
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
+// Client is a JSON-RPC client for an Ethereum node. Requests go to the primary
+// URL and fail over to fallback URLs, with retries for transient errors.
+type Client struct {
+	url        string
+	endpoints  *endpointPool
+	retry      RetryPolicy
+	chainID    uint64
+	httpClient *http.Client
+	headers    http.Header
+	middleware []Middleware
+	handler    RPCHandler
+	nextID     atomic.Uint64
+}
+
+// ClientOption configures a Client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the underlying HTTP client (and therefore its transport and timeout)
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the per-request HTTP timeout
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Timeout = timeout
+		c.httpClient = &hc
+	}
+}
+
+// WithHeader adds an HTTP header to every request
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		c.headers.Set(key, value)
+	}
+}
+
+// WithBearerToken sets an Authorization: Bearer header on every request
+func WithBearerToken(token string) ClientOption {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithMiddleware appends middleware to the call chain. The first middleware is the outermost.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// NewClient creates a JSON-RPC client for the given node URL
+func NewClient(url string, opts ...ClientOption) *Client {
+	c := &Client{
+		url:        url,
+		endpoints:  &endpointPool{},
+		retry:      DefaultRetryPolicy(),
+		httpClient: &http.Client{Transport: sharedTransport, Timeout: DefaultRPCTimeout},
+		headers:    make(http.Header),
+	}
+	c.endpoints.add(url)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// Build the middleware chain around the HTTP transport
+	handler := c.roundTrip
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		handler = c.middleware[i](handler)
+	}
+	c.handler = handler
+
+	return c
+}
+
+// URL returns the primary node URL the client talks to
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Call sends a JSON-RPC request through the middleware chain
+func (c *Client) Call(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	return c.handler(ctx, method, params)
+}
+
+// roundTrip performs the HTTP request for a single JSON-RPC call
+func (c *Client) roundTrip(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	id := int(c.nextID.Add(1))
+
+	// Create request body
+	reqBody, err := json.Marshal(rpcRequest{
+		JsonRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	var result json.RawMessage
+	err = c.withRetry(ctx, func(url string, attempt int) error {
+		body, err := c.post(ctx, url, reqBody)
+		if err != nil {
+			return err
+		}
+
+		// Parse response
+		var rpcResp rpcResponse
+		if err := json.Unmarshal(body, &rpcResp); err != nil {
+			return fmt.Errorf("failed to parse response: %v", err)
+		}
+
+		// Check for RPC error
+		if rpcResp.Error != nil {
+			rpcErr := &RPCError{Code: rpcResp.Error.Code, Message: rpcResp.Error.Message, Data: rpcResp.Error.Data}
+
+			// A re-sent raw transaction may already be known to the node
+			if method == "eth_sendRawTransaction" && attempt > 0 {
+				result, err = resolveRawTxRetry(params, rpcErr)
+				return err
+			}
+			return rpcErr
+		}
+
+		if rpcResp.ID != id {
+			return fmt.Errorf("response id %d does not match request id %d", rpcResp.ID, id)
+		}
+
+		result = rpcResp.Result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// post sends a raw JSON body to a node URL and returns the raw response body.
+// Rate limiting and server errors are returned as *HTTPError.
+func (c *Client) post(ctx context.Context, url string, reqBody []byte) ([]byte, error) {
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &transportError{err: fmt.Errorf("failed to read response: %v", err)}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Body:       string(body),
+		}
+	}
+
+	return body, nil
+}
@@ -0,0 +1,127 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestClientRequestIDsAndHeaders tests that request IDs increment and headers are sent
+func TestClientRequestIDsAndHeaders(t *testing.T) {
+	var mu sync.Mutex
+	var ids []int
+	var auth, custom string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		ids = append(ids, req.ID)
+		auth = r.Header.Get("Authorization")
+		custom = r.Header.Get("X-Api-Key")
+		mu.Unlock()
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": "0xaa36a7"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithBearerToken("secret"), WithHeader("X-Api-Key", "key"))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		chainID, err := client.GetChainID(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get chain ID: %v", err)
+		}
+		if chainID.Int64() != 11155111 {
+			t.Fatalf("Chain ID %s, expected 11155111", chainID)
+		}
+	}
+
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Fatalf("Expected request IDs [1 2 3], got %v", ids)
+	}
+	if auth != "Bearer secret" {
+		t.Fatalf("Authorization header %q, expected %q", auth, "Bearer secret")
+	}
+	if custom != "key" {
+		t.Fatalf("X-Api-Key header %q, expected %q", custom, "key")
+	}
+}
+
+// TestClientMiddleware tests that middleware runs in order around each call
+func TestClientMiddleware(t *testing.T) {
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		return "0x10", nil
+	})
+
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next RPCHandler) RPCHandler {
+			return func(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+				order = append(order, name+">"+method)
+				result, err := next(ctx, method, params)
+				order = append(order, name+"<")
+				return result, err
+			}
+		}
+	}
+
+	client := NewClient(rpc.URL, WithMiddleware(tag("outer"), tag("inner")))
+	if _, err := client.GetBlockNumber(context.Background()); err != nil {
+		t.Fatalf("Failed to get block number: %v", err)
+	}
+
+	want := "outer>eth_blockNumber,inner>eth_blockNumber,inner<,outer<"
+	if got := strings.Join(order, ","); got != want {
+		t.Fatalf("Middleware order %s, expected %s", got, want)
+	}
+
+	// Middleware can short-circuit the call
+	cached := func(next RPCHandler) RPCHandler {
+		return func(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+			return json.RawMessage(`"0x1"`), nil
+		}
+	}
+	client = NewClient(rpc.URL, WithMiddleware(cached))
+	before := rpc.callCount("eth_chainId")
+	if _, err := client.GetChainID(context.Background()); err != nil {
+		t.Fatalf("Failed to get chain ID: %v", err)
+	}
+	if rpc.callCount("eth_chainId") != before {
+		t.Fatal("Short-circuiting middleware should not reach the server")
+	}
+}
+
+// TestClientErrors tests RPC error typing and timeouts
+func TestClientErrors(t *testing.T) {
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		return nil, &mockRPCError{Code: -32000, Message: "insufficient funds for gas * price + value"}
+	})
+
+	_, err := NewClient(rpc.URL).GetBalance(context.Background(), HexToAddress(testAddress))
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("Expected an RPCError, got %v", err)
+	}
+	if rpcErr.Code != -32000 {
+		t.Fatalf("RPC error code %d, expected -32000", rpcErr.Code)
+	}
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	_, err = NewClient(slow.URL, WithTimeout(20*time.Millisecond), WithRetry(RetryPolicy{MaxAttempts: 1})).GetChainID(context.Background())
+	if err == nil {
+		t.Fatal("Expected a timeout error")
+	}
+}
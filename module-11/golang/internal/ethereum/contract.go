@@ -0,0 +1,163 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// CreateAddress returns the address of a contract deployed with CREATE:
+// keccak256(rlp([sender, nonce]))[12:]
+func CreateAddress(sender common.Address, nonce uint64) common.Address {
+	encoded, _ := rlp.EncodeToBytes([]interface{}{sender, nonce})
+	return common.BytesToAddress(Keccak256(encoded)[12:])
+}
+
+// ContractArtifact is a compiled contract loaded from a build artifact
+type ContractArtifact struct {
+	ContractName string
+	ABI          json.RawMessage // nil when the artifact has none
+	Bytecode     []byte          // creation (init) code
+}
+
+// LoadContractArtifact reads a compiled contract from a JSON artifact. It accepts
+// Hardhat/Truffle artifacts ("bytecode": "0x..."), Foundry artifacts
+// ("bytecode": {"object": "0x..."}), solc standard JSON contract output
+// ("evm": {"bytecode": {"object": "..."}}) and solc --combined-json entries ("bin": "...").
+func LoadContractArtifact(path string) (*ContractArtifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %v", err)
+	}
+
+	var raw struct {
+		ContractName string          `json:"contractName"`
+		ABI          json.RawMessage `json:"abi"`
+		Bytecode     json.RawMessage `json:"bytecode"`
+		Bin          string          `json:"bin"`
+		EVM          struct {
+			Bytecode struct {
+				Object string `json:"object"`
+			} `json:"bytecode"`
+		} `json:"evm"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse artifact %s: %v", path, err)
+	}
+
+	// Bytecode is either a hex string or an object with the hex in "object"
+	var code string
+	if len(raw.Bytecode) > 0 {
+		var object struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(raw.Bytecode, &code); err != nil {
+			if err := json.Unmarshal(raw.Bytecode, &object); err != nil {
+				return nil, fmt.Errorf("artifact %s has an unrecognised bytecode field", path)
+			}
+			code = object.Object
+		}
+	}
+	if code == "" {
+		code = raw.EVM.Bytecode.Object
+	}
+	if code == "" {
+		code = raw.Bin
+	}
+
+	bytecode, err := ParseBytecode(code)
+	if err != nil {
+		return nil, fmt.Errorf("artifact %s: %w", path, err)
+	}
+
+	// solc --combined-json may store the ABI as a JSON string
+	abi := raw.ABI
+	var abiString string
+	if json.Unmarshal(abi, &abiString) == nil {
+		abi = json.RawMessage(abiString)
+	}
+	if string(abi) == "null" {
+		abi = nil
+	}
+
+	return &ContractArtifact{ContractName: raw.ContractName, ABI: abi, Bytecode: bytecode}, nil
+}
+
+// ParseBytecode decodes contract bytecode given as hex, with or without 0x
+func ParseBytecode(code string) ([]byte, error) {
+	code = strings.TrimSpace(code)
+	if code == "" || code == "0x" {
+		return nil, fmt.Errorf("no bytecode (abstract contract or interface?)")
+	}
+	if strings.Contains(code, "__") {
+		return nil, fmt.Errorf("bytecode has unlinked library placeholders")
+	}
+	if !strings.HasPrefix(code, "0x") {
+		code = "0x" + code
+	}
+	bytecode, err := HexDecode(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bytecode: %v", err)
+	}
+	return bytecode, nil
+}
+
+// CallContract executes a read-only call with eth_call and returns the raw return data.
+// An empty from calls without a sender; block is a block number or tag and defaults to "latest".
+// A reverted call returns an *RPCError whose revert data is available through RevertData.
+func (c *Client) CallContract(ctx context.Context, from, to string, data []byte, block string) ([]byte, error) {
+	msg := map[string]string{
+		"to":   to,
+		"data": fmt.Sprintf("0x%x", data),
+	}
+	if from != "" {
+		msg["from"] = from
+	}
+	if block == "" {
+		block = "latest"
+	}
+
+	result, err := c.Call(ctx, "eth_call", []interface{}{msg, block})
+	if err != nil {
+		return nil, fmt.Errorf("error calling contract: %w", err)
+	}
+
+	var resultHex string
+	if err := json.Unmarshal(result, &resultHex); err != nil {
+		return nil, fmt.Errorf("failed to parse call result: %v", err)
+	}
+	return HexDecode(resultHex)
+}
+
+// RevertData extracts the revert data from a failed eth_call or eth_estimateGas.
+// Nodes return it as a hex string in the JSON-RPC error data, sometimes nested
+// in an object with a "data" field.
+func RevertData(err error) ([]byte, bool) {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || len(rpcErr.Data) == 0 {
+		return nil, false
+	}
+
+	var dataHex string
+	if json.Unmarshal(rpcErr.Data, &dataHex) != nil {
+		var nested struct {
+			Data string `json:"data"`
+		}
+		if json.Unmarshal(rpcErr.Data, &nested) != nil {
+			return nil, false
+		}
+		dataHex = nested.Data
+	}
+
+	data, decodeErr := HexDecode(dataHex)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return data, true
+}
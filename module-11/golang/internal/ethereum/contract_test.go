@@ -0,0 +1,206 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TestCreateAddress tests CREATE address derivation against known deployments
+func TestCreateAddress(t *testing.T) {
+	sender := common.HexToAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+	expected := []string{
+		"0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d",
+		"0x343c43a37d37dff08ae8c4a11544c718abb4fcf8",
+		"0xf778b86fa74e846c4f0a1fbd1335fe81c00a0c91",
+		"0xfffd933a0bc612844eaf0c6fe3e5b8e9b6c1d19c",
+	}
+	for nonce, want := range expected {
+		if got := CreateAddress(sender, uint64(nonce)); got != common.HexToAddress(want) {
+			t.Fatalf("Nonce %d: address %s, expected %s", nonce, got.Hex(), want)
+		}
+	}
+}
+
+// TestLoadContractArtifact tests the supported artifact formats
+func TestLoadContractArtifact(t *testing.T) {
+	dir := t.TempDir()
+	code := []byte{0x60, 0x80, 0x60, 0x40, 0x52}
+
+	artifacts := map[string]string{
+		"hardhat.json":  `{"contractName":"Counter","abi":[{"type":"constructor","inputs":[]}],"bytecode":"0x6080604052"}`,
+		"foundry.json":  `{"abi":[],"bytecode":{"object":"0x6080604052","linkReferences":{}}}`,
+		"solc.json":     `{"abi":[],"evm":{"bytecode":{"object":"6080604052"}}}`,
+		"combined.json": `{"abi":"[]","bin":"6080604052"}`,
+	}
+	for name, content := range artifacts {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0600)
+
+		artifact, err := LoadContractArtifact(path)
+		if err != nil {
+			t.Fatalf("%s: failed to load: %v", name, err)
+		}
+		if !bytes.Equal(artifact.Bytecode, code) {
+			t.Fatalf("%s: bytecode %x, expected %x", name, artifact.Bytecode, code)
+		}
+		if !json.Valid(artifact.ABI) {
+			t.Fatalf("%s: invalid ABI %s", name, artifact.ABI)
+		}
+	}
+
+	// Interfaces and unlinked libraries cannot be deployed
+	for name, content := range map[string]string{
+		"interface.json": `{"abi":[],"bytecode":"0x"}`,
+		"unlinked.json":  `{"abi":[],"bytecode":"0x6080__$a1b2c3$__6040"}`,
+	} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0600)
+		if _, err := LoadContractArtifact(path); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+// TestSendWithDataAndDeploy tests calldata and contract creation transactions
+func TestSendWithDataAndDeploy(t *testing.T) {
+	var rawTxs [][]byte
+	var estimates []map[string]interface{}
+
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_chainId":
+			return "0xaa36a7", nil
+		case "eth_getTransactionCount":
+			return "0x4", nil
+		case "eth_estimateGas":
+			var msg map[string]interface{}
+			json.Unmarshal(params[0], &msg)
+			estimates = append(estimates, msg)
+			return "0x186a0", nil
+		case "eth_getBlockByNumber":
+			return map[string]string{"baseFeePerGas": "0x3b9aca00"}, nil
+		case "eth_feeHistory":
+			return mockFeeHistory(), nil
+		case "eth_gasPrice":
+			return "0x3b9aca00", nil
+		case "eth_sendRawTransaction":
+			var rawHex string
+			json.Unmarshal(params[0], &rawHex)
+			raw, _ := HexDecode(rawHex)
+			rawTxs = append(rawTxs, raw)
+			return fmt.Sprintf("0x%x", Keccak256(raw)), nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+
+	calldata := []byte{0xa9, 0x05, 0x9c, 0xbb}
+	if _, err := client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(0), nil, WithData(calldata)); err != nil {
+		t.Fatalf("Failed to send with data: %v", err)
+	}
+
+	initCode := []byte{0x60, 0x80, 0x60, 0x40, 0x52}
+	if _, err := client.SendTransaction(ctx, keyPair, "", big.NewInt(0), WithData(initCode)); err != nil {
+		t.Fatalf("Failed to deploy: %v", err)
+	}
+
+	// Gas estimates include the data; contract creation has no "to"
+	if estimates[0]["data"] != "0xa9059cbb" || estimates[0]["to"] == nil {
+		t.Fatalf("Unexpected call estimate: %v", estimates[0])
+	}
+	if estimates[1]["data"] != "0x6080604052" || estimates[1]["to"] != nil {
+		t.Fatalf("Unexpected deployment estimate: %v", estimates[1])
+	}
+
+	// Decode the type-2 payload: [chainId, nonce, tip, maxFee, gas, to, value, data, ...]
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(rawTxs[0][1:], &fields); err != nil {
+		t.Fatalf("Failed to decode type-2 transaction: %v", err)
+	}
+	var data []byte
+	rlp.DecodeBytes(fields[7], &data)
+	if !bytes.Equal(data, calldata) {
+		t.Fatalf("Calldata %x, expected %x", data, calldata)
+	}
+
+	// Decode the legacy payload: [nonce, gasPrice, gas, to, value, data, v, r, s]
+	fields = nil
+	if err := rlp.DecodeBytes(rawTxs[1], &fields); err != nil {
+		t.Fatalf("Failed to decode legacy transaction: %v", err)
+	}
+	var to []byte
+	rlp.DecodeBytes(fields[3], &to)
+	rlp.DecodeBytes(fields[5], &data)
+	if len(to) != 0 || !bytes.Equal(data, initCode) {
+		t.Fatalf("Expected a contract creation with init code, got to=%x data=%x", to, data)
+	}
+}
+
+// TestCallContract tests eth_call results and revert data extraction
+func TestCallContract(t *testing.T) {
+	revertData := "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6e6f706500000000000000000000000000000000000000000000000000000000"
+
+	var calls []map[string]string
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		if method != "eth_call" {
+			return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+		}
+		var msg map[string]string
+		json.Unmarshal(params[0], &msg)
+		calls = append(calls, msg)
+
+		switch msg["data"] {
+		case "0x70a08231":
+			return "0x00000000000000000000000000000000000000000000000000000000000003e8", nil
+		case "0xdeadbeef":
+			return nil, &mockRPCError{Code: 3, Message: "execution reverted: nope", Data: revertData}
+		}
+		return nil, &mockRPCError{Code: -32000, Message: "execution reverted", Data: map[string]string{"data": "0x"}}
+	})
+
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+
+	result, err := client.CallContract(ctx, "", vectorTo, []byte{0x70, 0xa0, 0x82, 0x31}, "")
+	if err != nil {
+		t.Fatalf("Failed to call: %v", err)
+	}
+	if new(big.Int).SetBytes(result).Int64() != 1000 {
+		t.Fatalf("Unexpected result %x", result)
+	}
+	if _, hasFrom := calls[0]["from"]; hasFrom || calls[0]["to"] != vectorTo {
+		t.Fatalf("Unexpected call %v", calls[0])
+	}
+
+	// Revert data is a hex string or nested in an object
+	_, err = client.CallContract(ctx, vectorTo, vectorTo, []byte{0xde, 0xad, 0xbe, 0xef}, "latest")
+	data, ok := RevertData(err)
+	if !ok || fmt.Sprintf("0x%x", data) != revertData {
+		t.Fatalf("Unexpected revert data %x (%v)", data, err)
+	}
+	_, err = client.CallContract(ctx, "", vectorTo, []byte{0x01}, "latest")
+	if data, ok := RevertData(err); !ok || len(data) != 0 {
+		t.Fatalf("Expected empty revert data, got %x (%v)", data, err)
+	}
+	if _, ok := RevertData(fmt.Errorf("connection refused")); ok {
+		t.Fatalf("Expected no revert data for a transport error")
+	}
+}
@@ -0,0 +1,201 @@
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DecodedTx is a signed raw transaction split into its fields, with the hash and
+// the sender recovered from the signature
+type DecodedTx struct {
+	Type                 uint64
+	Hash                 common.Hash
+	From                 common.Address
+	ChainID              *big.Int // nil for a legacy transaction without EIP-155 replay protection
+	Nonce                uint64
+	GasLimit             uint64
+	GasPrice             *big.Int        // legacy and EIP-2930
+	MaxPriorityFeePerGas *big.Int        // EIP-1559
+	MaxFeePerGas         *big.Int        // EIP-1559
+	To                   *common.Address // nil for contract creation
+	Value                *big.Int
+	Data                 []byte
+	AccessList           AccessList
+	V, R, S              *big.Int // V is the y-parity for typed transactions
+
+	// Fields holds every RLP field of a transaction type this package does not
+	// know. Only Type, Hash, ChainID, the signature and From are filled in for it.
+	Fields []rlp.RawValue
+}
+
+// Known reports whether the transaction type was decoded field by field
+func (d *DecodedTx) Known() bool {
+	return d.Fields == nil
+}
+
+// DecodeTransaction decodes a signed raw transaction: a legacy RLP list, or an
+// EIP-2718 typed envelope (type byte followed by an RLP list). Legacy, EIP-2930
+// and EIP-1559 transactions are decoded field by field. Other types are split
+// into their raw fields, assuming the convention every typed transaction follows
+// so far: the chain ID first, the y-parity, r and s last, and the signature over
+// keccak256(type || rlp(the other fields)).
+func DecodeTransaction(raw []byte) (*DecodedTx, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("empty transaction")
+	}
+
+	d := &DecodedTx{Hash: common.BytesToHash(Keccak256(raw))}
+	if raw[0] >= 0xc0 {
+		if err := d.decodeLegacy(raw); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+	if raw[0] > 0x7f {
+		return nil, fmt.Errorf("invalid transaction: first byte 0x%02x is neither a type nor an RLP list", raw[0])
+	}
+
+	d.Type = uint64(raw[0])
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(raw[1:], &fields); err != nil {
+		return nil, fmt.Errorf("invalid type %d transaction: %v", d.Type, err)
+	}
+	if err := d.decodeTyped(fields); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// decodeLegacy decodes [nonce, gasPrice, gas, to, value, data, v, r, s]
+func (d *DecodedTx) decodeLegacy(raw []byte) error {
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(raw, &fields); err != nil {
+		return fmt.Errorf("invalid legacy transaction: %v", err)
+	}
+	if len(fields) != 9 {
+		return fmt.Errorf("invalid legacy transaction: %d fields, expected 9", len(fields))
+	}
+
+	d.Type = TxTypeLegacy
+	if err := decodeFields(fields[:6], &d.Nonce, &d.GasPrice, &d.GasLimit, &d.To, &d.Value, &d.Data); err != nil {
+		return fmt.Errorf("invalid legacy transaction: %v", err)
+	}
+	if err := decodeFields(fields[6:], &d.V, &d.R, &d.S); err != nil {
+		return fmt.Errorf("invalid legacy transaction signature: %v", err)
+	}
+
+	// EIP-155 signs [.., chainId, 0, 0] and sets v = chainId*2 + 35 + recovery id;
+	// older transactions sign the six fields and set v = 27 + recovery id
+	payload := fields[:6]
+	var recID *big.Int
+	if d.V.Cmp(big.NewInt(35)) >= 0 {
+		v := new(big.Int).Sub(d.V, big.NewInt(35))
+		d.ChainID = new(big.Int).Rsh(v, 1)
+		recID = new(big.Int).And(v, big.NewInt(1))
+
+		chainID, _ := rlp.EncodeToBytes(d.ChainID)
+		zero, _ := rlp.EncodeToBytes(uint(0))
+		payload = append(append([]rlp.RawValue(nil), payload...), chainID, zero, zero)
+	} else {
+		recID = new(big.Int).Sub(d.V, big.NewInt(27))
+	}
+
+	encoded, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode signing payload: %v", err)
+	}
+	return d.recoverSender(Keccak256(encoded), recID)
+}
+
+// decodeTyped decodes the RLP fields of a typed transaction
+func (d *DecodedTx) decodeTyped(fields []rlp.RawValue) error {
+	if len(fields) < 4 {
+		return fmt.Errorf("invalid type %d transaction: %d fields", d.Type, len(fields))
+	}
+	n := len(fields)
+
+	var err error
+	switch d.Type {
+	case TxTypeAccessList:
+		if n != 11 {
+			return fmt.Errorf("invalid EIP-2930 transaction: %d fields, expected 11", n)
+		}
+		err = decodeFields(fields[:8], &d.ChainID, &d.Nonce, &d.GasPrice, &d.GasLimit, &d.To, &d.Value, &d.Data, &d.AccessList)
+	case TxTypeDynamicFee:
+		if n != 12 {
+			return fmt.Errorf("invalid EIP-1559 transaction: %d fields, expected 12", n)
+		}
+		err = decodeFields(fields[:9], &d.ChainID, &d.Nonce, &d.MaxPriorityFeePerGas, &d.MaxFeePerGas, &d.GasLimit, &d.To, &d.Value, &d.Data, &d.AccessList)
+	default:
+		d.Fields = fields
+		if err := rlp.DecodeBytes(fields[0], &d.ChainID); err != nil {
+			return fmt.Errorf("type %d transaction does not start with a chain ID: %v", d.Type, err)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid type %d transaction: %v", d.Type, err)
+	}
+
+	if err := decodeFields(fields[n-3:], &d.V, &d.R, &d.S); err != nil {
+		return fmt.Errorf("invalid type %d transaction signature: %v", d.Type, err)
+	}
+
+	encoded, err := rlp.EncodeToBytes(fields[:n-3])
+	if err != nil {
+		return fmt.Errorf("failed to encode signing payload: %v", err)
+	}
+	return d.recoverSender(Keccak256(append([]byte{byte(d.Type)}, encoded...)), d.V)
+}
+
+// decodeFields RLP-decodes each field into the value pointed to by the matching
+// target. A *common.Address target is left nil for an empty field (contract creation).
+func decodeFields(fields []rlp.RawValue, targets ...interface{}) error {
+	for i, field := range fields {
+		if to, ok := targets[i].(**common.Address); ok {
+			var addr []byte
+			if err := rlp.DecodeBytes(field, &addr); err != nil {
+				return fmt.Errorf("field %d: %v", i, err)
+			}
+			switch len(addr) {
+			case 0:
+				*to = nil
+			case common.AddressLength:
+				a := common.BytesToAddress(addr)
+				*to = &a
+			default:
+				return fmt.Errorf("field %d: recipient has %d bytes", i, len(addr))
+			}
+			continue
+		}
+		if err := rlp.DecodeBytes(field, targets[i]); err != nil {
+			return fmt.Errorf("field %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// recoverSender checks the signature values and recovers the address that signed hash
+func (d *DecodedTx) recoverSender(hash []byte, recID *big.Int) error {
+	if !recID.IsUint64() || recID.Uint64() > 1 {
+		return fmt.Errorf("invalid signature: recovery id %s", recID)
+	}
+	if !crypto.ValidateSignatureValues(byte(recID.Uint64()), d.R, d.S, true) {
+		return fmt.Errorf("invalid signature: r or s out of range")
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	d.R.FillBytes(sig[:32])
+	d.S.FillBytes(sig[32:64])
+	sig[64] = byte(recID.Uint64())
+
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return fmt.Errorf("failed to recover sender: %v", err)
+	}
+	d.From = crypto.PubkeyToAddress(*pub)
+	return nil
+}
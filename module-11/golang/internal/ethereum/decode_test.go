@@ -0,0 +1,139 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TestDecodeTransaction tests decoding the signed transaction vectors back into
+// their fields, sender and hash
+func TestDecodeTransaction(t *testing.T) {
+	sender := HexToAddress(testAddress)
+	to := HexToAddress(vectorTo)
+	specTo := HexToAddress("0x3535353535353535353535353535353535353535")
+
+	vectors := []struct {
+		name string
+		raw  string
+		want DecodedTx
+	}{
+		{
+			// The example from the EIP-155 specification
+			name: "eip155 spec",
+			raw:  "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83",
+			want: DecodedTx{
+				Type: TxTypeLegacy, Hash: common.HexToHash("0x33469b22e9f636356c4160a87eb19df52b7412e8eac32a4a55ffe88ea8350788"),
+				From: HexToAddress("0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F"), ChainID: big.NewInt(1), Nonce: 9, GasLimit: 21000,
+				GasPrice: big.NewInt(20_000_000_000), To: &specTo, Value: new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil),
+			},
+		},
+		{
+			name: "contract creation",
+			raw:  "f8630784342770c08301d4c08080916080604052348015600f57600080fd5b5082f4f6a0208a754c387aff5d704963cb7860866f936dd571065209442f9de1dc48e075f9a00e642660b9d1084aef326887e16fea8378b0031731c6d2bf9cadebc1edc31f70",
+			want: DecodedTx{
+				Type: TxTypeLegacy, Hash: common.HexToHash("0x0070e280e7defe48b458b2afd53558d000a1014f55d8b2ec525f7cc99adc2c92"),
+				From: sender, ChainID: big.NewInt(31337), Nonce: 7, GasLimit: 120000,
+				GasPrice: big.NewInt(875_000_000), Value: big.NewInt(0), Data: mustHex(t, "0x6080604052348015600f57600080fd5b50"),
+			},
+		},
+		{
+			name: "eip2930",
+			raw:  "01f8db83aa36a702847735940082753094de9ca654ae5a3673d894eba15b63603fa00f85040180f872f859941c7d4b196cb0c7b01d743fbc6116a902379c7238f842a00000000000000000000000000000000000000000000000000000000000000001a09c04773acff4c5c42718bd0120c72761f458e43068a3961eb935577d1ed4effbd6940000000000000000000000000000000000000004c080a05cdec978e4c2906c506d10fa67aca868a1244f70d05d2dd0a0bb1071a7537c42a06d08aa55d49b607d5945a1aafb89c0b1b1f53d8abea30612bfce3fa5ec745f12",
+			want: DecodedTx{
+				Type: TxTypeAccessList, Hash: common.HexToHash("0xc6b42c234114400902447c0775d2d2b96d0a38cefa98f2b505ba73cc81bea05d"),
+				From: sender, ChainID: big.NewInt(11155111), Nonce: 2, GasLimit: 30000,
+				GasPrice: big.NewInt(2_000_000_000), To: &to, Value: big.NewInt(1), AccessList: testAccessList(),
+			},
+		},
+		{
+			name: "eip1559",
+			raw:  "02f87083aa36a7058459682f008507558bdb0082520894de9ca654ae5a3673d894eba15b63603fa00f85048203e880c001a09f0dbb5f88a0d8905b3141adfabe782f5742dff6dc474e47af34725988d510d1a023d94d3c1d6237fa46010ce1a22e4f1f70b1c622fe91c695db241600c516672c",
+			want: DecodedTx{
+				Type: TxTypeDynamicFee, Hash: common.HexToHash("0x998a42f5a26adb44b9f207c36d5671ae81f8e354590bf7be202db91ba3ed28f8"),
+				From: sender, ChainID: big.NewInt(11155111), Nonce: 5, GasLimit: 21000,
+				MaxPriorityFeePerGas: big.NewInt(1_500_000_000), MaxFeePerGas: big.NewInt(31_500_000_000), To: &to, Value: big.NewInt(1000),
+				AccessList: AccessList{},
+			},
+		},
+	}
+
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			d, err := DecodeTransaction(mustHex(t, "0x"+v.raw))
+			if err != nil {
+				t.Fatalf("Failed to decode: %v", err)
+			}
+			if !d.Known() || d.V == nil || d.R == nil || d.S == nil {
+				t.Fatalf("Expected a fully decoded signed transaction: %+v", d)
+			}
+			d.V, d.R, d.S = nil, nil, nil
+			// Compare printed fields, as RLP decodes empty data and lists as empty slices
+			if got, want := fmt.Sprintf("%+v", *d), fmt.Sprintf("%+v", v.want); got != want {
+				t.Fatalf("Decoded transaction mismatch\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+// TestDecodeTransactionSignatures tests sender recovery for pre-EIP-155 and
+// unknown typed transactions, and the rejection of malformed input
+func TestDecodeTransactionSignatures(t *testing.T) {
+	key, err := crypto.HexToECDSA(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Invalid key: %v", err)
+	}
+	sender := HexToAddress(testAddress)
+
+	// A legacy transaction without replay protection signs the six fields, v = 27 + recovery id
+	fields := []interface{}{uint64(1), big.NewInt(1), uint64(21000), HexToAddress(vectorTo), big.NewInt(1), []byte{}}
+	payload, _ := rlp.EncodeToBytes(fields)
+	sig, _ := crypto.Sign(Keccak256(payload), key)
+	unprotected, _ := rlp.EncodeToBytes(append(fields, big.NewInt(27+int64(sig[64])), new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])))
+
+	d, err := DecodeTransaction(unprotected)
+	if err != nil || d.From != sender || d.ChainID != nil {
+		t.Fatalf("Pre-EIP-155 transaction: %+v, %v", d, err)
+	}
+
+	// A future type decodes into raw fields with its chain ID and sender
+	fields = []interface{}{big.NewInt(11155111), uint64(4), []byte("future")}
+	payload, _ = rlp.EncodeToBytes(fields)
+	sig, _ = crypto.Sign(Keccak256(append([]byte{0x05}, payload...)), key)
+	body, _ := rlp.EncodeToBytes(append(fields, uint64(sig[64]), new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])))
+	future := append([]byte{0x05}, body...)
+
+	d, err = DecodeTransaction(future)
+	if err != nil || d.Known() || d.Type != 5 || len(d.Fields) != 6 || d.ChainID.Int64() != 11155111 || d.From != sender {
+		t.Fatalf("Future type transaction: %+v, %v", d, err)
+	}
+
+	bad := map[string]string{
+		"empty":          "",
+		"not a type":     "80",
+		"truncated":      "02f87083aa36a705",
+		"trailing bytes": "02c0c0",
+		"high s": "02f87083aa36a7058459682f008507558bdb0082520894de9ca654ae5a3673d894eba15b63603fa00f85048203e880c001a09f0dbb5f88a0d8905b3141adfabe782f5742dff6dc474e47af34725988d510d1a0" +
+			hex.EncodeToString(new(big.Int).Sub(crypto.S256().Params().N, mustBig(t, "0x23d94d3c1d6237fa46010ce1a22e4f1f70b1c622fe91c695db241600c516672c")).Bytes()),
+	}
+	for name, raw := range bad {
+		if _, err := DecodeTransaction(mustHex(t, "0x"+raw)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// mustBig parses a hex number or fails the test
+func mustBig(t *testing.T, s string) *big.Int {
+	t.Helper()
+	n, err := HexToBig(s)
+	if err != nil {
+		t.Fatalf("Invalid number %s: %v", s, err)
+	}
+	return n
+}
@@ -0,0 +1,415 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
+	"github.com/metana-bootcamp/ethwallet/internal/address"
+)
+
+// DefaultENSRegistry is the ENS registry address on mainnet, Sepolia and Holesky
+const DefaultENSRegistry = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+// ErrENSNotFound is returned when a name has no resolver or no address record
+var ErrENSNotFound = errors.New("ENS name not found")
+
+// CCIPStatusError is returned by FetchCCIP when the gateway answers with an HTTP
+// status other than 200
+type CCIPStatusError struct {
+	StatusCode int
+	Message    string // the gateway's error message, if any
+}
+
+// Error implements the error interface
+func (e *CCIPStatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("gateway returned HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("gateway returned HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// extendedResolverInterfaceID is the ERC-165 ID of the ENSIP-10 resolve(bytes,bytes) interface
+var extendedResolverInterfaceID = [4]byte{0x90, 0x61, 0xb9, 0x23}
+
+// maxCCIPLookups limits the EIP-3668 offchain lookups followed for a single call
+const maxCCIPLookups = 4
+
+// maxCCIPResponseSize limits the gateway response read by the default fetcher
+const maxCCIPResponseSize = 1 << 20
+
+// ensRegistryABI is the subset of the ENS registry used for resolution
+var ensRegistryABI = mustParseABI(
+	"function resolver(bytes32 node) view returns (address)",
+)
+
+// ensResolverABI is the subset of the resolver interfaces used for resolution
+var ensResolverABI = mustParseABI(
+	"function supportsInterface(bytes4 interfaceId) view returns (bool)",
+	"function addr(bytes32 node) view returns (address)",
+	"function name(bytes32 node) view returns (string)",
+	"function resolve(bytes name, bytes data) view returns (bytes)",
+)
+
+// offchainLookupError is the EIP-3668 revert that requests an offchain lookup
+var offchainLookupError = mustParseABI(
+	"error OffchainLookup(address sender, string[] urls, bytes callData, bytes4 callbackFunction, bytes extraData)",
+).Errors[0]
+
+// ccipCallbackArgs are the arguments passed to an EIP-3668 callback function
+var ccipCallbackArgs = mustParseABI(
+	"function callback(bytes response, bytes extraData)",
+).Methods[0].Inputs
+
+// CCIPFetcher queries an EIP-3668 gateway URL for an offchain lookup and returns
+// the response data that is passed to the contract's callback
+type CCIPFetcher func(ctx context.Context, url string, sender common.Address, callData []byte) ([]byte, error)
+
+// ENS resolves Ethereum Name Service names through a registry contract
+type ENS struct {
+	Registry common.Address
+	client   *Client
+	fetcher  CCIPFetcher
+}
+
+// ENSOption configures an ENS resolver
+type ENSOption func(*ENS)
+
+// WithCCIPFetcher replaces the HTTP fetcher used for EIP-3668 offchain lookups,
+// e.g. to restrict gateways or to serve lookups in tests
+func WithCCIPFetcher(fetcher CCIPFetcher) ENSOption {
+	return func(e *ENS) {
+		e.fetcher = fetcher
+	}
+}
+
+// NewENS returns a resolver using the registry at registry
+func NewENS(client *Client, registry common.Address, opts ...ENSOption) *ENS {
+	e := &ENS{Registry: registry, client: client, fetcher: FetchCCIP}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// IsENSName reports whether s looks like an ENS name rather than a hex address
+func IsENSName(s string) bool {
+	return strings.Contains(s, ".") && !address.IsHex(s) && !strings.ContainsAny(s, " /:")
+}
+
+// NormalizeENSName lowercases a name and checks that no label is empty. Full
+// ENSIP-15 normalization of Unicode names is not applied.
+func NormalizeENSName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", fmt.Errorf("empty ENS name")
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" {
+			return "", fmt.Errorf("invalid ENS name %q: empty label", name)
+		}
+	}
+	return name, nil
+}
+
+// Namehash computes the ENS namehash of a normalized name
+func Namehash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		node = common.BytesToHash(crypto.Keccak256(node[:], labelHash))
+	}
+	return node
+}
+
+// DNSEncode encodes a name in DNS wire format as used by ENSIP-10: each label
+// prefixed with its length, terminated by a zero byte
+func DNSEncode(name string) ([]byte, error) {
+	var encoded []byte
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > 255 {
+				return nil, fmt.Errorf("invalid ENS label %q", label)
+			}
+			encoded = append(encoded, byte(len(label)))
+			encoded = append(encoded, label...)
+		}
+	}
+	return append(encoded, 0), nil
+}
+
+// Resolve returns the address of an ENS name. The resolver is found with ENSIP-10
+// wildcard resolution, and EIP-3668 offchain lookups are followed.
+func (e *ENS) Resolve(ctx context.Context, name string) (common.Address, error) {
+	name, err := NormalizeENSName(name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	node := Namehash(name)
+
+	values, err := e.resolveRecord(ctx, name, mustMethod(ensResolverABI, "addr"), node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	addr := values[0].(common.Address)
+	if addr == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("%w: %s has no address record", ErrENSNotFound, name)
+	}
+	return addr, nil
+}
+
+// LookupAddress returns the primary name of an address from its reverse record,
+// or an empty string when there is none. The name is only returned when it
+// resolves back to the address.
+func (e *ENS) LookupAddress(ctx context.Context, addr common.Address) (string, error) {
+	reverseName := strings.ToLower(addr.Hex()[2:]) + ".addr.reverse"
+	values, err := e.resolveRecord(ctx, reverseName, mustMethod(ensResolverABI, "name"), Namehash(reverseName))
+	if errors.Is(err, ErrENSNotFound) || errors.Is(err, ErrNoContractData) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	name := values[0].(string)
+	if name == "" {
+		return "", nil
+	}
+
+	// Anyone can claim any name in their reverse record, so check the forward record
+	resolved, err := e.Resolve(ctx, name)
+	if errors.Is(err, ErrENSNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if resolved != addr {
+		return "", nil
+	}
+	return name, nil
+}
+
+// Resolver returns the resolver responsible for name and whether it was set for
+// the name itself rather than inherited from a parent (ENSIP-10 wildcard)
+func (e *ENS) Resolver(ctx context.Context, name string) (common.Address, bool, error) {
+	for current := name; ; {
+		values, err := e.client.callMethod(ctx, e.Registry, mustMethod(ensRegistryABI, "resolver"), Namehash(current))
+		if err != nil {
+			return common.Address{}, false, fmt.Errorf("error reading ENS registry: %w", err)
+		}
+		if resolver := values[0].(common.Address); resolver != (common.Address{}) {
+			return resolver, current == name, nil
+		}
+
+		if current == "" {
+			return common.Address{}, false, fmt.Errorf("%w: no resolver for %s", ErrENSNotFound, name)
+		}
+		_, parent, found := strings.Cut(current, ".")
+		if !found {
+			parent = ""
+		}
+		current = parent
+	}
+}
+
+// resolveRecord reads a record of name from its resolver. Extended resolvers are
+// queried through resolve(bytes,bytes); other resolvers are only used when they
+// are set for the name itself.
+func (e *ENS) resolveRecord(ctx context.Context, name string, method *abi.Method, node common.Hash) ([]interface{}, error) {
+	resolver, exact, err := e.Resolver(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	extended, err := e.supportsExtendedResolver(ctx, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := method.Pack(node)
+	if err != nil {
+		return nil, err
+	}
+
+	if !extended {
+		if !exact {
+			return nil, fmt.Errorf("%w: the resolver of a parent of %s does not support wildcard resolution", ErrENSNotFound, name)
+		}
+		result, err := e.call(ctx, resolver, data)
+		if err != nil {
+			return nil, fmt.Errorf("error calling %s on resolver: %w", method.Name, err)
+		}
+		return method.Unpack(result)
+	}
+
+	dnsName, err := DNSEncode(name)
+	if err != nil {
+		return nil, err
+	}
+	resolve := mustMethod(ensResolverABI, "resolve")
+	resolveData, err := resolve.Pack(dnsName, data)
+	if err != nil {
+		return nil, err
+	}
+	result, err := e.call(ctx, resolver, resolveData)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving %s: %w", name, err)
+	}
+	values, err := resolve.Unpack(result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resolve result: %v", err)
+	}
+	return method.Unpack(values[0].([]byte))
+}
+
+// supportsExtendedResolver reports whether a resolver implements ENSIP-10
+func (e *ENS) supportsExtendedResolver(ctx context.Context, resolver common.Address) (bool, error) {
+	values, err := e.client.callMethod(ctx, resolver, mustMethod(ensResolverABI, "supportsInterface"), extendedResolverInterfaceID)
+	if err != nil {
+		if _, reverted := RevertData(err); reverted || errors.Is(err, ErrNoContractData) {
+			return false, nil
+		}
+		return false, err
+	}
+	return values[0].(bool), nil
+}
+
+// call executes eth_call against contract, following EIP-3668 OffchainLookup
+// reverts through the fetcher and the contract's callback. Empty return data
+// fails with ErrNoContractData.
+func (e *ENS) call(ctx context.Context, contract common.Address, data []byte) ([]byte, error) {
+	for lookups := 0; ; lookups++ {
+		result, err := e.client.CallContract(ctx, "", contract.Hex(), data, "latest")
+		if err == nil {
+			if len(result) == 0 {
+				return nil, ErrNoContractData
+			}
+			return result, nil
+		}
+
+		revert, ok := RevertData(err)
+		if !ok || len(revert) < 4 || !bytes.Equal(revert[:4], offchainLookupError.ID()) {
+			return nil, err
+		}
+		if lookups == maxCCIPLookups {
+			return nil, fmt.Errorf("too many offchain lookups")
+		}
+
+		values, err := offchainLookupError.Inputs.Unpack(revert[4:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid OffchainLookup: %v", err)
+		}
+		sender := values[0].(common.Address)
+		if sender != contract {
+			return nil, fmt.Errorf("OffchainLookup sender %s does not match contract %s", sender.Hex(), contract.Hex())
+		}
+		var urls []string
+		for _, url := range values[1].([]interface{}) {
+			urls = append(urls, url.(string))
+		}
+		callData := values[2].([]byte)
+
+		response, err := e.fetchOffchain(ctx, urls, sender, callData)
+		if err != nil {
+			return nil, err
+		}
+
+		// Call the callback with the gateway response and the extra data
+		args, err := ccipCallbackArgs.Pack(response, values[4].([]byte))
+		if err != nil {
+			return nil, err
+		}
+		data = append(append([]byte(nil), values[3].([]byte)...), args...)
+	}
+}
+
+// fetchOffchain tries the gateway URLs in order and returns the first response.
+// As EIP-3668 requires, a 4xx response ends the lookup; only server and network
+// errors move on to the next URL.
+func (e *ENS) fetchOffchain(ctx context.Context, urls []string, sender common.Address, callData []byte) ([]byte, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("OffchainLookup has no gateway URLs")
+	}
+
+	var errs []error
+	for _, url := range urls {
+		response, err := e.fetcher(ctx, url, sender, callData)
+		if err == nil {
+			return response, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", url, err))
+
+		var statusErr *CCIPStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 {
+			break
+		}
+	}
+	return nil, fmt.Errorf("offchain lookup failed: %w", errors.Join(errs...))
+}
+
+// FetchCCIP queries an EIP-3668 gateway over HTTP. URLs containing {data} are
+// requested with GET after substituting {sender} and {data}; others receive a
+// POST with a JSON body holding both.
+func FetchCCIP(ctx context.Context, url string, sender common.Address, callData []byte) ([]byte, error) {
+	senderHex := strings.ToLower(sender.Hex())
+	dataHex := fmt.Sprintf("0x%x", callData)
+
+	var req *http.Request
+	var err error
+	if strings.Contains(url, "{data}") {
+		url = strings.NewReplacer("{sender}", senderHex, "{data}", dataHex).Replace(url)
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	} else {
+		url = strings.ReplaceAll(url, "{sender}", senderHex)
+		body, _ := json.Marshal(map[string]string{"data": dataHex, "sender": senderHex})
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	httpClient := &http.Client{Timeout: DefaultRPCTimeout, Transport: sharedTransport}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCCIPResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading gateway response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Gateways put the reason in {"message": ...}
+		var failure struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &failure)
+		return nil, &CCIPStatusError{StatusCode: resp.StatusCode, Message: failure.Message}
+	}
+
+	var response struct {
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("invalid gateway response: %v", err)
+	}
+	return HexDecode(response.Data)
+}
@@ -0,0 +1,236 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestNamehash checks namehash and DNS encoding against the ENS specification
+func TestNamehash(t *testing.T) {
+	vectors := map[string]string{
+		"":        "0000000000000000000000000000000000000000000000000000000000000000",
+		"eth":     "93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae",
+		"foo.eth": "de9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f",
+	}
+	for name, want := range vectors {
+		if got := hex.EncodeToString(Namehash(name).Bytes()); got != want {
+			t.Fatalf("Namehash(%q) = %s, expected %s", name, got, want)
+		}
+	}
+
+	if got, _ := DNSEncode("foo.eth"); hex.EncodeToString(got) != "03666f6f0365746800" {
+		t.Fatalf("Unexpected DNS encoding %x", got)
+	}
+	if got := hex.EncodeToString(mustMethod(ensResolverABI, "addr").ID()); got != "3b3b57de" {
+		t.Fatalf("Unexpected addr selector %s", got)
+	}
+	if got := hex.EncodeToString(offchainLookupError.ID()); got != "556f1830" {
+		t.Fatalf("Unexpected OffchainLookup selector %s", got)
+	}
+
+	if name, err := NormalizeENSName(" Vitalik.ETH "); err != nil || name != "vitalik.eth" {
+		t.Fatalf("NormalizeENSName = %q, %v", name, err)
+	}
+	if _, err := NormalizeENSName("foo..eth"); err == nil {
+		t.Fatalf("Expected an error for an empty label")
+	}
+	for s, want := range map[string]bool{"vitalik.eth": true, "sub.alice.xyz": true, vectorTo: false, "12345": false, "https://a.b": false} {
+		if IsENSName(s) != want {
+			t.Fatalf("IsENSName(%q) should be %v", s, want)
+		}
+	}
+}
+
+// TestENSResolve tests direct, wildcard and offchain resolution and reverse lookups against mock contracts
+func TestENSResolve(t *testing.T) {
+	registry := common.HexToAddress(DefaultENSRegistry)
+	plainResolver := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	wildcardResolver := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	offchainResolver := common.HexToAddress("0x00000000000000000000000000000000000000c3")
+
+	alice := common.HexToAddress(vectorTo)
+	wildcardTarget := common.HexToAddress("0x3535353535353535353535353535353535353535")
+	offchainTarget := common.HexToAddress("0x4242424242424242424242424242424242424242")
+	reverseName := strings.ToLower(alice.Hex()[2:]) + ".addr.reverse"
+
+	resolvers := map[common.Hash]common.Address{
+		Namehash("alice.eth"):    plainResolver,
+		Namehash(reverseName):    plainResolver,
+		Namehash("wild.eth"):     wildcardResolver,
+		Namehash("offchain.eth"): offchainResolver,
+	}
+	addressWord := func(addr common.Address) []byte {
+		return common.LeftPadBytes(addr.Bytes(), 32)
+	}
+	bytesResult := func(b []byte) string {
+		encoded, _ := mustMethod(ensResolverABI, "resolve").Outputs.Pack(b)
+		return "0x" + hex.EncodeToString(encoded)
+	}
+	callbackSelector := []byte{0x12, 0x34, 0x56, 0x78}
+
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		if method != "eth_call" {
+			return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+		}
+		var msg map[string]string
+		json.Unmarshal(params[0], &msg)
+		to := common.HexToAddress(msg["to"])
+		data, _ := HexDecode(msg["data"])
+		selector, args := data[:4], data[4:]
+
+		switch {
+		case to == registry:
+			return "0x" + hex.EncodeToString(addressWord(resolvers[common.BytesToHash(args[:32])])), nil
+		case bytes.Equal(selector, mustMethod(ensResolverABI, "supportsInterface").ID()):
+			extended := to != plainResolver && bytes.Equal(args[:4], extendedResolverInterfaceID[:])
+			if extended {
+				return "0x" + abiWord(1), nil
+			}
+			return "0x" + abiWord(0), nil
+		case to == plainResolver && bytes.Equal(selector, mustMethod(ensResolverABI, "addr").ID()):
+			if common.BytesToHash(args) == Namehash("alice.eth") {
+				return "0x" + hex.EncodeToString(addressWord(alice)), nil
+			}
+			return "0x" + abiWord(0), nil
+		case to == plainResolver && bytes.Equal(selector, mustMethod(ensResolverABI, "name").ID()):
+			return "0x" + abiString("alice.eth"), nil
+		case to == wildcardResolver && bytes.Equal(selector, mustMethod(ensResolverABI, "resolve").ID()):
+			values, _ := mustMethod(ensResolverABI, "resolve").Inputs.Unpack(args)
+			if dnsName, _ := DNSEncode("sub.wild.eth"); !bytes.Equal(values[0].([]byte), dnsName) {
+				return bytesResult(addressWord(common.Address{})), nil
+			}
+			return bytesResult(addressWord(wildcardTarget)), nil
+		case to == offchainResolver && bytes.Equal(selector, mustMethod(ensResolverABI, "resolve").ID()):
+			revert, _ := offchainLookupError.Inputs.Pack(offchainResolver, []string{"https://bad.example/{data}", "https://gateway.example/{sender}/{data}.json"}, []byte{0xde, 0xad}, callbackSelector, []byte{0xbe, 0xef})
+			return nil, &mockRPCError{Code: 3, Message: "execution reverted", Data: "0x" + hex.EncodeToString(append(offchainLookupError.ID(), revert...))}
+		case to == offchainResolver && bytes.Equal(selector, callbackSelector):
+			values, _ := ccipCallbackArgs.Unpack(args)
+			if string(values[0].([]byte)) != "signed" || !bytes.Equal(values[1].([]byte), []byte{0xbe, 0xef}) {
+				return nil, &mockRPCError{Code: 3, Message: "execution reverted", Data: "0x"}
+			}
+			return bytesResult(addressWord(offchainTarget)), nil
+		}
+		return "0x", nil
+	})
+
+	var fetched []string
+	fetcher := func(ctx context.Context, url string, sender common.Address, callData []byte) ([]byte, error) {
+		fetched = append(fetched, url)
+		if strings.HasPrefix(url, "https://bad.example") {
+			return nil, errors.New("gateway down")
+		}
+		if sender != offchainResolver || !bytes.Equal(callData, []byte{0xde, 0xad}) {
+			return nil, errors.New("unexpected lookup")
+		}
+		return []byte("signed"), nil
+	}
+
+	ctx := context.Background()
+	ens := NewENS(NewClient(rpc.URL), registry, WithCCIPFetcher(fetcher))
+
+	if got, err := ens.Resolve(ctx, "Alice.eth"); err != nil || got != alice {
+		t.Fatalf("Resolve(alice.eth) = %s, %v", got.Hex(), err)
+	}
+	if got, err := ens.Resolve(ctx, "sub.wild.eth"); err != nil || got != wildcardTarget {
+		t.Fatalf("Wildcard resolve = %s, %v", got.Hex(), err)
+	}
+	if got, err := ens.Resolve(ctx, "offchain.eth"); err != nil || got != offchainTarget {
+		t.Fatalf("Offchain resolve = %s, %v", got.Hex(), err)
+	}
+	if len(fetched) != 2 {
+		t.Fatalf("Expected the second gateway to be tried after the first failed, got %v", fetched)
+	}
+
+	// A plain resolver is not used for subnames, and unknown names have no resolver
+	if _, err := ens.Resolve(ctx, "bob.alice.eth"); !errors.Is(err, ErrENSNotFound) {
+		t.Fatalf("Expected ErrENSNotFound for a subname of a plain resolver, got %v", err)
+	}
+	if _, err := ens.Resolve(ctx, "nobody.eth"); !errors.Is(err, ErrENSNotFound) {
+		t.Fatalf("Expected ErrENSNotFound, got %v", err)
+	}
+
+	// The reverse record is returned because alice.eth resolves back to the address
+	if name, err := ens.LookupAddress(ctx, alice); err != nil || name != "alice.eth" {
+		t.Fatalf("LookupAddress = %q, %v", name, err)
+	}
+	if name, err := ens.LookupAddress(ctx, wildcardTarget); err != nil || name != "" {
+		t.Fatalf("Expected no name for an address without a reverse record, got %q, %v", name, err)
+	}
+}
+
+// TestFetchCCIP tests the EIP-3668 GET and POST gateway requests
+func TestFetchCCIP(t *testing.T) {
+	sender := common.HexToAddress(vectorTo)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Path != "/"+strings.ToLower(vectorTo)+"/0x0102.json" {
+				http.NotFound(w, r)
+				return
+			}
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			var request map[string]string
+			json.Unmarshal(body, &request)
+			if request["data"] != "0x0102" || request["sender"] != strings.ToLower(vectorTo) {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+		}
+		w.Write([]byte(`{"data":"0xabcd"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	for _, url := range []string{server.URL + "/{sender}/{data}.json", server.URL + "/lookup"} {
+		response, err := FetchCCIP(ctx, url, sender, []byte{1, 2})
+		if err != nil || hex.EncodeToString(response) != "abcd" {
+			t.Fatalf("FetchCCIP(%s) = %x, %v", url, response, err)
+		}
+	}
+	var statusErr *CCIPStatusError
+	if _, err := FetchCCIP(ctx, server.URL+"/missing/{data}", sender, []byte{1, 2}); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Expected a 404 CCIPStatusError for a failed gateway request, got %v", err)
+	}
+}
+
+// TestFetchOffchainGatewayErrors tests that a 4xx gateway response ends the lookup
+// while server and network errors move on to the next gateway
+func TestFetchOffchainGatewayErrors(t *testing.T) {
+	var fetched []string
+	fetcher := func(ctx context.Context, url string, sender common.Address, callData []byte) ([]byte, error) {
+		fetched = append(fetched, url)
+		switch url {
+		case "https://client-error.example":
+			return nil, &CCIPStatusError{StatusCode: http.StatusNotFound, Message: "name not found"}
+		case "https://server-error.example":
+			return nil, &CCIPStatusError{StatusCode: http.StatusServiceUnavailable}
+		case "https://down.example":
+			return nil, errors.New("connection refused")
+		}
+		return []byte("signed"), nil
+	}
+	ens := NewENS(NewClient("http://127.0.0.1:0"), common.Address{}, WithCCIPFetcher(fetcher))
+	ctx := context.Background()
+
+	response, err := ens.fetchOffchain(ctx, []string{"https://server-error.example", "https://down.example", "https://ok.example"}, common.Address{}, nil)
+	if err != nil || string(response) != "signed" || len(fetched) != 3 {
+		t.Fatalf("Expected the third gateway to answer, got %q, %v after %v", response, err, fetched)
+	}
+
+	fetched = nil
+	_, err = ens.fetchOffchain(ctx, []string{"https://client-error.example", "https://ok.example"}, common.Address{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "name not found") || len(fetched) != 1 {
+		t.Fatalf("Expected the lookup to stop at the 4xx response, got %v after %v", err, fetched)
+	}
+}
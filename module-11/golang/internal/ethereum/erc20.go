@@ -0,0 +1,200 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
+	"github.com/metana-bootcamp/ethwallet/internal/units"
+)
+
+// erc20ABI is the subset of the ERC-20 interface used by the wallet
+var erc20ABI = mustParseABI(
+	"function name() view returns (string)",
+	"function symbol() view returns (string)",
+	"function decimals() view returns (uint8)",
+	"function totalSupply() view returns (uint256)",
+	"function balanceOf(address owner) view returns (uint256)",
+	"function allowance(address owner, address spender) view returns (uint256)",
+	"function transfer(address to, uint256 amount) returns (bool)",
+	"function approve(address spender, uint256 amount) returns (bool)",
+	"function transferFrom(address from, address to, uint256 amount) returns (bool)",
+)
+
+// MaxUint256 is 2^256-1, the conventional "unlimited" approval amount
+var MaxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// ErrNoContractData is returned when a call returns no data, usually because
+// there is no contract at the address or it does not implement the method
+var ErrNoContractData = errors.New("call returned no data")
+
+// mustParseABI parses human-readable signatures known at compile time
+func mustParseABI(signatures ...string) *abi.ABI {
+	parsed, err := abi.ParseHumanReadable(signatures...)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// mustMethod returns a method of a compile-time ABI by name
+func mustMethod(contractABI *abi.ABI, name string) *abi.Method {
+	method, err := contractABI.MethodByName(name, -1)
+	if err != nil {
+		panic(err)
+	}
+	return method
+}
+
+// Token is an ERC-20 token contract
+type Token struct {
+	Address common.Address
+	client  *Client
+}
+
+// TokenInfo holds the metadata of an ERC-20 token
+type TokenInfo struct {
+	Address     common.Address
+	Name        string // empty when the token does not implement name()
+	Symbol      string // empty when the token does not implement symbol()
+	Decimals    uint8
+	TotalSupply *big.Int
+}
+
+// NewToken returns the ERC-20 token at address
+func NewToken(client *Client, address common.Address) *Token {
+	return &Token{Address: address, client: client}
+}
+
+// call executes a read-only ERC-20 method and returns its decoded outputs
+func (t *Token) call(ctx context.Context, name string, args ...interface{}) ([]interface{}, error) {
+	return t.client.callMethod(ctx, t.Address, mustMethod(erc20ABI, name), args...)
+}
+
+// callMethod executes a read-only contract method and returns its decoded outputs.
+// Empty return data fails with ErrNoContractData.
+func (c *Client) callMethod(ctx context.Context, contract common.Address, method *abi.Method, args ...interface{}) ([]interface{}, error) {
+	data, err := method.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.CallContract(ctx, "", contract.Hex(), data, "latest")
+	if err != nil {
+		return nil, fmt.Errorf("error calling %s: %w", method.Name, err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("error calling %s on %s: %w", method.Name, contract.Hex(), ErrNoContractData)
+	}
+	return method.Unpack(result)
+}
+
+// Info reads the token name, symbol, decimals and total supply in a single batch
+func (t *Token) Info(ctx context.Context) (*TokenInfo, error) {
+	names := []string{"name", "symbol", "decimals", "totalSupply"}
+	elems := make([]BatchElem, len(names))
+	for i, name := range names {
+		data, _ := mustMethod(erc20ABI, name).Pack()
+		msg := map[string]string{"to": t.Address.Hex(), "data": fmt.Sprintf("0x%x", data)}
+		elems[i] = BatchElem{Method: "eth_call", Params: []interface{}{msg, "latest"}}
+	}
+
+	if err := t.client.BatchCall(ctx, elems); err != nil {
+		return nil, fmt.Errorf("error reading token metadata: %w", err)
+	}
+
+	results := make([][]byte, len(elems))
+	for i, elem := range elems {
+		if elem.Error != nil {
+			continue
+		}
+		var resultHex string
+		if err := json.Unmarshal(elem.Result, &resultHex); err == nil {
+			results[i], _ = HexDecode(resultHex)
+		}
+	}
+
+	info := &TokenInfo{Address: t.Address}
+
+	// name and symbol are optional; some early tokens return bytes32
+	info.Name = decodeTokenString(results[0])
+	info.Symbol = decodeTokenString(results[1])
+
+	if len(results[2]) == 0 {
+		return nil, fmt.Errorf("token %s does not implement decimals(): %w", t.Address.Hex(), ErrNoContractData)
+	}
+	decimals, err := mustMethod(erc20ABI, "decimals").Unpack(results[2])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding decimals: %w", err)
+	}
+	info.Decimals = uint8(decimals[0].(*big.Int).Uint64())
+
+	if supply, err := mustMethod(erc20ABI, "totalSupply").Unpack(results[3]); err == nil {
+		info.TotalSupply = supply[0].(*big.Int)
+	}
+
+	return info, nil
+}
+
+// decodeTokenString decodes a string returned by name() or symbol(), accepting
+// the bytes32 values returned by tokens that predate the final ERC-20 standard
+func decodeTokenString(data []byte) string {
+	if values, err := mustMethod(erc20ABI, "name").Unpack(data); err == nil {
+		return values[0].(string)
+	}
+	if len(data) == 32 {
+		return strings.TrimRight(string(data), "\x00")
+	}
+	return ""
+}
+
+// BalanceOf returns the token balance of owner in base units
+func (t *Token) BalanceOf(ctx context.Context, owner common.Address) (*big.Int, error) {
+	values, err := t.call(ctx, "balanceOf", owner)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// Allowance returns how many base units spender may transfer from owner
+func (t *Token) Allowance(ctx context.Context, owner, spender common.Address) (*big.Int, error) {
+	values, err := t.call(ctx, "allowance", owner, spender)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// TransferData encodes transfer(to, amount) calldata
+func TransferData(to common.Address, amount *big.Int) ([]byte, error) {
+	return mustMethod(erc20ABI, "transfer").Pack(to, amount)
+}
+
+// ApproveData encodes approve(spender, amount) calldata
+func ApproveData(spender common.Address, amount *big.Int) ([]byte, error) {
+	return mustMethod(erc20ABI, "approve").Pack(spender, amount)
+}
+
+// TransferFromData encodes transferFrom(from, to, amount) calldata
+func TransferFromData(from, to common.Address, amount *big.Int) ([]byte, error) {
+	return mustMethod(erc20ABI, "transferFrom").Pack(from, to, amount)
+}
+
+// FormatTokenAmount formats an amount in base units using the token's decimals,
+// without rounding or trailing zeros
+func FormatTokenAmount(amount *big.Int, decimals uint8) string {
+	return units.Format(amount, decimals)
+}
+
+// ParseTokenAmount parses a decimal amount such as "1.5" into base units using
+// the token's decimals. More fractional digits than decimals are rejected.
+func ParseTokenAmount(s string, decimals uint8) (*big.Int, error) {
+	return units.Parse(s, decimals)
+}
@@ -0,0 +1,152 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// abiWord encodes a number as a 32-byte ABI word
+func abiWord(n uint64) string {
+	return fmt.Sprintf("%064x", n)
+}
+
+// abiString encodes a single ABI string return value
+func abiString(s string) string {
+	padded := make([]byte, (len(s)+31)/32*32)
+	copy(padded, s)
+	return abiWord(32) + abiWord(uint64(len(s))) + hex.EncodeToString(padded)
+}
+
+// TestTokenAmounts tests formatting and parsing amounts with token decimals
+func TestTokenAmounts(t *testing.T) {
+	formats := []struct {
+		amount   string
+		decimals uint8
+		want     string
+	}{
+		{"1500000", 6, "1.5"},
+		{"1000000000000000000", 18, "1"},
+		{"1", 18, "0.000000000000000001"},
+		{"0", 6, "0"},
+		{"123456789", 0, "123456789"},
+		{"100010", 2, "1000.1"},
+	}
+	for _, f := range formats {
+		amount, _ := new(big.Int).SetString(f.amount, 10)
+		if got := FormatTokenAmount(amount, f.decimals); got != f.want {
+			t.Fatalf("FormatTokenAmount(%s, %d) = %s, expected %s", f.amount, f.decimals, got, f.want)
+		}
+
+		// Formatting and parsing round-trip
+		parsed, err := ParseTokenAmount(f.want, f.decimals)
+		if err != nil || parsed.Cmp(amount) != 0 {
+			t.Fatalf("ParseTokenAmount(%s, %d) = %v, %v", f.want, f.decimals, parsed, err)
+		}
+	}
+
+	if parsed, err := ParseTokenAmount(".25", 2); err != nil || parsed.Int64() != 25 {
+		t.Fatalf("ParseTokenAmount(.25) = %v, %v", parsed, err)
+	}
+	for _, bad := range []string{"", ".", "1.", "1.2345", "-1", "1e6", "0x10", "1.2.3"} {
+		if _, err := ParseTokenAmount(bad, 3); err == nil {
+			t.Fatalf("ParseTokenAmount(%q) should fail", bad)
+		}
+	}
+}
+
+// TestTokenCalldata checks ERC-20 calldata against known encodings
+func TestTokenCalldata(t *testing.T) {
+	to := common.HexToAddress(vectorTo)
+	amount := big.NewInt(1000)
+
+	data, err := TransferData(to, amount)
+	if err != nil {
+		t.Fatalf("Failed to encode transfer: %v", err)
+	}
+	if got := hex.EncodeToString(data); got != "a9059cbb000000000000000000000000de9ca654ae5a3673d894eba15b63603fa00f850400000000000000000000000000000000000000000000000000000000000003e8" {
+		t.Fatalf("Unexpected transfer calldata %s", got)
+	}
+
+	data, _ = ApproveData(to, MaxUint256)
+	if got := hex.EncodeToString(data); got != "095ea7b3000000000000000000000000de9ca654ae5a3673d894eba15b63603fa00f8504"+strings.Repeat("f", 64) {
+		t.Fatalf("Unexpected approve calldata %s", got)
+	}
+
+	data, _ = TransferFromData(to, to, amount)
+	if got := hex.EncodeToString(data[:4]); got != "23b872dd" || len(data) != 4+3*32 {
+		t.Fatalf("Unexpected transferFrom calldata %x", data)
+	}
+}
+
+// TestTokenReads tests metadata, balance and allowance calls against a mock token
+func TestTokenReads(t *testing.T) {
+	owner := common.HexToAddress(vectorTo)
+	responses := map[string]string{
+		"0x06fdde03": abiString("USD Coin"),
+		"0x95d89b41": "4d4b520000000000000000000000000000000000000000000000000000000000", // bytes32 "MKR"
+		"0x313ce567": abiWord(6),
+		"0x18160ddd": abiWord(1_000_000_000_000),
+	}
+
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		if method != "eth_call" {
+			return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+		}
+		var msg map[string]string
+		json.Unmarshal(params[0], &msg)
+
+		switch {
+		case msg["to"] == "0x0000000000000000000000000000000000000001":
+			return "0x", nil // not a contract
+		case strings.HasPrefix(msg["data"], "0x70a08231"):
+			return "0x" + abiWord(2_500_000), nil
+		case strings.HasPrefix(msg["data"], "0xdd62ed3e"):
+			return "0x" + strings.Repeat("f", 64), nil
+		}
+		if result, ok := responses[msg["data"]]; ok {
+			return "0x" + result, nil
+		}
+		return nil, &mockRPCError{Code: 3, Message: "execution reverted", Data: "0x"}
+	})
+
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+	token := NewToken(client, common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"))
+
+	info, err := token.Info(ctx)
+	if err != nil {
+		t.Fatalf("Failed to read token info: %v", err)
+	}
+	if info.Name != "USD Coin" || info.Symbol != "MKR" || info.Decimals != 6 || info.TotalSupply.Int64() != 1_000_000_000_000 {
+		t.Fatalf("Unexpected token info %+v", info)
+	}
+	if rpc.requestCount() != 1 {
+		t.Fatalf("Expected metadata in one batch, got %d requests", rpc.requestCount())
+	}
+
+	balance, err := token.BalanceOf(ctx, owner)
+	if err != nil || FormatTokenAmount(balance, info.Decimals) != "2.5" {
+		t.Fatalf("Unexpected balance %v: %v", balance, err)
+	}
+	allowance, err := token.Allowance(ctx, owner, owner)
+	if err != nil || allowance.Cmp(MaxUint256) != 0 {
+		t.Fatalf("Unexpected allowance %v: %v", allowance, err)
+	}
+
+	// An address without code returns no data
+	missing := NewToken(client, common.HexToAddress("0x0000000000000000000000000000000000000001"))
+	if _, err := missing.BalanceOf(ctx, owner); !errors.Is(err, ErrNoContractData) {
+		t.Fatalf("Expected ErrNoContractData, got %v", err)
+	}
+	if _, err := missing.Info(ctx); !errors.Is(err, ErrNoContractData) {
+		t.Fatalf("Expected ErrNoContractData, got %v", err)
+	}
+}
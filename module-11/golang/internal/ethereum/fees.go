@@ -0,0 +1,237 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// FeeSpeed selects an EIP-1559 fee preset
+type FeeSpeed string
+
+const (
+	FeeSlow     FeeSpeed = "slow"
+	FeeStandard FeeSpeed = "standard"
+	FeeFast     FeeSpeed = "fast"
+)
+
+// ErrFeeCapExceeded is returned instead of signing when a transaction's max fee
+// (or gas price) is above the cap set with WithFeeCap
+var ErrFeeCapExceeded = errors.New("fee cap exceeded")
+
+// feeHistoryBlocks is the number of recent blocks sampled with eth_feeHistory
+const feeHistoryBlocks = 20
+
+// feeHistoryPercentiles are the reward percentiles behind the slow, standard and fast tips
+var feeHistoryPercentiles = []float64{10, 50, 90}
+
+// baseFeeMultipliers scale the pending base fee into each preset's max fee, in
+// percent. A full block raises the base fee by 12.5%, so 200% covers about six
+// full blocks in a row.
+var baseFeeMultipliers = map[FeeSpeed]int64{
+	FeeSlow:     125,
+	FeeStandard: 200,
+	FeeFast:     250,
+}
+
+// ParseFeeSpeed parses "slow", "standard" or "fast"
+func ParseFeeSpeed(s string) (FeeSpeed, error) {
+	speed := FeeSpeed(strings.ToLower(s))
+	if _, ok := baseFeeMultipliers[speed]; !ok {
+		return "", fmt.Errorf("unknown fee speed %q (use slow, standard or fast)", s)
+	}
+	return speed, nil
+}
+
+// FeeEstimate is the suggested fee of one speed preset
+type FeeEstimate struct {
+	Speed                FeeSpeed
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerGas         *big.Int
+}
+
+// FeeSuggestion holds the EIP-1559 fee presets derived from recent blocks
+type FeeSuggestion struct {
+	BaseFee  *big.Int // base fee of the pending block
+	NodeTip  *big.Int // eth_maxPriorityFeePerGas, nil when the node does not support it
+	Slow     *FeeEstimate
+	Standard *FeeEstimate
+	Fast     *FeeEstimate
+}
+
+// Preset returns the estimate for a speed
+func (s *FeeSuggestion) Preset(speed FeeSpeed) *FeeEstimate {
+	switch speed {
+	case FeeSlow:
+		return s.Slow
+	case FeeFast:
+		return s.Fast
+	}
+	return s.Standard
+}
+
+// MaxFee returns the max fee per gas of a speed preset with the given tip: the
+// pending base fee scaled by the preset's multiplier, plus the tip
+func (s *FeeSuggestion) MaxFee(speed FeeSpeed, tip *big.Int) *big.Int {
+	multiplier, ok := baseFeeMultipliers[speed]
+	if !ok {
+		multiplier = baseFeeMultipliers[FeeStandard]
+	}
+	maxFee := new(big.Int).Mul(s.BaseFee, big.NewInt(multiplier))
+	maxFee.Div(maxFee, big.NewInt(100))
+	return maxFee.Add(maxFee, tip)
+}
+
+// feeHistory is the result of eth_feeHistory
+type feeHistory struct {
+	BaseFeePerGas []string   `json:"baseFeePerGas"`
+	GasUsedRatio  []float64  `json:"gasUsedRatio"`
+	Reward        [][]string `json:"reward"`
+}
+
+// SuggestFees derives slow, standard and fast fees from the priority fees paid
+// in recent blocks (eth_feeHistory). The standard tip is the node's
+// eth_maxPriorityFeePerGas when it supports it. There is no fallback: nodes
+// without eth_feeHistory return an error.
+func (c *Client) SuggestFees(ctx context.Context) (*FeeSuggestion, error) {
+	elems := []BatchElem{
+		{Method: "eth_feeHistory", Params: []interface{}{fmt.Sprintf("0x%x", feeHistoryBlocks), "latest", feeHistoryPercentiles}},
+		{Method: "eth_maxPriorityFeePerGas", Params: []interface{}{}},
+	}
+	if err := c.BatchCall(ctx, elems); err != nil {
+		return nil, fmt.Errorf("error getting fee history: %w", err)
+	}
+	if elems[0].Error != nil {
+		return nil, fmt.Errorf("error getting fee history: %w", elems[0].Error)
+	}
+
+	var history feeHistory
+	if err := json.Unmarshal(elems[0].Result, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse fee history: %v", err)
+	}
+	if len(history.BaseFeePerGas) == 0 {
+		return nil, fmt.Errorf("fee history has no base fees; the network may not support EIP-1559")
+	}
+
+	// The last base fee is the one of the next block
+	baseFee, err := HexToBig(history.BaseFeePerGas[len(history.BaseFeePerGas)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid base fee in fee history: %v", err)
+	}
+
+	tips, err := percentileTips(history)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion := &FeeSuggestion{BaseFee: baseFee}
+	if elems[1].Error == nil {
+		if nodeTip, err := HexToBig(string(elems[1].Result)); err == nil {
+			suggestion.NodeTip = nodeTip
+			tips[1] = nodeTip
+		}
+	}
+
+	// Keep the presets ordered even when the node's tip is outside the sampled range
+	if tips[0].Cmp(tips[1]) > 0 {
+		tips[0] = tips[1]
+	}
+	if tips[2].Cmp(tips[1]) < 0 {
+		tips[2] = tips[1]
+	}
+
+	for i, speed := range []FeeSpeed{FeeSlow, FeeStandard, FeeFast} {
+		estimate := &FeeEstimate{Speed: speed, MaxPriorityFeePerGas: tips[i], MaxFeePerGas: suggestion.MaxFee(speed, tips[i])}
+		switch speed {
+		case FeeSlow:
+			suggestion.Slow = estimate
+		case FeeStandard:
+			suggestion.Standard = estimate
+		case FeeFast:
+			suggestion.Fast = estimate
+		}
+	}
+	return suggestion, nil
+}
+
+// percentileTips returns the median reward of each percentile across the sampled
+// blocks, skipping empty blocks whose rewards are all zero
+func percentileTips(history feeHistory) ([]*big.Int, error) {
+	samples := make([][]*big.Int, len(feeHistoryPercentiles))
+	for block, rewards := range history.Reward {
+		if block < len(history.GasUsedRatio) && history.GasUsedRatio[block] == 0 {
+			continue
+		}
+		if len(rewards) != len(feeHistoryPercentiles) {
+			return nil, fmt.Errorf("fee history has %d rewards per block, expected %d", len(rewards), len(feeHistoryPercentiles))
+		}
+		for i, reward := range rewards {
+			tip, err := HexToBig(reward)
+			if err != nil {
+				return nil, fmt.Errorf("invalid reward in fee history: %v", err)
+			}
+			samples[i] = append(samples[i], tip)
+		}
+	}
+
+	tips := make([]*big.Int, len(samples))
+	for i, values := range samples {
+		tips[i] = median(values)
+	}
+	return tips, nil
+}
+
+// median returns the median of values, or zero when there are none
+func median(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return big.NewInt(0)
+	}
+	sorted := append([]*big.Int(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return new(big.Int).Set(sorted[mid])
+	}
+	sum := new(big.Int).Add(sorted[mid-1], sorted[mid])
+	return sum.Div(sum, big.NewInt(2))
+}
+
+// eip1559Fees returns the priority fee and max fee of a type-2 transaction. Fees
+// that are not given come from the WithFeeSpeed preset of SuggestFees; the result
+// is checked against the WithFeeCap cap.
+func (c *Client) eip1559Fees(ctx context.Context, priorityFee *big.Int, o *sendOptions) (*big.Int, *big.Int, error) {
+	tip, maxFee := priorityFee, o.maxFeePerGas
+	if tip == nil || maxFee == nil {
+		suggestion, err := c.SuggestFees(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error estimating fees: %w", err)
+		}
+		if tip == nil {
+			tip = suggestion.Preset(o.feeSpeed).MaxPriorityFeePerGas
+		}
+		if maxFee == nil {
+			maxFee = suggestion.MaxFee(o.feeSpeed, tip)
+		}
+	}
+
+	if maxFee.Cmp(tip) < 0 {
+		return nil, nil, fmt.Errorf("max fee %s wei is below the priority fee %s wei", maxFee, tip)
+	}
+	if err := checkFeeCap(maxFee, o.feeCap); err != nil {
+		return nil, nil, err
+	}
+	return tip, maxFee, nil
+}
+
+// checkFeeCap fails with ErrFeeCapExceeded when fee is above a non-nil cap
+func checkFeeCap(fee, feeCap *big.Int) error {
+	if feeCap != nil && fee.Cmp(feeCap) > 0 {
+		return fmt.Errorf("%w: %s wei per gas is above the cap of %s wei", ErrFeeCapExceeded, fee, feeCap)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// mockFeeHistory is an eth_feeHistory result of four blocks, one of them empty,
+// with a pending base fee of 1 gwei
+func mockFeeHistory() map[string]interface{} {
+	return map[string]interface{}{
+		"oldestBlock":   "0x10",
+		"baseFeePerGas": []string{"0x3b9aca00", "0x3b9aca00", "0x3b9aca00", "0x3b9aca00", "0x3b9aca00"},
+		"gasUsedRatio":  []float64{0.5, 0, 0.9, 0.3},
+		"reward": [][]string{
+			{"0x5f5e100", "0x3b9aca00", "0x77359400"},  // 0.1, 1, 2 gwei
+			{"0x0", "0x0", "0x0"},                      // empty block, ignored
+			{"0xbebc200", "0x59682f00", "0xb2d05e00"},  // 0.2, 1.5, 3 gwei
+			{"0x11e1a300", "0x77359400", "0xee6b2800"}, // 0.3, 2, 4 gwei
+		},
+	}
+}
+
+// TestSuggestFees tests the fee presets derived from eth_feeHistory, with and without eth_maxPriorityFeePerGas
+func TestSuggestFees(t *testing.T) {
+	nodeTip := ""
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_feeHistory":
+			return mockFeeHistory(), nil
+		case "eth_maxPriorityFeePerGas":
+			if nodeTip != "" {
+				return nodeTip, nil
+			}
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+
+	suggestion, err := client.SuggestFees(ctx)
+	if err != nil {
+		t.Fatalf("Failed to suggest fees: %v", err)
+	}
+	if suggestion.BaseFee.Int64() != 1_000_000_000 || suggestion.NodeTip != nil {
+		t.Fatalf("Unexpected base fee %s or node tip %v", suggestion.BaseFee, suggestion.NodeTip)
+	}
+
+	// Tips are the medians of the non-empty blocks; max fees scale the base fee per preset
+	expected := map[FeeSpeed][2]int64{
+		FeeSlow:     {200_000_000, 1_450_000_000},
+		FeeStandard: {1_500_000_000, 3_500_000_000},
+		FeeFast:     {3_000_000_000, 5_500_000_000},
+	}
+	for speed, want := range expected {
+		preset := suggestion.Preset(speed)
+		if preset.Speed != speed || preset.MaxPriorityFeePerGas.Int64() != want[0] || preset.MaxFeePerGas.Int64() != want[1] {
+			t.Fatalf("%s preset = %s/%s, expected %d/%d", speed, preset.MaxPriorityFeePerGas, preset.MaxFeePerGas, want[0], want[1])
+		}
+	}
+
+	// The node's tip replaces the standard tip, and the other presets stay ordered around it
+	nodeTip = "0xdf8475800" // 60 gwei
+	suggestion, err = client.SuggestFees(ctx)
+	if err != nil {
+		t.Fatalf("Failed to suggest fees: %v", err)
+	}
+	if suggestion.NodeTip.Int64() != 60_000_000_000 || suggestion.Standard.MaxPriorityFeePerGas.Cmp(suggestion.NodeTip) != 0 {
+		t.Fatalf("Expected the node tip as the standard tip, got %s", suggestion.Standard.MaxPriorityFeePerGas)
+	}
+	if suggestion.Fast.MaxPriorityFeePerGas.Cmp(suggestion.NodeTip) != 0 || suggestion.Slow.MaxPriorityFeePerGas.Int64() != 200_000_000 {
+		t.Fatalf("Unexpected slow/fast tips %s/%s", suggestion.Slow.MaxPriorityFeePerGas, suggestion.Fast.MaxPriorityFeePerGas)
+	}
+
+	if speed, err := ParseFeeSpeed("FAST"); err != nil || speed != FeeFast {
+		t.Fatalf("ParseFeeSpeed(FAST) = %q, %v", speed, err)
+	}
+	if _, err := ParseFeeSpeed("turbo"); err == nil {
+		t.Fatalf("Expected an error for an unknown speed")
+	}
+}
+
+// TestSuggestFeesUnsupported tests that a node without eth_feeHistory is an error rather than a guess
+func TestSuggestFeesUnsupported(t *testing.T) {
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	if _, err := NewClient(rpc.URL).SuggestFees(context.Background()); err == nil {
+		t.Fatalf("Expected an error without eth_feeHistory")
+	}
+}
+
+// TestSendFeesAndCap tests fee overrides and that fees above the cap are never signed
+func TestSendFeesAndCap(t *testing.T) {
+	var rawTxs [][]byte
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_chainId":
+			return "0xaa36a7", nil
+		case "eth_getTransactionCount":
+			return "0x0", nil
+		case "eth_estimateGas":
+			return "0x5208", nil
+		case "eth_getBlockByNumber":
+			return map[string]string{"baseFeePerGas": "0x3b9aca00"}, nil
+		case "eth_feeHistory":
+			return mockFeeHistory(), nil
+		case "eth_gasPrice":
+			return "0x77359400", nil
+		case "eth_sendRawTransaction":
+			var rawHex string
+			json.Unmarshal(params[0], &rawHex)
+			raw, _ := HexDecode(rawHex)
+			rawTxs = append(rawTxs, raw)
+			return testTxHash, nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+
+	// Missing fees come from the standard preset
+	if _, err := client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(1), nil); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+	if tip, maxFee := decodeFees(t, rawTxs[0]); tip != 1_500_000_000 || maxFee != 3_500_000_000 {
+		t.Fatalf("Unexpected oracle fees %d/%d", tip, maxFee)
+	}
+
+	// Explicit fees skip the oracle
+	history := rpc.callCount("eth_feeHistory")
+	if _, err := client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(1), big.NewInt(2_000_000_000), WithMaxFeePerGas(big.NewInt(9_000_000_000))); err != nil {
+		t.Fatalf("Failed to send with explicit fees: %v", err)
+	}
+	if tip, maxFee := decodeFees(t, rawTxs[1]); tip != 2_000_000_000 || maxFee != 9_000_000_000 {
+		t.Fatalf("Unexpected explicit fees %d/%d", tip, maxFee)
+	}
+	if rpc.callCount("eth_feeHistory") != history {
+		t.Fatalf("Fee history was queried although both fees were given")
+	}
+
+	// A tip above the max fee and fees above the cap are refused before signing
+	if _, err := client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(1), big.NewInt(5_000_000_000), WithMaxFeePerGas(big.NewInt(4_000_000_000))); err == nil {
+		t.Fatalf("Expected an error for a tip above the max fee")
+	}
+	_, err = client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(1), nil, WithFeeCap(big.NewInt(3_000_000_000)))
+	if !errors.Is(err, ErrFeeCapExceeded) {
+		t.Fatalf("Expected ErrFeeCapExceeded, got %v", err)
+	}
+	_, err = client.SendTransaction(ctx, keyPair, vectorTo, big.NewInt(1), WithFeeCap(big.NewInt(1_000_000_000)))
+	if !errors.Is(err, ErrFeeCapExceeded) {
+		t.Fatalf("Expected ErrFeeCapExceeded for the gas price, got %v", err)
+	}
+	if len(rawTxs) != 2 {
+		t.Fatalf("Expected only two broadcasts, got %d", len(rawTxs))
+	}
+
+	// A given gas price replaces eth_gasPrice
+	gasPrices := rpc.callCount("eth_gasPrice")
+	if _, err := client.SendTransaction(ctx, keyPair, vectorTo, big.NewInt(1), WithGasPrice(big.NewInt(3_000_000_000)), WithFeeCap(big.NewInt(3_000_000_000))); err != nil {
+		t.Fatalf("Failed to send with a gas price: %v", err)
+	}
+	if rpc.callCount("eth_gasPrice") != gasPrices {
+		t.Fatalf("eth_gasPrice was queried although a gas price was given")
+	}
+
+	// WithFeeSpeed selects the preset for missing fees
+	if _, err := client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(1), nil, WithFeeSpeed(FeeFast)); err != nil {
+		t.Fatalf("Failed to send with the fast preset: %v", err)
+	}
+	if tip, maxFee := decodeFees(t, rawTxs[len(rawTxs)-1]); tip != 3_000_000_000 || maxFee != 5_500_000_000 {
+		t.Fatalf("Unexpected fast fees %d/%d", tip, maxFee)
+	}
+}
+
+// decodeFees returns the priority fee and max fee of a signed type-2 transaction
+func decodeFees(t *testing.T, raw []byte) (int64, int64) {
+	t.Helper()
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(raw[1:], &fields); err != nil {
+		t.Fatalf("Failed to decode type-2 transaction: %v", err)
+	}
+	var tip, maxFee big.Int
+	rlp.DecodeBytes(fields[2], &tip)
+	rlp.DecodeBytes(fields[3], &maxFee)
+	return tip.Int64(), maxFee.Int64()
+}
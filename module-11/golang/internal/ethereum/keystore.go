@@ -0,0 +1,376 @@
+package ethereum
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Scrypt parameters for keystore encryption
+const (
+	// StandardScryptN is the N parameter used by geth and MetaMask (256MB memory, ~1s CPU)
+	StandardScryptN = 1 << 18
+	// StandardScryptP is the P parameter used by geth and MetaMask
+	StandardScryptP = 1
+
+	// LightScryptN is the N parameter for low-memory environments (4MB memory, ~100ms CPU)
+	LightScryptN = 1 << 12
+	// LightScryptP is the P parameter for low-memory environments
+	LightScryptP = 6
+
+	keystoreVersion = 3
+	scryptR         = 8
+	scryptDKLen     = 32
+
+	// Upper bounds on the scrypt parameters read from a keystore file, checked
+	// before deriving the key so a crafted file cannot exhaust memory or CPU
+	maxScryptN = 1 << 20
+	maxScryptR = 8
+	maxScryptP = 16
+
+	// maxPBKDF2Iterations bounds the pbkdf2 iteration count (geth writes 262144)
+	maxPBKDF2Iterations = 10_000_000
+	// maxKDFKeyLen bounds the derived key length; only 32 bytes are used
+	maxKDFKeyLen = 64
+)
+
+// ErrKeystoreDecrypt is returned when the MAC check fails (usually a wrong passphrase)
+var ErrKeystoreDecrypt = errors.New("could not decrypt key with given passphrase")
+
+// keystoreJSON is the Web3 Secret Storage (version 3) file format
+type keystoreJSON struct {
+	Address string         `json:"address"`
+	Crypto  keystoreCrypto `json:"crypto"`
+	ID      string         `json:"id"`
+	Version int            `json:"version"`
+}
+
+// keystoreCrypto holds the cipher and KDF sections of a keystore file
+type keystoreCrypto struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams keystoreCipherParams   `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+// keystoreCipherParams holds the AES-CTR initialisation vector
+type keystoreCipherParams struct {
+	IV string `json:"iv"`
+}
+
+// EncryptKey encrypts a key pair into Web3 Secret Storage (keystore v3) JSON
+// using scrypt and AES-128-CTR
+func EncryptKey(keyPair *KeyPair, passphrase string, scryptN, scryptP int) ([]byte, error) {
+	// Random salt for the KDF
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	// Random IV for AES-CTR
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("failed to generate IV: %w", err)
+	}
+
+	// Encrypt the 32-byte private key with the first half of the derived key
+	privateKeyBytes := crypto.FromECDSA(keyPair.PrivateKey)
+	cipherText, err := aesCTRXOR(derivedKey[:16], privateKeyBytes, iv)
+	if err != nil {
+		return nil, err
+	}
+
+	// MAC = keccak256(derivedKey[16:32] || ciphertext)
+	mac := Keccak256(append(append([]byte{}, derivedKey[16:32]...), cipherText...))
+
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	ks := keystoreJSON{
+		Address: strings.ToLower(strings.TrimPrefix(keyPair.Address.Hex(), "0x")),
+		Crypto: keystoreCrypto{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: keystoreCipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams: map[string]interface{}{
+				"n":     scryptN,
+				"r":     scryptR,
+				"p":     scryptP,
+				"dklen": scryptDKLen,
+				"salt":  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(mac),
+		},
+		ID:      id,
+		Version: keystoreVersion,
+	}
+
+	return json.MarshalIndent(ks, "", "  ")
+}
+
+// DecryptKey decrypts Web3 Secret Storage (keystore v3) JSON with the given passphrase.
+// Both scrypt and pbkdf2 (hmac-sha256) key derivation are supported.
+func DecryptKey(keyJSON []byte, passphrase string) (*KeyPair, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal(keyJSON, &ks); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore JSON: %w", err)
+	}
+
+	if ks.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version: %d", ks.Version)
+	}
+
+	if ks.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("unsupported cipher: %s", ks.Crypto.Cipher)
+	}
+
+	derivedKey, err := deriveKeystoreKey(ks.Crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+
+	mac, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mac: %w", err)
+	}
+
+	// Verify the MAC before decrypting
+	calculatedMAC := Keccak256(append(append([]byte{}, derivedKey[16:32]...), cipherText...))
+	if !bytes.Equal(calculatedMAC, mac) {
+		return nil, ErrKeystoreDecrypt
+	}
+
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("invalid iv: %w", err)
+	}
+
+	privateKeyBytes, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := crypto.ToECDSA(privateKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key in keystore: %w", err)
+	}
+
+	keyPair := &KeyPair{
+		PrivateKey: privateKey,
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+
+	// The address field is optional, but if present it must match the key
+	if ks.Address != "" && !strings.EqualFold(common.HexToAddress(ks.Address).Hex(), keyPair.Address.Hex()) {
+		return nil, fmt.Errorf("keystore address %s does not match decrypted key %s", ks.Address, keyPair.Address.Hex())
+	}
+
+	return keyPair, nil
+}
+
+// SaveKeystore encrypts a key pair and writes it to dir using geth's
+// UTC--<timestamp>--<address> naming convention. It returns the file path.
+func SaveKeystore(keyPair *KeyPair, dir, passphrase string, scryptN, scryptP int) (string, error) {
+	keyJSON, err := EncryptKey(keyPair, passphrase, scryptN, scryptP)
+	if err != nil {
+		return "", fmt.Errorf("error encrypting key: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+
+	filename := filepath.Join(dir, keystoreFileName(keyPair.Address, time.Now().UTC()))
+	if err := os.WriteFile(filename, keyJSON, 0600); err != nil {
+		return "", fmt.Errorf("failed to write keystore file: %w", err)
+	}
+
+	return filename, nil
+}
+
+// LoadKeystore reads and decrypts a keystore file
+func LoadKeystore(filename, passphrase string) (*KeyPair, error) {
+	keyJSON, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %w", err)
+	}
+
+	return DecryptKey(keyJSON, passphrase)
+}
+
+// FindKeystoreFile locates the keystore file for an address inside a keystore directory.
+// If account is empty and the directory holds exactly one keystore file, that file is returned.
+func FindKeystoreFile(dir, account string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read keystore directory: %w", err)
+	}
+
+	want := strings.ToLower(strings.TrimPrefix(account, "0x"))
+
+	var matches []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		addr, err := keystoreFileAddress(path)
+		if err != nil {
+			// Skip files that aren't keystores
+			continue
+		}
+
+		if want == "" || addr == want {
+			matches = append(matches, path)
+		}
+	}
+
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) == 0 && want != "":
+		return "", fmt.Errorf("no keystore file found for account %s in %s", account, dir)
+	case len(matches) == 0:
+		return "", fmt.Errorf("no keystore files found in %s", dir)
+	case want == "":
+		return "", fmt.Errorf("multiple keystore files found in %s, specify an account", dir)
+	default:
+		return "", fmt.Errorf("multiple keystore files found for account %s in %s", account, dir)
+	}
+}
+
+// keystoreFileAddress reads the address field of a keystore file (lowercase, no 0x)
+func keystoreFileAddress(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var ks keystoreJSON
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return "", err
+	}
+	if ks.Version != keystoreVersion || ks.Address == "" {
+		return "", errors.New("not a keystore v3 file")
+	}
+
+	return strings.ToLower(strings.TrimPrefix(ks.Address, "0x")), nil
+}
+
+// keystoreFileName returns the geth-style file name for a keystore file
+func keystoreFileName(address common.Address, t time.Time) string {
+	ts := t.Format("2006-01-02T15-04-05.000000000Z")
+	return fmt.Sprintf("UTC--%s--%s", ts, strings.ToLower(strings.TrimPrefix(address.Hex(), "0x")))
+}
+
+// deriveKeystoreKey runs the KDF described in the keystore crypto section
+func deriveKeystoreKey(c keystoreCrypto, passphrase string) ([]byte, error) {
+	saltHex, ok := c.KDFParams["salt"].(string)
+	if !ok {
+		return nil, errors.New("missing kdf salt")
+	}
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kdf salt: %w", err)
+	}
+
+	dkLen := kdfParamInt(c.KDFParams, "dklen")
+	if dkLen < 32 || dkLen > maxKDFKeyLen {
+		return nil, fmt.Errorf("invalid kdf dklen %d: must be between 32 and %d", dkLen, maxKDFKeyLen)
+	}
+
+	switch c.KDF {
+	case "scrypt":
+		n := kdfParamInt(c.KDFParams, "n")
+		r := kdfParamInt(c.KDFParams, "r")
+		p := kdfParamInt(c.KDFParams, "p")
+		if n > maxScryptN || r > maxScryptR || p > maxScryptP {
+			return nil, fmt.Errorf("scrypt parameters n=%d, r=%d, p=%d exceed the limits n=%d, r=%d, p=%d",
+				n, r, p, maxScryptN, maxScryptR, maxScryptP)
+		}
+		return scrypt.Key([]byte(passphrase), salt, n, r, p, dkLen)
+
+	case "pbkdf2":
+		if prf, _ := c.KDFParams["prf"].(string); prf != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported pbkdf2 prf: %v", c.KDFParams["prf"])
+		}
+		iterations := kdfParamInt(c.KDFParams, "c")
+		if iterations <= 0 || iterations > maxPBKDF2Iterations {
+			return nil, fmt.Errorf("invalid pbkdf2 iteration count %d: must be between 1 and %d", iterations, maxPBKDF2Iterations)
+		}
+		return pbkdf2.Key([]byte(passphrase), salt, iterations, dkLen, sha256.New), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported kdf: %s", c.KDF)
+	}
+}
+
+// kdfParamInt reads a numeric KDF parameter (JSON numbers decode as float64)
+func kdfParamInt(params map[string]interface{}, name string) int {
+	switch v := params[name].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+// aesCTRXOR encrypts or decrypts data with AES-CTR (the operation is symmetric)
+func aesCTRXOR(key, data, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid iv: expected %d bytes, got %d", aes.BlockSize, len(iv))
+	}
+
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
+
+// newUUID returns a random (version 4) UUID string
+func newUUID() (string, error) {
+	u := make([]byte, 16)
+	if _, err := rand.Read(u); err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
+}
@@ -0,0 +1,164 @@
+package ethereum
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Test vectors from the Web3 Secret Storage Definition (password "testpassword")
+const (
+	keystoreVectorPassword   = "testpassword"
+	keystoreVectorPrivateKey = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+
+	keystoreVectorPBKDF2 = `{
+		"crypto" : {
+			"cipher" : "aes-128-ctr",
+			"cipherparams" : { "iv" : "6087dab2f9fdbbfaddc31a909735c1e6" },
+			"ciphertext" : "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+			"kdf" : "pbkdf2",
+			"kdfparams" : {
+				"c" : 262144,
+				"dklen" : 32,
+				"prf" : "hmac-sha256",
+				"salt" : "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"
+			},
+			"mac" : "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+		},
+		"id" : "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version" : 3
+	}`
+
+	keystoreVectorScrypt = `{
+		"crypto" : {
+			"cipher" : "aes-128-ctr",
+			"cipherparams" : { "iv" : "83dbcc02d8ccb40e466191a123791e0e" },
+			"ciphertext" : "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+			"kdf" : "scrypt",
+			"kdfparams" : {
+				"dklen" : 32,
+				"n" : 262144,
+				"p" : 8,
+				"r" : 1,
+				"salt" : "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"
+			},
+			"mac" : "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+		},
+		"id" : "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version" : 3
+	}`
+)
+
+// TestKeystoreDecryptVectors tests decrypting the reference keystore files
+func TestKeystoreDecryptVectors(t *testing.T) {
+	vectors := map[string]string{
+		"pbkdf2": keystoreVectorPBKDF2,
+		"scrypt": keystoreVectorScrypt,
+	}
+
+	for name, keyJSON := range vectors {
+		keyPair, err := DecryptKey([]byte(keyJSON), keystoreVectorPassword)
+		if err != nil {
+			t.Fatalf("%s: failed to decrypt keystore: %v", name, err)
+		}
+
+		exported := strings.TrimPrefix(ExportPrivateKey(keyPair), "0x")
+		if exported != keystoreVectorPrivateKey {
+			t.Fatalf("%s: decrypted key %s doesn't match expected %s", name, exported, keystoreVectorPrivateKey)
+		}
+
+		// Wrong passphrase must fail the MAC check
+		_, err = DecryptKey([]byte(keyJSON), "wrongpassword")
+		if !errors.Is(err, ErrKeystoreDecrypt) {
+			t.Fatalf("%s: expected ErrKeystoreDecrypt for wrong passphrase, got %v", name, err)
+		}
+	}
+}
+
+// TestKeystoreMalformed tests that malformed keystore files are rejected with an
+// error instead of crashing or exhausting memory
+func TestKeystoreMalformed(t *testing.T) {
+	cases := []struct {
+		name     string
+		vector   string
+		old, new string
+	}{
+		{"short iv", keystoreVectorScrypt, `"iv" : "83dbcc02d8ccb40e466191a123791e0e"`, `"iv" : "83dbcc02"`},
+		{"scrypt n too large", keystoreVectorScrypt, `"n" : 262144`, `"n" : 1073741824`},
+		{"scrypt r too large", keystoreVectorScrypt, `"r" : 1`, `"r" : 16`},
+		{"scrypt p too large", keystoreVectorScrypt, `"p" : 8`, `"p" : 32`},
+		{"scrypt dklen too large", keystoreVectorScrypt, `"dklen" : 32`, `"dklen" : 4294967296`},
+		{"pbkdf2 c too large", keystoreVectorPBKDF2, `"c" : 262144`, `"c" : 2147483647`},
+		{"pbkdf2 dklen too large", keystoreVectorPBKDF2, `"dklen" : 32`, `"dklen" : 65`},
+	}
+	for _, tc := range cases {
+		keyJSON := strings.Replace(tc.vector, tc.old, tc.new, 1)
+		if keyJSON == tc.vector {
+			t.Fatalf("%s: replacement not found", tc.name)
+		}
+		_, err := DecryptKey([]byte(keyJSON), keystoreVectorPassword)
+		if err == nil || errors.Is(err, ErrKeystoreDecrypt) {
+			t.Fatalf("%s: expected a malformed keystore error, got %v", tc.name, err)
+		}
+	}
+}
+
+// TestKeystoreRoundTrip tests encrypting a key and reading it back from a keystore directory
+func TestKeystoreRoundTrip(t *testing.T) {
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import private key: %v", err)
+	}
+
+	dir := t.TempDir()
+	filename, err := SaveKeystore(keyPair, dir, "correct horse", LightScryptN, LightScryptP)
+	if err != nil {
+		t.Fatalf("Failed to save keystore: %v", err)
+	}
+	t.Logf("Keystore file: %s", filename)
+
+	// The file name should follow geth's convention and the file must be private
+	base := filepath.Base(filename)
+	if !strings.HasPrefix(base, "UTC--") || !strings.HasSuffix(base, strings.ToLower(testAddress[2:])) {
+		t.Fatalf("Unexpected keystore file name: %s", base)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("Failed to stat keystore file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("Keystore file permissions %v, expected 0600", info.Mode().Perm())
+	}
+
+	// The private key must not appear in plaintext
+	content, _ := os.ReadFile(filename)
+	if strings.Contains(string(content), testPrivateKey) {
+		t.Fatal("Keystore file contains the plaintext private key")
+	}
+
+	// Locate by account, with and without checksum casing
+	found, err := FindKeystoreFile(dir, testAddress)
+	if err != nil {
+		t.Fatalf("Failed to find keystore file: %v", err)
+	}
+	if found != filename {
+		t.Fatalf("Found %s, expected %s", found, filename)
+	}
+	if _, err := FindKeystoreFile(dir, "0x0000000000000000000000000000000000000001"); err == nil {
+		t.Fatal("FindKeystoreFile should fail for an unknown account")
+	}
+
+	loaded, err := LoadKeystore(found, "correct horse")
+	if err != nil {
+		t.Fatalf("Failed to load keystore: %v", err)
+	}
+	if loaded.Address != keyPair.Address {
+		t.Fatalf("Loaded address %s doesn't match %s", loaded.Address.Hex(), keyPair.Address.Hex())
+	}
+
+	if _, err := LoadKeystore(found, "wrong"); !errors.Is(err, ErrKeystoreDecrypt) {
+		t.Fatalf("Expected ErrKeystoreDecrypt for wrong passphrase, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package ethereum
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Legacy (type-0) transaction with EIP-155 replay protection
+type TXLegacy struct {
+	Nonce    uint64    // S
+	GasPrice *big.Int  // S
+	GasLimit uint64    // S
+	To       *[20]byte // S  (pointer lets RLP encode nil for contract creation)
+	Value    *big.Int  // S
+	Data     []byte    // S
+	// not part of the RLP list; signed as [.., chainId, 0, 0] per EIP-155
+	ChainID *big.Int `rlp:"-"`
+	// after signing:
+	V *big.Int // chainId*2 + 35 + recovery id
+	R *big.Int
+	S *big.Int
+}
+
+// PayloadRLP returns the EIP-155 signing payload RLP-encoded:
+// [nonce, gasPrice, gasLimit, to, value, data, chainId, 0, 0]
+func (t *TXLegacy) PayloadRLP() ([]byte, error) {
+	type unsigned struct {
+		Nonce    uint64
+		GasPrice *big.Int
+		GasLimit uint64
+		To       *[20]byte
+		Value    *big.Int
+		Data     []byte
+		ChainID  *big.Int
+		Zero1    uint
+		Zero2    uint
+	}
+	return rlp.EncodeToBytes(unsigned{
+		t.Nonce, t.GasPrice, t.GasLimit, t.To, t.Value, t.Data,
+		t.ChainID, 0, 0,
+	})
+}
+
+// SigningHash returns the keccak256 hash that is signed
+func (t *TXLegacy) SigningHash() ([]byte, error) {
+	payload, err := t.PayloadRLP()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return Keccak256(payload), nil
+}
+
+// Sign fills in V,R,S and returns the signed raw tx bytes (plain RLP, no type prefix)
+func (t *TXLegacy) Sign(priv *ecdsa.PrivateKey) ([]byte, error) {
+	if t.ChainID == nil || t.ChainID.Sign() <= 0 {
+		return nil, fmt.Errorf("chain ID is required for EIP-155 signing")
+	}
+
+	hash, err := t.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+
+	// Sign the hash using Ethereum's crypto package
+	signature, err := crypto.Sign(hash, priv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	// v = chainId*2 + 35 + recovery id
+	v := new(big.Int).Mul(t.ChainID, big.NewInt(2))
+	v.Add(v, big.NewInt(35+int64(signature[64])))
+
+	t.V = v
+	t.R = new(big.Int).SetBytes(signature[0:32])
+	t.S = new(big.Int).SetBytes(signature[32:64])
+
+	raw, err := rlp.EncodeToBytes(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signed transaction: %w", err)
+	}
+
+	return raw, nil
+}
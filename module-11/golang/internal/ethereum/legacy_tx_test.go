@@ -0,0 +1,208 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// vectorTo is the recipient used by the signed transaction vectors
+const vectorTo = "0xde9ca654aE5a3673d894eba15b63603Fa00F8504"
+
+// toPtr decodes an address for use as a transaction recipient
+func toPtr(t *testing.T, address string) *[20]byte {
+	t.Helper()
+	var to [20]byte
+	copy(to[:], HexToAddress(address).Bytes())
+	return &to
+}
+
+// mustHex decodes a hex string or fails the test
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := HexDecode(s)
+	if err != nil {
+		t.Fatalf("Invalid hex %s: %v", s, err)
+	}
+	return b
+}
+
+// TestLegacyTxVectors checks legacy EIP-155 signing against known signed transactions
+func TestLegacyTxVectors(t *testing.T) {
+	oneEther := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+	vectors := []struct {
+		name string
+		key  string
+		tx   TXLegacy
+		raw  string
+		hash string
+	}{
+		{
+			// The example from the EIP-155 specification
+			name: "eip155 spec",
+			key:  "4646464646464646464646464646464646464646464646464646464646464646",
+			tx: TXLegacy{
+				Nonce: 9, GasPrice: big.NewInt(20_000_000_000), GasLimit: 21000,
+				To: toPtr(t, "0x3535353535353535353535353535353535353535"), Value: oneEther, ChainID: big.NewInt(1),
+			},
+			raw:  "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83",
+			hash: "0x33469b22e9f636356c4160a87eb19df52b7412e8eac32a4a55ffe88ea8350788",
+		},
+		{
+			name: "sepolia transfer",
+			key:  testPrivateKey,
+			tx: TXLegacy{
+				Nonce: 0, GasPrice: big.NewInt(1_000_000_000), GasLimit: 21000,
+				To: toPtr(t, vectorTo), Value: big.NewInt(1), ChainID: big.NewInt(11155111),
+			},
+			raw:  "f86780843b9aca0082520894de9ca654ae5a3673d894eba15b63603fa00f850401808401546d71a00568ec98d0943013d5ca2f28b68c0f74a6526141eb7b87777d1dcf74130156d5a064db1eabfead8c942158b127fbfaa6869edadc69b9c296f11876b3e05cb9008a",
+			hash: "0xa8fa22334cf7a4498a48024ceebc973ffdaf9021f4033085bd0ec2df464e409e",
+		},
+		{
+			name: "contract creation",
+			key:  testPrivateKey,
+			tx: TXLegacy{
+				Nonce: 7, GasPrice: big.NewInt(875_000_000), GasLimit: 120000,
+				To: nil, Value: big.NewInt(0), Data: mustHex(t, "0x6080604052348015600f57600080fd5b50"), ChainID: big.NewInt(31337),
+			},
+			raw:  "f8630784342770c08301d4c08080916080604052348015600f57600080fd5b5082f4f6a0208a754c387aff5d704963cb7860866f936dd571065209442f9de1dc48e075f9a00e642660b9d1084aef326887e16fea8378b0031731c6d2bf9cadebc1edc31f70",
+			hash: "0x0070e280e7defe48b458b2afd53558d000a1014f55d8b2ec525f7cc99adc2c92",
+		},
+		{
+			name: "large chain id",
+			key:  testPrivateKey,
+			tx: TXLegacy{
+				Nonce: 300, GasPrice: big.NewInt(25_000_000_000), GasLimit: 50000,
+				To: toPtr(t, vectorTo), Value: oneEther, Data: mustHex(t, "0xa9059cbb"), ChainID: big.NewInt(4294967295),
+			},
+			raw:  "f87782012c8505d21dba0082c35094de9ca654ae5a3673d894eba15b63603fa00f8504880de0b6b3a764000084a9059cbb850200000021a0aa6c65b08ef44929254caf83b8945e4c690e9601cbdb13b41e471283e4e0e6b0a0081e3aa0412ecb7009aa3c775946526b9757a43daa0b3f7c4c7a08b01fecd92d",
+			hash: "0xa49f01e43e93131b0b5222d0481df3b93df02d1d9fd8ebfa265e88e0dbe8af47",
+		},
+	}
+
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			key, err := crypto.HexToECDSA(v.key)
+			if err != nil {
+				t.Fatalf("Invalid key: %v", err)
+			}
+
+			tx := v.tx
+			raw, err := tx.Sign(key)
+			if err != nil {
+				t.Fatalf("Failed to sign: %v", err)
+			}
+
+			if got := hex.EncodeToString(raw); got != v.raw {
+				t.Fatalf("Raw transaction mismatch\n got: %s\nwant: %s", got, v.raw)
+			}
+			if got := fmt.Sprintf("0x%x", Keccak256(raw)); got != v.hash {
+				t.Fatalf("Hash %s, expected %s", got, v.hash)
+			}
+
+			// v = chainId*2 + 35 + {0,1}
+			base := new(big.Int).Add(new(big.Int).Mul(tx.ChainID, big.NewInt(2)), big.NewInt(35))
+			if recID := new(big.Int).Sub(tx.V, base); recID.Sign() < 0 || recID.Cmp(big.NewInt(1)) > 0 {
+				t.Fatalf("V %s is not an EIP-155 value for chain %s", tx.V, tx.ChainID)
+			}
+		})
+	}
+
+	// The EIP-155 signing payload from the specification
+	spec := vectors[0].tx
+	payload, err := spec.PayloadRLP()
+	if err != nil {
+		t.Fatalf("Failed to encode payload: %v", err)
+	}
+	if got := hex.EncodeToString(payload); got != "ec098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a764000080018080" {
+		t.Fatalf("Signing payload mismatch: %s", got)
+	}
+	hash, _ := spec.SigningHash()
+	if got := hex.EncodeToString(hash); got != "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53" {
+		t.Fatalf("Signing hash mismatch: %s", got)
+	}
+}
+
+// TestEIP1559TxVector checks type-2 signing against a known signed transaction
+func TestEIP1559TxVector(t *testing.T) {
+	key, err := crypto.HexToECDSA(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Invalid key: %v", err)
+	}
+
+	tx := &TX1559{
+		ChainID: big.NewInt(11155111), Nonce: 5,
+		MaxPriorityFeePerGas: big.NewInt(1_500_000_000), MaxFeePerGas: big.NewInt(31_500_000_000),
+		GasLimit: 21000, To: toPtr(t, vectorTo), Value: big.NewInt(1000),
+	}
+	raw, err := tx.Sign(key)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+
+	want := "02f87083aa36a7058459682f008507558bdb0082520894de9ca654ae5a3673d894eba15b63603fa00f85048203e880c001a09f0dbb5f88a0d8905b3141adfabe782f5742dff6dc474e47af34725988d510d1a023d94d3c1d6237fa46010ce1a22e4f1f70b1c622fe91c695db241600c516672c"
+	if got := hex.EncodeToString(raw); got != want {
+		t.Fatalf("Raw transaction mismatch\n got: %s\nwant: %s", got, want)
+	}
+	if got := fmt.Sprintf("0x%x", Keccak256(raw)); got != "0x998a42f5a26adb44b9f207c36d5671ae81f8e354590bf7be202db91ba3ed28f8" {
+		t.Fatalf("Hash mismatch: %s", got)
+	}
+}
+
+// TestSendLegacyTransaction tests that SendTransaction broadcasts a type-0 tx priced by eth_gasPrice
+func TestSendLegacyTransaction(t *testing.T) {
+	var rawTx []byte
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_chainId":
+			return "0xaa36a7", nil
+		case "eth_getTransactionCount":
+			return "0x0", nil
+		case "eth_estimateGas":
+			return "0x4650", nil // 18000, 21600 with the buffer
+		case "eth_getBlockByNumber":
+			return map[string]string{"baseFeePerGas": "0x3b9aca00"}, nil
+		case "eth_gasPrice":
+			return "0x3b9aca00", nil
+		case "eth_sendRawTransaction":
+			var rawHex string
+			json.Unmarshal(params[0], &rawHex)
+			rawTx, _ = HexDecode(rawHex)
+			return fmt.Sprintf("0x%x", Keccak256(rawTx)), nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+
+	txHash, err := NewClient(rpc.URL).SendTransaction(context.Background(), keyPair, vectorTo, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+
+	// A legacy transaction is a plain RLP list, not a typed envelope
+	if len(rawTx) == 0 || rawTx[0] < 0xc0 {
+		t.Fatalf("Expected an RLP list, got type byte 0x%02x", rawTx[0])
+	}
+
+	want := TXLegacy{
+		Nonce: 0, GasPrice: big.NewInt(1_000_000_000), GasLimit: 21600,
+		To: toPtr(t, vectorTo), Value: big.NewInt(1), Data: []byte{}, ChainID: big.NewInt(11155111),
+	}
+	expected, _ := want.Sign(keyPair.PrivateKey)
+	if hex.EncodeToString(rawTx) != hex.EncodeToString(expected) {
+		t.Fatalf("Broadcast transaction mismatch\n got: %x\nwant: %x", rawTx, expected)
+	}
+	if txHash != fmt.Sprintf("0x%x", Keccak256(expected)) {
+		t.Fatalf("Unexpected hash %s", txHash)
+	}
+}
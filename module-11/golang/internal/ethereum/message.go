@@ -0,0 +1,141 @@
+package ethereum
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/metana-bootcamp/ethwallet/internal/eip712"
+)
+
+// ErrSignerMismatch is returned when a valid signature was made by another address
+var ErrSignerMismatch = errors.New("signature does not match the address")
+
+// messagePrefix starts every EIP-191 version 0x45 (personal_sign) message
+const messagePrefix = "\x19Ethereum Signed Message:\n"
+
+// HashMessage returns the EIP-191 hash of a personal_sign message:
+// keccak256("\x19Ethereum Signed Message:\n" + len(message) + message)
+func HashMessage(message []byte) []byte {
+	prefixed := append([]byte(messagePrefix+strconv.Itoa(len(message))), message...)
+	return Keccak256(prefixed)
+}
+
+// SignMessage signs a message the way personal_sign does and returns the 65-byte
+// signature r || s || v with v = 27 or 28
+func SignMessage(priv *ecdsa.PrivateKey, message []byte) ([]byte, error) {
+	return SignHash(priv, HashMessage(message))
+}
+
+// RecoverMessageSigner returns the address that signed a personal_sign message
+func RecoverMessageSigner(message, signature []byte) (common.Address, error) {
+	return RecoverSigner(HashMessage(message), signature)
+}
+
+// VerifyMessage checks that address signed a personal_sign message. It fails
+// with ErrSignerMismatch when the signature is valid but made by another key.
+func VerifyMessage(address common.Address, message, signature []byte) error {
+	return verifySigner(address, HashMessage(message), signature)
+}
+
+// SignTypedData signs EIP-712 typed data the way eth_signTypedData_v4 does and
+// returns the 65-byte signature r || s || v with v = 27 or 28
+func SignTypedData(priv *ecdsa.PrivateKey, td *eip712.TypedData) ([]byte, error) {
+	hash, err := td.Hash()
+	if err != nil {
+		return nil, fmt.Errorf("error hashing typed data: %w", err)
+	}
+	return SignHash(priv, hash)
+}
+
+// RecoverTypedDataSigner returns the address that signed EIP-712 typed data
+func RecoverTypedDataSigner(td *eip712.TypedData, signature []byte) (common.Address, error) {
+	hash, err := td.Hash()
+	if err != nil {
+		return common.Address{}, fmt.Errorf("error hashing typed data: %w", err)
+	}
+	return RecoverSigner(hash, signature)
+}
+
+// VerifyTypedData checks that address signed EIP-712 typed data. It fails with
+// ErrSignerMismatch when the signature is valid but made by another key.
+func VerifyTypedData(address common.Address, td *eip712.TypedData, signature []byte) error {
+	hash, err := td.Hash()
+	if err != nil {
+		return fmt.Errorf("error hashing typed data: %w", err)
+	}
+	return verifySigner(address, hash, signature)
+}
+
+// SignHash signs a 32-byte hash and returns the 65-byte signature r || s || v
+// with v = 27 or 28, the form wallets and ecrecover use
+func SignHash(priv *ecdsa.PrivateKey, hash []byte) ([]byte, error) {
+	signature, err := crypto.Sign(hash, priv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// CompactSignature converts a 65-byte r || s || v signature to the 64-byte
+// EIP-2098 form r || yParityAndS, where the y-parity is the top bit of s
+func CompactSignature(signature []byte) ([]byte, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("signature must be %d bytes, got %d", crypto.SignatureLength, len(signature))
+	}
+	v := signature[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 || signature[32]&0x80 != 0 {
+		return nil, fmt.Errorf("invalid signature: v must be 27 or 28 and s in the lower half of the curve order")
+	}
+	compact := append([]byte(nil), signature[:64]...)
+	compact[32] |= v << 7
+	return compact, nil
+}
+
+// RecoverSigner returns the address that produced a 65-byte r || s || v signature
+// of a 32-byte hash. v may be 27/28 or 0/1; signatures with s in the upper half
+// of the curve order are rejected (EIP-2), as OpenZeppelin's ECDSA library does.
+func RecoverSigner(hash, signature []byte) (common.Address, error) {
+	if len(hash) != 32 {
+		return common.Address{}, fmt.Errorf("hash must be 32 bytes, got %d", len(hash))
+	}
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes, got %d", crypto.SignatureLength, len(signature))
+	}
+
+	sig := append([]byte(nil), signature...)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(sig[64], r, s, true) {
+		return common.Address{}, fmt.Errorf("invalid signature: v must be 27 or 28 and r, s in range")
+	}
+
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %v", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// verifySigner checks that address produced signature over hash
+func verifySigner(address common.Address, hash, signature []byte) error {
+	signer, err := RecoverSigner(hash, signature)
+	if err != nil {
+		return err
+	}
+	if signer != address {
+		return fmt.Errorf("%w: signed by %s, not %s", ErrSignerMismatch, signer.Hex(), address.Hex())
+	}
+	return nil
+}
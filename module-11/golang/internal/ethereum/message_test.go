@@ -0,0 +1,157 @@
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/metana-bootcamp/ethwallet/internal/eip712"
+)
+
+// TestSignMessage tests personal_sign signing against a known signature and
+// recovery of the signer
+func TestSignMessage(t *testing.T) {
+	key, err := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatalf("Invalid key: %v", err)
+	}
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	message := []byte("Some data")
+
+	if got := fmt.Sprintf("0x%x", HashMessage(message)); got != "0x1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655" {
+		t.Fatalf("Message hash mismatch: %s", got)
+	}
+
+	signature, err := SignMessage(key, message)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	want := "0xb91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"
+	if got := fmt.Sprintf("0x%x", signature); got != want {
+		t.Fatalf("Signature mismatch\n got: %s\nwant: %s", got, want)
+	}
+
+	if recovered, err := RecoverMessageSigner(message, signature); err != nil || recovered != signer {
+		t.Fatalf("Recovered %s, %v; expected %s", recovered.Hex(), err, signer.Hex())
+	}
+	if err := VerifyMessage(signer, message, signature); err != nil {
+		t.Fatalf("Failed to verify: %v", err)
+	}
+
+	// v = 0/1 is accepted as well as 27/28
+	raw := append([]byte(nil), signature...)
+	raw[64] -= 27
+	if err := VerifyMessage(signer, message, raw); err != nil {
+		t.Fatalf("Failed to verify with v = %d: %v", raw[64], err)
+	}
+
+	// Another message or address does not verify
+	if err := VerifyMessage(signer, []byte("Some data!"), signature); !errors.Is(err, ErrSignerMismatch) {
+		t.Fatalf("Expected ErrSignerMismatch for another message, got %v", err)
+	}
+	if err := VerifyMessage(HexToAddress(testAddress), message, signature); !errors.Is(err, ErrSignerMismatch) {
+		t.Fatalf("Expected ErrSignerMismatch for another address, got %v", err)
+	}
+
+	// Malformed signatures are rejected
+	bad := [][]byte{signature[:64], append(append([]byte(nil), signature[:64]...), 29)}
+	high := append([]byte(nil), signature...)
+	s := new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(signature[32:64]))
+	s.FillBytes(high[32:64])
+	bad = append(bad, high)
+	for i, sig := range bad {
+		if _, err := RecoverMessageSigner(message, sig); err == nil {
+			t.Fatalf("Signature %d: expected an error", i)
+		}
+	}
+}
+
+// TestSignTypedData tests EIP-712 signing against the example in the specification
+func TestSignTypedData(t *testing.T) {
+	td, err := eip712.Parse([]byte(`{
+		"types": {
+			"EIP712Domain": [
+				{"name": "name", "type": "string"},
+				{"name": "version", "type": "string"},
+				{"name": "chainId", "type": "uint256"},
+				{"name": "verifyingContract", "type": "address"}
+			],
+			"Person": [{"name": "name", "type": "string"}, {"name": "wallet", "type": "address"}],
+			"Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "string"}]
+		},
+		"primaryType": "Mail",
+		"domain": {"name": "Ether Mail", "version": "1", "chainId": 1, "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+		"message": {
+			"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+			"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+			"contents": "Hello, Bob!"
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse typed data: %v", err)
+	}
+
+	// The specification signs with keccak256("cow")
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	if err != nil {
+		t.Fatalf("Invalid key: %v", err)
+	}
+	cow := crypto.PubkeyToAddress(key.PublicKey)
+
+	signature, err := SignTypedData(key, td)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	want := "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c"
+	if got := fmt.Sprintf("0x%x", signature); got != want {
+		t.Fatalf("Signature mismatch\n got: %s\nwant: %s", got, want)
+	}
+
+	if recovered, err := RecoverTypedDataSigner(td, signature); err != nil || recovered != cow {
+		t.Fatalf("Recovered %s, %v; expected %s", recovered.Hex(), err, cow.Hex())
+	}
+	if err := VerifyTypedData(cow, td, signature); err != nil {
+		t.Fatalf("Failed to verify: %v", err)
+	}
+
+	// Changing the message invalidates the signature
+	td.Message["contents"] = "Hello, Eve!"
+	if err := VerifyTypedData(cow, td, signature); !errors.Is(err, ErrSignerMismatch) {
+		t.Fatalf("Expected ErrSignerMismatch for another message, got %v", err)
+	}
+}
+
+// TestCompactSignature tests EIP-2098 compact signatures against the examples in the EIP
+func TestCompactSignature(t *testing.T) {
+	key, err := crypto.HexToECDSA("1234567890123456789012345678901234567890123456789012345678901234")
+	if err != nil {
+		t.Fatalf("Invalid key: %v", err)
+	}
+
+	vectors := []struct {
+		message string
+		want    string // r || yParityAndS
+	}{
+		{"Hello World", "68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b90" +
+			"7e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064"},
+		{"It's a small(er) world", "9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76" +
+			"939c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793"},
+	}
+	for _, v := range vectors {
+		signature, err := SignMessage(key, []byte(v.message))
+		if err != nil {
+			t.Fatalf("Failed to sign %q: %v", v.message, err)
+		}
+		compact, err := CompactSignature(signature)
+		if err != nil {
+			t.Fatalf("Failed to compact %q: %v", v.message, err)
+		}
+		if got := fmt.Sprintf("%x", compact); got != v.want {
+			t.Fatalf("Compact signature of %q\n got: %s\nwant: %s", v.message, got, v.want)
+		}
+	}
+}
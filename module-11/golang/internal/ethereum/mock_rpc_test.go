@@ -0,0 +1,118 @@
+package ethereum
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// mockRPCHandler answers a single JSON-RPC call. Returning a non-nil error
+// value produces a JSON-RPC error response.
+type mockRPCHandler func(method string, params []json.RawMessage) (interface{}, *mockRPCError)
+
+// mockRPCError is a JSON-RPC error returned by a mock handler
+type mockRPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// mockRPCRequest is a decoded JSON-RPC request
+type mockRPCRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// mockRPC is an in-process JSON-RPC server for unit tests
+type mockRPC struct {
+	*httptest.Server
+	mu       sync.Mutex
+	calls    map[string]int
+	requests int
+}
+
+// newMockRPC starts a JSON-RPC test server backed by handler
+func newMockRPC(t *testing.T, handler mockRPCHandler) *mockRPC {
+	t.Helper()
+
+	m := &mockRPC{calls: make(map[string]int)}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		m.mu.Lock()
+		m.requests++
+		m.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		// Batches are answered in reverse order so callers must match by ID
+		if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+			var reqs []mockRPCRequest
+			if err := json.Unmarshal(body, &reqs); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			resps := make([]map[string]interface{}, 0, len(reqs))
+			for i := len(reqs) - 1; i >= 0; i-- {
+				resps = append(resps, m.answer(handler, reqs[i]))
+			}
+			json.NewEncoder(w).Encode(resps)
+			return
+		}
+
+		var req mockRPCRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(m.answer(handler, req))
+	}))
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+// answer runs the handler for one request and builds its response
+func (m *mockRPC) answer(handler mockRPCHandler, req mockRPCRequest) map[string]interface{} {
+	m.mu.Lock()
+	m.calls[req.Method]++
+	m.mu.Unlock()
+
+	result, rpcErr := handler(req.Method, req.Params)
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if rpcErr != nil {
+		resp["error"] = rpcErr
+	} else {
+		resp["result"] = result
+	}
+	return resp
+}
+
+// requestCount returns how many HTTP requests the server received
+func (m *mockRPC) requestCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requests
+}
+
+// callCount returns how many times a method was called
+func (m *mockRPC) callCount(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[method]
+}
+
+// hexUint formats a number as a JSON-RPC hex quantity
+func hexUint(n uint64) string {
+	return fmt.Sprintf("0x%x", n)
+}
@@ -0,0 +1,358 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/metana-bootcamp/ethwallet/internal/address"
+)
+
+// DefaultNetwork is the network profile used when none is selected
+const DefaultNetwork = "sepolia"
+
+// DefaultNetworksFile is the user network file looked up in the working directory
+const DefaultNetworksFile = "networks.json"
+
+// ErrChainIDMismatch is returned when the node serves a different chain than the selected network
+var ErrChainIDMismatch = errors.New("chain ID mismatch")
+
+// Network is a named network profile
+type Network struct {
+	Name        string
+	ChainID     uint64
+	RPCURLs     []string // primary first, then fallbacks
+	Explorer    string   // block explorer base URL, empty when there is none
+	Currency    string   // native currency symbol
+	EIP1559     bool     // whether the chain supports EIP-1559 (type 2) transactions
+	EIP1191     bool     // whether addresses use EIP-1191 chain-specific checksums
+	ENSRegistry string   // ENS registry address, empty when the network has no ENS
+}
+
+// networkConfig is a network entry as written in the networks file
+type networkConfig struct {
+	ChainID     uint64   `json:"chainId"`
+	RPCURLs     []string `json:"rpcUrls"`
+	Explorer    string   `json:"explorer"`
+	Currency    string   `json:"currency"`
+	EIP1559     *bool    `json:"eip1559"`
+	EIP1191     bool     `json:"eip1191"`
+	ENSRegistry string   `json:"ensRegistry"`
+}
+
+// BuiltinNetworks returns the networks that are available without a networks file
+func BuiltinNetworks() map[string]*Network {
+	return map[string]*Network{
+		"mainnet": {
+			Name:        "mainnet",
+			ChainID:     1,
+			RPCURLs:     []string{"https://eth-mainnet.g.alchemy.com/v2/${ALCHEMY_API_KEY}"},
+			Explorer:    "https://etherscan.io",
+			Currency:    "ETH",
+			EIP1559:     true,
+			ENSRegistry: DefaultENSRegistry,
+		},
+		"sepolia": {
+			Name:        "sepolia",
+			ChainID:     11155111,
+			RPCURLs:     []string{"https://eth-sepolia.g.alchemy.com/v2/${ALCHEMY_API_KEY}"},
+			Explorer:    "https://sepolia.etherscan.io",
+			Currency:    "ETH",
+			EIP1559:     true,
+			ENSRegistry: DefaultENSRegistry,
+		},
+		"holesky": {
+			Name:        "holesky",
+			ChainID:     17000,
+			RPCURLs:     []string{"https://eth-holesky.g.alchemy.com/v2/${ALCHEMY_API_KEY}"},
+			Explorer:    "https://holesky.etherscan.io",
+			Currency:    "ETH",
+			EIP1559:     true,
+			ENSRegistry: DefaultENSRegistry,
+		},
+		"anvil": {
+			Name:     "anvil",
+			ChainID:  31337,
+			RPCURLs:  []string{"http://127.0.0.1:8545"},
+			Currency: "ETH",
+			EIP1559:  true,
+		},
+		"hardhat": {
+			Name:     "hardhat",
+			ChainID:  31337,
+			RPCURLs:  []string{"http://127.0.0.1:8545"},
+			Currency: "ETH",
+			EIP1559:  true,
+		},
+	}
+}
+
+// LoadNetworks returns the built-in networks merged with the entries of a networks
+// file. A file entry with the same name as a built-in network replaces it. An empty
+// path or a missing file yields only the built-in networks.
+//
+// The file is a JSON object keyed by network name:
+//
+//	{"base-sepolia": {"chainId": 84532, "rpcUrls": ["https://sepolia.base.org"], "explorer": "https://sepolia.basescan.org"}}
+func LoadNetworks(path string) (map[string]*Network, error) {
+	networks := BuiltinNetworks()
+	if path == "" {
+		return networks, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return networks, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read networks file: %v", err)
+	}
+
+	var configs map[string]networkConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse networks file %s: %v", path, err)
+	}
+
+	for name, cfg := range configs {
+		if cfg.ChainID == 0 {
+			return nil, fmt.Errorf("network %q in %s has no chainId", name, path)
+		}
+		if len(cfg.RPCURLs) == 0 {
+			return nil, fmt.Errorf("network %q in %s has no rpcUrls", name, path)
+		}
+		if cfg.ENSRegistry != "" && !address.IsHex(cfg.ENSRegistry) {
+			return nil, fmt.Errorf("network %q in %s has an invalid ensRegistry", name, path)
+		}
+
+		network := &Network{
+			Name:        name,
+			ChainID:     cfg.ChainID,
+			RPCURLs:     cfg.RPCURLs,
+			Explorer:    strings.TrimSuffix(cfg.Explorer, "/"),
+			Currency:    cfg.Currency,
+			EIP1559:     true,
+			EIP1191:     cfg.EIP1191,
+			ENSRegistry: cfg.ENSRegistry,
+		}
+		if network.Currency == "" {
+			network.Currency = "ETH"
+		}
+		if cfg.EIP1559 != nil {
+			network.EIP1559 = *cfg.EIP1559
+		}
+		networks[name] = network
+	}
+
+	return networks, nil
+}
+
+// NetworkNames returns the network names in alphabetical order
+func NetworkNames(networks map[string]*Network) []string {
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetNetworksFile gets the networks file path from ETHWALLET_NETWORKS or falls back to
+// networks.json in the working directory
+func GetNetworksFile() string {
+	LoadEnvVariables()
+
+	if path := os.Getenv("ETHWALLET_NETWORKS"); path != "" {
+		return path
+	}
+	return DefaultNetworksFile
+}
+
+// SelectNetwork loads the networks file and returns the named network. Without a
+// name, ETH_NETWORK is used, then the network matching CHAIN_ID, then DefaultNetwork.
+// Environment overrides are applied to the returned profile (see Network.applyEnv).
+func SelectNetwork(name, networksFile string) (*Network, error) {
+	LoadEnvVariables()
+
+	networks, err := LoadNetworks(networksFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = os.Getenv("ETH_NETWORK")
+	}
+	if name == "" {
+		if chainID := os.Getenv("CHAIN_ID"); chainID != "" {
+			name, err = networkForChainID(networks, chainID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if name == "" {
+		name = DefaultNetwork
+	}
+
+	network, ok := networks[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown network %q (available: %s)", name, strings.Join(NetworkNames(networks), ", "))
+	}
+
+	network.applyEnv()
+	return network, nil
+}
+
+// networkForChainID finds the network for a CHAIN_ID value, preferring the default network
+func networkForChainID(networks map[string]*Network, value string) (string, error) {
+	chainID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid CHAIN_ID %q", value)
+	}
+
+	if networks[DefaultNetwork].ChainID == chainID {
+		return DefaultNetwork, nil
+	}
+	for _, name := range NetworkNames(networks) {
+		if networks[name].ChainID == chainID {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no network profile has CHAIN_ID %d; select one with --network", chainID)
+}
+
+// applyEnv applies environment overrides to the profile, named after it: <NAME>_RPC_URL
+// replaces the primary RPC URL (e.g. SEPOLIA_RPC_URL), <NAME>_RPC_FALLBACK_URLS are
+// appended as fallbacks and <NAME>_EXPLORER_URL replaces the block explorer. ${VAR}
+// references in the URLs are expanded. The older RPC_FALLBACK_URLS and
+// BLOCK_EXPLORER_URL only apply to DefaultNetwork, which the original .env describes;
+// for any other network they are ignored with a warning, so a mainnet command never
+// fails over to Sepolia nodes.
+func (n *Network) applyEnv() {
+	prefix := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(n.Name))
+	urls := append([]string(nil), n.RPCURLs...)
+
+	if url := os.Getenv(prefix + "_RPC_URL"); url != "" {
+		urls[0] = url
+	}
+
+	fallbacks := splitURLList(os.Getenv(prefix + "_RPC_FALLBACK_URLS"))
+	explorer := os.Getenv(prefix + "_EXPLORER_URL")
+	if n.Name == DefaultNetwork {
+		if len(fallbacks) == 0 {
+			fallbacks = GetFallbackRPCURLs()
+		}
+		if explorer == "" {
+			explorer = os.Getenv("BLOCK_EXPLORER_URL")
+		}
+	} else {
+		for _, legacy := range [][2]string{{"RPC_FALLBACK_URLS", "_RPC_FALLBACK_URLS"}, {"BLOCK_EXPLORER_URL", "_EXPLORER_URL"}} {
+			if os.Getenv(legacy[0]) != "" {
+				fmt.Fprintf(os.Stderr, "Warning: %s only applies to the %s network and is ignored for %s; set %s instead.\n",
+					legacy[0], DefaultNetwork, n.Name, prefix+legacy[1])
+			}
+		}
+	}
+	urls = append(urls, fallbacks...)
+	if explorer != "" {
+		n.Explorer = strings.TrimSuffix(explorer, "/")
+	}
+
+	for i, url := range urls {
+		urls[i] = expandRPCURL(url)
+	}
+	n.RPCURLs = urls
+}
+
+// splitURLList splits a comma-separated list of URLs, dropping empty entries
+func splitURLList(list string) []string {
+	var urls []string
+	for _, url := range strings.Split(list, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// expandRPCURL expands ${VAR} references from the environment. ALCHEMY_API_KEY
+// resolves through GetAPIKey so the development fallback key still applies.
+func expandRPCURL(url string) string {
+	if !strings.Contains(url, "${") {
+		return url
+	}
+	return os.Expand(url, func(key string) string {
+		if key == "ALCHEMY_API_KEY" {
+			return GetAPIKey()
+		}
+		return os.Getenv(key)
+	})
+}
+
+// ChecksumChainID returns the chain ID used for address checksums: the network's
+// chain ID with EIP-1191, or 0 for plain EIP-55
+func (n *Network) ChecksumChainID() uint64 {
+	if n.EIP1191 {
+		return n.ChainID
+	}
+	return 0
+}
+
+// RPCURL returns the primary RPC URL
+func (n *Network) RPCURL() string {
+	return n.RPCURLs[0]
+}
+
+// FallbackURLs returns the RPC URLs used when the primary fails
+func (n *Network) FallbackURLs() []string {
+	return n.RPCURLs[1:]
+}
+
+// ChainIDMismatchError reports that the node serves a different chain than expected
+type ChainIDMismatchError struct {
+	Expected uint64
+	Actual   uint64
+}
+
+// Error implements the error interface
+func (e *ChainIDMismatchError) Error() string {
+	return fmt.Sprintf("%v: node reports chain ID %d, expected %d", ErrChainIDMismatch, e.Actual, e.Expected)
+}
+
+// Is makes errors.Is(err, ErrChainIDMismatch) match
+func (e *ChainIDMismatchError) Is(target error) bool {
+	return target == ErrChainIDMismatch
+}
+
+// WithChainID sets the chain ID the node must serve. Preflight, and therefore every
+// send, fails with ErrChainIDMismatch before signing when the node reports another chain.
+func WithChainID(chainID uint64) ClientOption {
+	return func(c *Client) {
+		c.chainID = chainID
+	}
+}
+
+// VerifyChainID checks that the node serves the chain set with WithChainID.
+// It is a no-op when no chain ID was configured.
+func (c *Client) VerifyChainID(ctx context.Context) error {
+	if c.chainID == 0 {
+		return nil
+	}
+	chainID, err := c.GetChainID(ctx)
+	if err != nil {
+		return err
+	}
+	return c.checkChainID(chainID.Uint64())
+}
+
+// checkChainID compares a chain ID reported by the node with the expected one
+func (c *Client) checkChainID(actual uint64) error {
+	if c.chainID != 0 && actual != c.chainID {
+		return &ChainIDMismatchError{Expected: c.chainID, Actual: actual}
+	}
+	return nil
+}
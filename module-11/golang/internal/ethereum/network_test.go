@@ -0,0 +1,186 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadNetworks tests merging user-defined networks with the built-in profiles
+func TestLoadNetworks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "networks.json")
+	config := `{
+		"base-sepolia": {"chainId": 84532, "rpcUrls": ["https://sepolia.base.org"], "explorer": "https://sepolia.basescan.org/", "ensRegistry": "0x00000000000000000000000000000000000000e5"},
+		"mainnet": {"chainId": 1, "rpcUrls": ["http://localhost:8545"], "currency": "ETH", "eip1559": false},
+		"rsk": {"chainId": 30, "rpcUrls": ["https://public-node.rsk.co"], "currency": "RBTC", "eip1559": false, "eip1191": true}
+	}`
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatalf("Failed to write networks file: %v", err)
+	}
+
+	networks, err := LoadNetworks(path)
+	if err != nil {
+		t.Fatalf("Failed to load networks: %v", err)
+	}
+
+	for _, name := range []string{"sepolia", "holesky", "anvil", "hardhat", "base-sepolia"} {
+		if networks[name] == nil {
+			t.Fatalf("Network %s missing", name)
+		}
+	}
+
+	base := networks["base-sepolia"]
+	if base.ChainID != 84532 || base.Currency != "ETH" || !base.EIP1559 || base.Explorer != "https://sepolia.basescan.org" {
+		t.Fatalf("Unexpected user network: %+v", base)
+	}
+	if networks["mainnet"].EIP1559 || networks["mainnet"].RPCURLs[0] != "http://localhost:8545" {
+		t.Fatalf("User entry should replace the built-in mainnet: %+v", networks["mainnet"])
+	}
+	if base.ENSRegistry != "0x00000000000000000000000000000000000000e5" || networks["sepolia"].ENSRegistry != DefaultENSRegistry || networks["anvil"].ENSRegistry != "" {
+		t.Fatalf("Unexpected ENS registries")
+	}
+	if networks["rsk"].ChecksumChainID() != 30 || base.ChecksumChainID() != 0 {
+		t.Fatalf("Unexpected checksum chain IDs %d and %d", networks["rsk"].ChecksumChainID(), base.ChecksumChainID())
+	}
+
+	// A missing file yields the built-in networks only
+	if networks, err := LoadNetworks(filepath.Join(t.TempDir(), "missing.json")); err != nil || len(networks) != len(BuiltinNetworks()) {
+		t.Fatalf("Expected built-in networks for a missing file, got %d (%v)", len(networks), err)
+	}
+
+	// Entries without a chain ID are rejected
+	os.WriteFile(path, []byte(`{"broken": {"rpcUrls": ["http://localhost:8545"]}}`), 0600)
+	if _, err := LoadNetworks(path); err == nil {
+		t.Fatal("Expected an error for a network without chainId")
+	}
+}
+
+// TestSelectNetwork tests network selection and environment overrides
+func TestSelectNetwork(t *testing.T) {
+	t.Setenv("ETH_NETWORK", "")
+	t.Setenv("CHAIN_ID", "")
+	t.Setenv("RPC_FALLBACK_URLS", "")
+	t.Setenv("HOLESKY_RPC_URL", "https://holesky.example/${TEST_RPC_TOKEN}")
+	t.Setenv("TEST_RPC_TOKEN", "token")
+
+	network, err := SelectNetwork("holesky", "")
+	if err != nil {
+		t.Fatalf("Failed to select holesky: %v", err)
+	}
+	if network.ChainID != 17000 || network.RPCURL() != "https://holesky.example/token" {
+		t.Fatalf("Unexpected holesky profile: %+v", network)
+	}
+
+	// CHAIN_ID picks the matching profile when no network is named
+	t.Setenv("CHAIN_ID", "31337")
+	if network, err = SelectNetwork("", ""); err != nil || network.ChainID != 31337 {
+		t.Fatalf("Expected a 31337 profile from CHAIN_ID, got %+v (%v)", network, err)
+	}
+
+	// ETH_NETWORK takes precedence over CHAIN_ID
+	t.Setenv("ETH_NETWORK", "mainnet")
+	if network, err = SelectNetwork("", ""); err != nil || network.Name != "mainnet" {
+		t.Fatalf("Expected mainnet from ETH_NETWORK, got %+v (%v)", network, err)
+	}
+
+	if _, err := SelectNetwork("nowhere", ""); err == nil {
+		t.Fatal("Expected an error for an unknown network")
+	}
+}
+
+// TestNetworkFallbackURLs tests that fallback URLs and explorers only apply to their own network
+func TestNetworkFallbackURLs(t *testing.T) {
+	t.Setenv("ETH_NETWORK", "")
+	t.Setenv("CHAIN_ID", "")
+	t.Setenv("SEPOLIA_RPC_URL", "https://sepolia.example")
+	t.Setenv("RPC_FALLBACK_URLS", "https://sepolia-fallback.example, ")
+	t.Setenv("BLOCK_EXPLORER_URL", "https://sepolia-explorer.example/")
+	t.Setenv("SEPOLIA_RPC_FALLBACK_URLS", "")
+	t.Setenv("SEPOLIA_EXPLORER_URL", "")
+	t.Setenv("MAINNET_RPC_URL", "https://mainnet.example")
+	t.Setenv("MAINNET_RPC_FALLBACK_URLS", "")
+	t.Setenv("MAINNET_EXPLORER_URL", "")
+
+	// The global variables describe the default network
+	sepolia, err := SelectNetwork("sepolia", "")
+	if err != nil {
+		t.Fatalf("Failed to select sepolia: %v", err)
+	}
+	if fallbacks := sepolia.FallbackURLs(); len(fallbacks) != 1 || fallbacks[0] != "https://sepolia-fallback.example" {
+		t.Fatalf("Unexpected sepolia fallbacks %v", fallbacks)
+	}
+	if sepolia.Explorer != "https://sepolia-explorer.example" {
+		t.Fatalf("Unexpected sepolia explorer %s", sepolia.Explorer)
+	}
+
+	// ...and are ignored for any other network
+	mainnet, err := SelectNetwork("mainnet", "")
+	if err != nil {
+		t.Fatalf("Failed to select mainnet: %v", err)
+	}
+	if len(mainnet.FallbackURLs()) != 0 || mainnet.Explorer != "https://etherscan.io" {
+		t.Fatalf("Sepolia settings leaked into mainnet: %v %s", mainnet.RPCURLs, mainnet.Explorer)
+	}
+
+	// Per-network variables take precedence
+	t.Setenv("MAINNET_RPC_FALLBACK_URLS", "https://a.example,https://b.example")
+	t.Setenv("MAINNET_EXPLORER_URL", "https://explorer.example")
+	t.Setenv("SEPOLIA_RPC_FALLBACK_URLS", "https://c.example")
+	if mainnet, err = SelectNetwork("mainnet", ""); err != nil {
+		t.Fatalf("Failed to select mainnet: %v", err)
+	}
+	if len(mainnet.RPCURLs) != 3 || mainnet.RPCURLs[2] != "https://b.example" || mainnet.Explorer != "https://explorer.example" {
+		t.Fatalf("Unexpected mainnet profile: %v %s", mainnet.RPCURLs, mainnet.Explorer)
+	}
+	if sepolia, err = SelectNetwork("sepolia", ""); err != nil || sepolia.FallbackURLs()[0] != "https://c.example" {
+		t.Fatalf("Expected SEPOLIA_RPC_FALLBACK_URLS to replace RPC_FALLBACK_URLS, got %v (%v)", sepolia, err)
+	}
+}
+
+// TestChainIDMismatchRefusesToSign tests that nothing is signed or broadcast on the wrong chain
+func TestChainIDMismatchRefusesToSign(t *testing.T) {
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_chainId":
+			return "0x1", nil // mainnet
+		case "eth_getTransactionCount":
+			return "0x0", nil
+		case "eth_estimateGas":
+			return "0x5208", nil
+		case "eth_getBlockByNumber":
+			return map[string]string{"baseFeePerGas": "0x3b9aca00"}, nil
+		case "eth_sendRawTransaction":
+			return "0x" + testTxHash[2:], nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	client := NewClient(rpc.URL, WithChainID(11155111))
+
+	err := client.VerifyChainID(context.Background())
+	var mismatch *ChainIDMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Actual != 1 || mismatch.Expected != 11155111 {
+		t.Fatalf("Expected a chain ID mismatch, got %v", err)
+	}
+
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+	_, err = client.SendEIP1559Transaction(context.Background(), keyPair, testAddress, big.NewInt(1), nil)
+	if !errors.Is(err, ErrChainIDMismatch) {
+		t.Fatalf("Expected ErrChainIDMismatch from send, got %v", err)
+	}
+	if n := rpc.callCount("eth_sendRawTransaction"); n != 0 {
+		t.Fatalf("Transaction was broadcast %d time(s) despite the mismatch", n)
+	}
+
+	// A matching chain passes
+	if err := NewClient(rpc.URL, WithChainID(1)).VerifyChainID(context.Background()); err != nil {
+		t.Fatalf("Expected matching chain ID to verify, got %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/metana-bootcamp/ethwallet/internal/abi"
+)
+
+// NFTStandard identifies the token standard implemented by an NFT contract
+type NFTStandard string
+
+const (
+	ERC721  NFTStandard = "ERC-721"
+	ERC1155 NFTStandard = "ERC-1155"
+)
+
+// ERC-165 interface IDs of the NFT standards
+var (
+	erc721InterfaceID  = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	erc1155InterfaceID = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+)
+
+// DefaultIPFSGateway resolves ipfs:// URIs when IPFS_GATEWAY is not set
+const DefaultIPFSGateway = "https://ipfs.io/ipfs/"
+
+// maxMetadataSize limits the metadata document read from a URI
+const maxMetadataSize = 1 << 20
+
+// erc721ABI is the subset of the ERC-721 interface used by the wallet
+var erc721ABI = mustParseABI(
+	"function supportsInterface(bytes4 interfaceId) view returns (bool)",
+	"function name() view returns (string)",
+	"function symbol() view returns (string)",
+	"function balanceOf(address owner) view returns (uint256)",
+	"function ownerOf(uint256 tokenId) view returns (address)",
+	"function tokenURI(uint256 tokenId) view returns (string)",
+	"function safeTransferFrom(address from, address to, uint256 tokenId, bytes data)",
+)
+
+// erc1155ABI is the subset of the ERC-1155 interface used by the wallet
+var erc1155ABI = mustParseABI(
+	"function balanceOf(address account, uint256 id) view returns (uint256)",
+	"function balanceOfBatch(address[] accounts, uint256[] ids) view returns (uint256[])",
+	"function uri(uint256 id) view returns (string)",
+	"function safeTransferFrom(address from, address to, uint256 id, uint256 amount, bytes data)",
+)
+
+// NFT is an ERC-721 or ERC-1155 token contract
+type NFT struct {
+	Address common.Address
+	client  *Client
+}
+
+// NewNFT returns the NFT contract at address
+func NewNFT(client *Client, address common.Address) *NFT {
+	return &NFT{Address: address, client: client}
+}
+
+// call executes a read-only method of contractABI and returns its decoded outputs
+func (n *NFT) call(ctx context.Context, contractABI *abi.ABI, name string, args ...interface{}) ([]interface{}, error) {
+	return n.client.callMethod(ctx, n.Address, mustMethod(contractABI, name), args...)
+}
+
+// SupportsInterface reports whether the contract implements an ERC-165 interface.
+// Contracts without ERC-165 report false.
+func (n *NFT) SupportsInterface(ctx context.Context, interfaceID [4]byte) (bool, error) {
+	values, err := n.call(ctx, erc721ABI, "supportsInterface", interfaceID)
+	if err != nil {
+		if _, reverted := RevertData(err); reverted || errors.Is(err, ErrNoContractData) {
+			return false, nil
+		}
+		return false, err
+	}
+	return values[0].(bool), nil
+}
+
+// DetectStandard identifies the contract as ERC-721 or ERC-1155 using ERC-165
+func (n *NFT) DetectStandard(ctx context.Context) (NFTStandard, error) {
+	for _, candidate := range []struct {
+		standard NFTStandard
+		id       [4]byte
+	}{{ERC721, erc721InterfaceID}, {ERC1155, erc1155InterfaceID}} {
+		ok, err := n.SupportsInterface(ctx, candidate.id)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return candidate.standard, nil
+		}
+	}
+	return "", fmt.Errorf("contract %s does not report ERC-721 or ERC-1155 support through ERC-165", n.Address.Hex())
+}
+
+// OwnerOf returns the owner of an ERC-721 token
+func (n *NFT) OwnerOf(ctx context.Context, tokenID *big.Int) (common.Address, error) {
+	values, err := n.call(ctx, erc721ABI, "ownerOf", tokenID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return values[0].(common.Address), nil
+}
+
+// BalanceOf returns the number of ERC-721 tokens held by owner
+func (n *NFT) BalanceOf(ctx context.Context, owner common.Address) (*big.Int, error) {
+	values, err := n.call(ctx, erc721ABI, "balanceOf", owner)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// BalanceOfToken returns the ERC-1155 balance of account for token id
+func (n *NFT) BalanceOfToken(ctx context.Context, account common.Address, id *big.Int) (*big.Int, error) {
+	values, err := n.call(ctx, erc1155ABI, "balanceOf", account, id)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// BalanceOfBatch returns the ERC-1155 balances of accounts[i] for ids[i]
+func (n *NFT) BalanceOfBatch(ctx context.Context, accounts []common.Address, ids []*big.Int) ([]*big.Int, error) {
+	if len(accounts) != len(ids) {
+		return nil, fmt.Errorf("balanceOfBatch needs as many accounts as ids, got %d and %d", len(accounts), len(ids))
+	}
+
+	values, err := n.call(ctx, erc1155ABI, "balanceOfBatch", accounts, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	elems := values[0].([]interface{})
+	if len(elems) != len(ids) {
+		return nil, fmt.Errorf("balanceOfBatch returned %d balances for %d ids", len(elems), len(ids))
+	}
+	balances := make([]*big.Int, len(elems))
+	for i, elem := range elems {
+		balances[i] = elem.(*big.Int)
+	}
+	return balances, nil
+}
+
+// TokenURI returns the metadata URI of a token: tokenURI for ERC-721, and uri
+// with the {id} placeholder substituted for ERC-1155
+func (n *NFT) TokenURI(ctx context.Context, standard NFTStandard, tokenID *big.Int) (string, error) {
+	if standard == ERC1155 {
+		values, err := n.call(ctx, erc1155ABI, "uri", tokenID)
+		if err != nil {
+			return "", err
+		}
+		return ExpandERC1155URI(values[0].(string), tokenID), nil
+	}
+
+	values, err := n.call(ctx, erc721ABI, "tokenURI", tokenID)
+	if err != nil {
+		return "", err
+	}
+	return values[0].(string), nil
+}
+
+// ExpandERC1155URI substitutes the {id} placeholder of an ERC-1155 URI with the
+// token id as 64 lowercase hex digits
+func ExpandERC1155URI(uri string, id *big.Int) string {
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
+
+// SafeTransferFromERC721Data encodes safeTransferFrom(from, to, tokenId, data) calldata
+func SafeTransferFromERC721Data(from, to common.Address, tokenID *big.Int, data []byte) ([]byte, error) {
+	return mustMethod(erc721ABI, "safeTransferFrom").Pack(from, to, tokenID, data)
+}
+
+// SafeTransferFromERC1155Data encodes safeTransferFrom(from, to, id, amount, data) calldata
+func SafeTransferFromERC1155Data(from, to common.Address, id, amount *big.Int, data []byte) ([]byte, error) {
+	return mustMethod(erc1155ABI, "safeTransferFrom").Pack(from, to, id, amount, data)
+}
+
+// NFTMetadata is the ERC-721/ERC-1155 metadata JSON document
+type NFTMetadata struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Image       string          `json:"image"`
+	ExternalURL string          `json:"external_url"`
+	Attributes  []NFTAttribute  `json:"attributes"`
+	Raw         json.RawMessage `json:"-"`
+}
+
+// NFTAttribute is a trait in NFT metadata
+type NFTAttribute struct {
+	TraitType string      `json:"trait_type"`
+	Value     interface{} `json:"value"`
+}
+
+// GetIPFSGateway returns the gateway used for ipfs:// URIs
+func GetIPFSGateway() string {
+	if gateway := os.Getenv("IPFS_GATEWAY"); gateway != "" {
+		return strings.TrimSuffix(gateway, "/") + "/"
+	}
+	return DefaultIPFSGateway
+}
+
+// ResolveURI maps ipfs:// and ar:// URIs to HTTP gateway URLs; other URIs are returned unchanged
+func ResolveURI(uri string) string {
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return GetIPFSGateway() + path
+	case strings.HasPrefix(uri, "ar://"):
+		return "https://arweave.net/" + strings.TrimPrefix(uri, "ar://")
+	}
+	return uri
+}
+
+// ParseDataURI decodes an RFC 2397 data: URI, returning its media type and content
+func ParseDataURI(uri string) (string, []byte, error) {
+	if !strings.HasPrefix(uri, "data:") {
+		return "", nil, fmt.Errorf("not a data URI")
+	}
+	header, payload, found := strings.Cut(uri[len("data:"):], ",")
+	if !found {
+		return "", nil, fmt.Errorf("invalid data URI: missing ','")
+	}
+
+	mediaType, isBase64 := strings.CutSuffix(header, ";base64")
+	if mediaType == "" {
+		mediaType = "text/plain;charset=US-ASCII"
+	}
+
+	if isBase64 {
+		content, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			// Some contracts omit the padding
+			if content, err = base64.RawStdEncoding.DecodeString(payload); err != nil {
+				return "", nil, fmt.Errorf("invalid base64 in data URI: %v", err)
+			}
+		}
+		return mediaType, content, nil
+	}
+
+	content, err := url.PathUnescape(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid data URI: %v", err)
+	}
+	return mediaType, []byte(content), nil
+}
+
+// FetchNFTMetadata loads the metadata JSON from a data:, ipfs://, ar:// or HTTP(S) URI
+func FetchNFTMetadata(ctx context.Context, uri string) (*NFTMetadata, error) {
+	var content []byte
+	if strings.HasPrefix(uri, "data:") {
+		_, decoded, err := ParseDataURI(uri)
+		if err != nil {
+			return nil, err
+		}
+		content = decoded
+	} else {
+		resolved := ResolveURI(uri)
+		if !strings.HasPrefix(resolved, "https://") && !strings.HasPrefix(resolved, "http://") {
+			return nil, fmt.Errorf("unsupported metadata URI %q", uri)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, resolved, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+		httpClient := &http.Client{Timeout: DefaultRPCTimeout, Transport: sharedTransport}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching metadata: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("error fetching metadata: HTTP %d from %s", resp.StatusCode, resolved)
+		}
+		if content, err = io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize)); err != nil {
+			return nil, fmt.Errorf("error reading metadata: %v", err)
+		}
+	}
+
+	var metadata NFTMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %v", err)
+	}
+	metadata.Raw = content
+	return &metadata, nil
+}
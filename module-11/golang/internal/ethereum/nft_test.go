@@ -0,0 +1,193 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestNFTCalldata checks the NFT selectors and transfer encodings
+func TestNFTCalldata(t *testing.T) {
+	selectors := map[string]string{
+		"supportsInterface": "01ffc9a7",
+		"ownerOf":           "6352211e",
+		"tokenURI":          "c87b56dd",
+		"safeTransferFrom":  "b88d4fde",
+	}
+	for name, want := range selectors {
+		if got := hex.EncodeToString(mustMethod(erc721ABI, name).ID()); got != want {
+			t.Fatalf("ERC-721 %s selector %s, expected %s", name, got, want)
+		}
+	}
+	selectors = map[string]string{
+		"balanceOf":        "00fdd58e",
+		"balanceOfBatch":   "4e1273f4",
+		"uri":              "0e89341c",
+		"safeTransferFrom": "f242432a",
+	}
+	for name, want := range selectors {
+		if got := hex.EncodeToString(mustMethod(erc1155ABI, name).ID()); got != want {
+			t.Fatalf("ERC-1155 %s selector %s, expected %s", name, got, want)
+		}
+	}
+
+	from := common.HexToAddress(vectorTo)
+	to := common.HexToAddress("0x3535353535353535353535353535353535353535")
+	data, err := SafeTransferFromERC1155Data(from, to, big.NewInt(7), big.NewInt(2), nil)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	want := "f242432a" +
+		"000000000000000000000000de9ca654ae5a3673d894eba15b63603fa00f8504" +
+		"0000000000000000000000003535353535353535353535353535353535353535" +
+		"0000000000000000000000000000000000000000000000000000000000000007" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"00000000000000000000000000000000000000000000000000000000000000a0" +
+		"0000000000000000000000000000000000000000000000000000000000000000"
+	if got := hex.EncodeToString(data); got != want {
+		t.Fatalf("ERC-1155 transfer mismatch\n got: %s\nwant: %s", got, want)
+	}
+}
+
+// TestNFTURIs tests ERC-1155 id substitution, gateway resolution and data URIs
+func TestNFTURIs(t *testing.T) {
+	id, _ := new(big.Int).SetString("314592", 10)
+	if got := ExpandERC1155URI("https://token-cdn-domain/{id}.json", id); got != "https://token-cdn-domain/000000000000000000000000000000000000000000000000000000000004cce0.json" {
+		t.Fatalf("Unexpected ERC-1155 URI %s", got)
+	}
+
+	t.Setenv("IPFS_GATEWAY", "")
+	if got := ResolveURI("ipfs://ipfs/QmHash/1.json"); got != "https://ipfs.io/ipfs/QmHash/1.json" {
+		t.Fatalf("Unexpected IPFS URL %s", got)
+	}
+	t.Setenv("IPFS_GATEWAY", "https://gateway.example/ipfs")
+	if got := ResolveURI("ipfs://QmHash"); got != "https://gateway.example/ipfs/QmHash" {
+		t.Fatalf("Unexpected IPFS URL with gateway %s", got)
+	}
+
+	uris := map[string]string{
+		"data:application/json;base64,eyJuYW1lIjoiT25jaGFpbiAjMSJ9":   `{"name":"Onchain #1"}`,
+		"data:application/json;base64,eyJuYW1lIjoiT25jaGFpbiAjMSJ9fQ": `{"name":"Onchain #1"}}`, // unpadded
+		`data:application/json,{"name":"Plain%20%231"}`:               `{"name":"Plain #1"}`,
+		"data:,hello": "hello",
+	}
+	for uri, want := range uris {
+		_, content, err := ParseDataURI(uri)
+		if err != nil || string(content) != want {
+			t.Fatalf("ParseDataURI(%s) = %q, %v", uri, content, err)
+		}
+	}
+	if mediaType, _, _ := ParseDataURI("data:,hello"); mediaType != "text/plain;charset=US-ASCII" {
+		t.Fatalf("Unexpected default media type %s", mediaType)
+	}
+	if _, _, err := ParseDataURI("data:application/json;base64"); err == nil {
+		t.Fatalf("Expected an error for a data URI without payload")
+	}
+}
+
+// TestFetchNFTMetadata tests loading metadata from data URIs and HTTP
+func TestFetchNFTMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"name":"Token #1","image":"ipfs://QmImage","attributes":[{"trait_type":"Color","value":"Red"}]}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	metadata, err := FetchNFTMetadata(ctx, server.URL+"/1.json")
+	if err != nil {
+		t.Fatalf("Failed to fetch metadata: %v", err)
+	}
+	if metadata.Name != "Token #1" || len(metadata.Attributes) != 1 || metadata.Attributes[0].Value != "Red" {
+		t.Fatalf("Unexpected metadata %+v", metadata)
+	}
+	if _, err := FetchNFTMetadata(ctx, server.URL+"/2.json"); err == nil {
+		t.Fatalf("Expected an error for a missing document")
+	}
+
+	metadata, err = FetchNFTMetadata(ctx, "data:application/json;base64,eyJuYW1lIjoiT25jaGFpbiAjMSJ9")
+	if err != nil || metadata.Name != "Onchain #1" {
+		t.Fatalf("Unexpected data URI metadata %+v: %v", metadata, err)
+	}
+
+	if _, err := FetchNFTMetadata(ctx, "file:///etc/passwd"); err == nil {
+		t.Fatalf("Expected an error for an unsupported scheme")
+	}
+}
+
+// TestNFTReads tests standard detection, ownership and batch balances against a mock contract
+func TestNFTReads(t *testing.T) {
+	owner := common.HexToAddress(vectorTo)
+	erc721Address := "0x00000000000000000000000000000000000007a1"
+
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		if method != "eth_call" {
+			return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+		}
+		var msg map[string]string
+		json.Unmarshal(params[0], &msg)
+		data := msg["data"]
+		is721 := strings.EqualFold(msg["to"], erc721Address)
+
+		switch {
+		case strings.HasPrefix(data, "0x01ffc9a7"):
+			// ERC-165: the ERC-721 contract supports 0x80ac58cd, the other 0xd9b67a26
+			supported := (is721 && strings.HasPrefix(data[10:], "80ac58cd")) || (!is721 && strings.HasPrefix(data[10:], "d9b67a26"))
+			if supported {
+				return "0x" + abiWord(1), nil
+			}
+			return "0x" + abiWord(0), nil
+		case strings.HasPrefix(data, "0x6352211e"):
+			return "0x000000000000000000000000" + strings.ToLower(vectorTo[2:]), nil
+		case strings.HasPrefix(data, "0xc87b56dd"):
+			return "0x" + abiString("data:application/json;base64,eyJuYW1lIjoiT25jaGFpbiAjMSJ9"), nil
+		case strings.HasPrefix(data, "0x0e89341c"):
+			return "0x" + abiString("https://cdn.example/{id}.json"), nil
+		case strings.HasPrefix(data, "0x4e1273f4"):
+			return "0x" + abiWord(32) + abiWord(2) + abiWord(5) + abiWord(0), nil
+		}
+		return nil, &mockRPCError{Code: 3, Message: "execution reverted", Data: "0x"}
+	})
+
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+
+	nft := NewNFT(client, common.HexToAddress(erc721Address))
+	standard, err := nft.DetectStandard(ctx)
+	if err != nil || standard != ERC721 {
+		t.Fatalf("Expected ERC-721, got %s: %v", standard, err)
+	}
+	if got, err := nft.OwnerOf(ctx, big.NewInt(1)); err != nil || got != owner {
+		t.Fatalf("Unexpected owner %s: %v", got.Hex(), err)
+	}
+	uri, err := nft.TokenURI(ctx, standard, big.NewInt(1))
+	if err != nil || !strings.HasPrefix(uri, "data:application/json") {
+		t.Fatalf("Unexpected token URI %s: %v", uri, err)
+	}
+
+	multi := NewNFT(client, common.HexToAddress("0x0000000000000000000000000000000000001155"))
+	if standard, err := multi.DetectStandard(ctx); err != nil || standard != ERC1155 {
+		t.Fatalf("Expected ERC-1155, got %s: %v", standard, err)
+	}
+	uri, err = multi.TokenURI(ctx, ERC1155, big.NewInt(10))
+	if err != nil || uri != "https://cdn.example/000000000000000000000000000000000000000000000000000000000000000a.json" {
+		t.Fatalf("Unexpected ERC-1155 URI %s: %v", uri, err)
+	}
+	balances, err := multi.BalanceOfBatch(ctx, []common.Address{owner, owner}, []*big.Int{big.NewInt(1), big.NewInt(2)})
+	if err != nil || len(balances) != 2 || balances[0].Int64() != 5 || balances[1].Sign() != 0 {
+		t.Fatalf("Unexpected batch balances %v: %v", balances, err)
+	}
+	if _, err := multi.BalanceOfBatch(ctx, []common.Address{owner}, nil); err == nil {
+		t.Fatalf("Expected an error for mismatched batch lengths")
+	}
+}
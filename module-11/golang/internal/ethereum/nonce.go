@@ -0,0 +1,309 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultNonceGrace is how long a reserved nonce may stay unknown to the node
+// before it is treated as lost and handed out again
+const DefaultNonceGrace = time.Minute
+
+// NonceSource hands out nonces for transactions, so that transactions sent in
+// quick succession or in parallel do not reuse the node's pending nonce
+type NonceSource interface {
+	// Reserve returns the nonce for the next transaction of address on chainID.
+	// pending is the node's pending transaction count for the address.
+	Reserve(chainID uint64, address common.Address, pending uint64) (uint64, error)
+	// Release returns a reserved nonce whose transaction was never broadcast
+	Release(chainID uint64, address common.Address, nonce uint64) error
+}
+
+// WithNonceSource takes the transaction nonce from source instead of the node's
+// pending nonce. The nonce is released again when the node rejects the transaction.
+func WithNonceSource(source NonceSource) SendOption {
+	return func(o *sendOptions) {
+		o.nonceSource = source
+	}
+}
+
+// WithSentNonce stores the nonce the transaction was signed with in *nonce once
+// the node accepts it, for waiting on the transaction or deriving a contract address
+func WithSentNonce(nonce *uint64) SendOption {
+	return func(o *sendOptions) {
+		o.sentNonce = nonce
+	}
+}
+
+// nonceAccount is the nonce state of one account on one chain
+type nonceAccount struct {
+	Next     uint64               `json:"next"`     // next nonce to hand out
+	Reserved map[uint64]time.Time `json:"reserved"` // nonces handed out that the node has not counted yet
+}
+
+// NonceManager is a NonceSource keyed by chain ID and address. It is safe for
+// concurrent use; with a file path, reservations are persisted and the file is
+// locked while it is updated, so several processes can share it.
+type NonceManager struct {
+	path  string
+	grace time.Duration
+	now   func() time.Time
+
+	mu       sync.Mutex
+	accounts map[string]*nonceAccount // used when there is no file
+}
+
+// NewNonceManager creates a nonce manager persisted to path, or kept in memory
+// when path is empty
+func NewNonceManager(path string) *NonceManager {
+	return &NonceManager{
+		path:     path,
+		grace:    DefaultNonceGrace,
+		now:      time.Now,
+		accounts: make(map[string]*nonceAccount),
+	}
+}
+
+// GetNonceFile gets the nonce file path from ETHWALLET_NONCES or falls back to
+// ethwallet/nonces.json in the user cache directory
+func GetNonceFile() (string, error) {
+	LoadEnvVariables()
+
+	if path := os.Getenv("ETHWALLET_NONCES"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("no nonce file location: %v", err)
+	}
+	return filepath.Join(dir, "ethwallet", "nonces.json"), nil
+}
+
+// Reserve implements NonceSource. Nonces the node has counted are forgotten and
+// the counter never falls behind the node. When the node's pending nonce is a gap
+// (released, or reserved longer ago than the grace period and never seen by the
+// node) that nonce is handed out again, since every later transaction is stuck
+// behind it; otherwise the next unused nonce is returned.
+func (m *NonceManager) Reserve(chainID uint64, address common.Address, pending uint64) (uint64, error) {
+	var nonce uint64
+	err := m.update(chainID, address, func(account *nonceAccount) {
+		now := m.now()
+		account.sync(pending)
+
+		if pending < account.Next && !account.inFlight(pending, now, m.grace) {
+			nonce = pending
+		} else {
+			nonce = account.Next
+			account.Next++
+		}
+		account.Reserved[nonce] = now
+	})
+	return nonce, err
+}
+
+// Release implements NonceSource
+func (m *NonceManager) Release(chainID uint64, address common.Address, nonce uint64) error {
+	return m.update(chainID, address, func(account *nonceAccount) {
+		delete(account.Reserved, nonce)
+
+		// The last nonce handed out is simply taken back; an earlier one leaves a
+		// gap that Reserve fills once the node's pending nonce reaches it
+		if nonce+1 == account.Next {
+			account.Next--
+		}
+	})
+}
+
+// Gap returns the first nonce that was handed out but that the node does not
+// know, given its pending nonce. It reports false when there is no gap, or when
+// the missing nonce was reserved within the grace period and may still be on its
+// way to the node.
+func (m *NonceManager) Gap(chainID uint64, address common.Address, pending uint64) (uint64, bool, error) {
+	var gap bool
+	err := m.update(chainID, address, func(account *nonceAccount) {
+		account.sync(pending)
+		gap = pending < account.Next && !account.inFlight(pending, m.now(), m.grace)
+	})
+	return pending, gap, err
+}
+
+// Next returns the nonce the manager would hand out next, without reserving it
+func (m *NonceManager) Next(chainID uint64, address common.Address, pending uint64) (uint64, error) {
+	var next uint64
+	err := m.update(chainID, address, func(account *nonceAccount) {
+		account.sync(pending)
+		next = account.Next
+	})
+	return next, err
+}
+
+// Resync drops the local state of address and restarts from the node's pending
+// nonce, which it returns
+func (m *NonceManager) Resync(ctx context.Context, c *Client, address common.Address) (uint64, error) {
+	chainID, err := c.GetChainID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.checkChainID(chainID.Uint64()); err != nil {
+		return 0, err
+	}
+	pending, err := c.GetNonce(ctx, address)
+	if err != nil {
+		return 0, err
+	}
+
+	err = m.update(chainID.Uint64(), address, func(account *nonceAccount) {
+		account.Next = pending
+		account.Reserved = make(map[uint64]time.Time)
+	})
+	return pending, err
+}
+
+// sync forgets the nonces the node has counted and catches up with nonces used
+// by other wallets
+func (a *nonceAccount) sync(pending uint64) {
+	for nonce := range a.Reserved {
+		if nonce < pending {
+			delete(a.Reserved, nonce)
+		}
+	}
+	if a.Next < pending {
+		a.Next = pending
+	}
+}
+
+// inFlight reports whether nonce was reserved within the grace period
+func (a *nonceAccount) inFlight(nonce uint64, now time.Time, grace time.Duration) bool {
+	reserved, ok := a.Reserved[nonce]
+	return ok && now.Sub(reserved) < grace
+}
+
+// update runs fn on the state of one account under the manager's locks and saves the result
+func (m *NonceManager) update(chainID uint64, address common.Address, fn func(*nonceAccount)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := fmt.Sprintf("%d:%s", chainID, address.Hex())
+	if m.path == "" {
+		fn(m.account(m.accounts, key))
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0700); err != nil {
+		return fmt.Errorf("failed to create nonce directory: %v", err)
+	}
+	unlock, err := lockFile(m.path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock nonce file: %v", err)
+	}
+	defer unlock()
+
+	accounts, err := readNonceFile(m.path)
+	if err != nil {
+		return err
+	}
+	fn(m.account(accounts, key))
+	return writeNonceFile(m.path, accounts)
+}
+
+// account returns the state stored under key, creating it when missing
+func (m *NonceManager) account(accounts map[string]*nonceAccount, key string) *nonceAccount {
+	account, ok := accounts[key]
+	if !ok {
+		account = &nonceAccount{}
+		accounts[key] = account
+	}
+	if account.Reserved == nil {
+		account.Reserved = make(map[uint64]time.Time)
+	}
+	return account
+}
+
+// readNonceFile loads the nonce state; a missing file is empty
+func readNonceFile(path string) (map[string]*nonceAccount, error) {
+	accounts := make(map[string]*nonceAccount)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return accounts, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nonce file: %v", err)
+	}
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to parse nonce file %s: %v", path, err)
+	}
+	return accounts, nil
+}
+
+// writeNonceFile saves the nonce state through a temporary file, so readers never
+// see a partial write
+func writeNonceFile(path string, accounts map[string]*nonceAccount) error {
+	data, err := json.MarshalIndent(accounts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode nonce file: %v", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write nonce file: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write nonce file: %v", err)
+	}
+	return nil
+}
+
+// reserveNonce returns the nonce of a transaction: from the WithNonceSource
+// source when there is one, otherwise the node's pending nonce
+func (o *sendOptions) reserveNonce(chainID *big.Int, from common.Address, pending uint64) (uint64, error) {
+	if o.nonceSource == nil {
+		return pending, nil
+	}
+	nonce, err := o.nonceSource.Reserve(chainID.Uint64(), from, pending)
+	if err != nil {
+		return 0, fmt.Errorf("error reserving nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// releaseNonce gives a reserved nonce back when the transaction did not reach
+// the node's mempool. err is the broadcast error, nil when the transaction was
+// never sent; a JSON-RPC error means the node rejected it, unless the error says
+// a transaction with this nonce is already pending or mined (see nonceInUse).
+// After a transport error the transaction may have arrived, so the nonce stays
+// reserved until the node counts it or the grace period ends.
+func (o *sendOptions) releaseNonce(chainID *big.Int, from common.Address, nonce uint64, err error) {
+	if o.nonceSource == nil {
+		return
+	}
+	var rpcErr *RPCError
+	if err == nil || errors.As(err, &rpcErr) && !nonceInUse(rpcErr) {
+		o.nonceSource.Release(chainID.Uint64(), from, nonce)
+	}
+}
+
+// nonceInUse reports whether a rejected broadcast shows that a transaction with
+// the same nonce is already in the mempool or mined: this one ("already known"),
+// an earlier one ("nonce too low"), or another pending one the node will not
+// replace ("replacement transaction underpriced"). Reusing the nonce would collide.
+func nonceInUse(rpcErr *RPCError) bool {
+	msg := strings.ToLower(rpcErr.Message)
+	for _, known := range []string{
+		"already known", "known transaction", "already exists", "already imported",
+		"nonce too low", "replacement transaction underpriced", "replacement underpriced",
+	} {
+		if strings.Contains(msg, known) {
+			return true
+		}
+	}
+	return false
+}
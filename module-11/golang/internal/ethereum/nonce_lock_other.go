@@ -0,0 +1,34 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockFileTimeout bounds the wait for another process to release the lock
+const lockFileTimeout = 10 * time.Second
+
+// lockFile takes an exclusive lock by creating path, waiting while another
+// process holds it, and returns the function that releases it. A lock file left
+// behind by a crashed process must be removed by hand.
+func lockFile(path string) (func() error, error) {
+	deadline := time.Now().Add(lockFileTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() error { return os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is held by another process", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,226 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TestNonceManagerConcurrent tests that parallel reservations, in one process and
+// through a shared file, never hand out the same nonce twice
+func TestNonceManagerConcurrent(t *testing.T) {
+	address := HexToAddress(testAddress)
+	path := filepath.Join(t.TempDir(), "nonces.json")
+
+	for name, managers := range map[string][]*NonceManager{
+		"memory": {NewNonceManager("")},
+		"file":   {NewNonceManager(path), NewNonceManager(path)},
+	} {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		var nonces []uint64
+		for i := 0; i < 40; i++ {
+			wg.Add(1)
+			go func(m *NonceManager) {
+				defer wg.Done()
+				nonce, err := m.Reserve(1, address, 5)
+				if err != nil {
+					t.Errorf("Failed to reserve a nonce: %v", err)
+					return
+				}
+				mu.Lock()
+				nonces = append(nonces, nonce)
+				mu.Unlock()
+			}(managers[i%len(managers)])
+		}
+		wg.Wait()
+
+		sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+		for i, nonce := range nonces {
+			if nonce != uint64(5+i) {
+				t.Fatalf("%s: nonces are not unique and consecutive: %v", name, nonces)
+			}
+		}
+	}
+
+	// Another chain has its own counter
+	if nonce, err := NewNonceManager(path).Reserve(11155111, address, 0); err != nil || nonce != 0 {
+		t.Fatalf("Expected nonce 0 on another chain, got %d, %v", nonce, err)
+	}
+}
+
+// TestNonceManagerGaps tests releasing nonces and refilling gaps the node never saw
+func TestNonceManagerGaps(t *testing.T) {
+	address := HexToAddress(testAddress)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewNonceManager("")
+	m.now = func() time.Time { return now }
+
+	reserve := func(pending, want uint64) {
+		t.Helper()
+		if nonce, err := m.Reserve(1, address, pending); err != nil || nonce != want {
+			t.Fatalf("Reserve(pending %d) = %d, %v; expected %d", pending, nonce, err, want)
+		}
+	}
+
+	reserve(0, 0)
+	reserve(0, 1)
+	reserve(0, 2)
+
+	// The last nonce is taken back; an earlier one is reused once the node reaches it
+	m.Release(1, address, 2)
+	reserve(0, 2)
+	m.Release(1, address, 1)
+	reserve(1, 1)
+
+	// Nonce 3 never reaches the node; within the grace period it may still be in flight
+	reserve(3, 3)
+	reserve(3, 4)
+	if _, gap, _ := m.Gap(1, address, 3); gap {
+		t.Fatalf("Expected no gap within the grace period")
+	}
+	now = now.Add(2 * DefaultNonceGrace)
+	if nonce, gap, _ := m.Gap(1, address, 3); !gap || nonce != 3 {
+		t.Fatalf("Expected a gap at nonce 3, got %d, %v", nonce, gap)
+	}
+	reserve(3, 3)
+	reserve(3, 5)
+
+	// Transactions sent by other wallets move the counter forward
+	reserve(10, 10)
+	if next, _ := m.Next(1, address, 10); next != 11 {
+		t.Fatalf("Next = %d, expected 11", next)
+	}
+}
+
+// TestSendWithNonceSource tests that sends take nonces from the source and give
+// them back when the node rejects the transaction
+func TestSendWithNonceSource(t *testing.T) {
+	var rawTxs [][]byte
+	reject := true
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_chainId":
+			return "0xaa36a7", nil
+		case "eth_getTransactionCount":
+			return "0x4", nil
+		case "eth_estimateGas":
+			return "0x5208", nil
+		case "eth_getBlockByNumber":
+			return map[string]string{"baseFeePerGas": "0x3b9aca00"}, nil
+		case "eth_feeHistory":
+			return mockFeeHistory(), nil
+		case "eth_gasPrice":
+			return "0x3b9aca00", nil
+		case "eth_sendRawTransaction":
+			if reject {
+				reject = false
+				return nil, &mockRPCError{Code: -32000, Message: "insufficient funds for gas * price + value"}
+			}
+			var rawHex string
+			json.Unmarshal(params[0], &rawHex)
+			raw, _ := HexDecode(rawHex)
+			rawTxs = append(rawTxs, raw)
+			return testTxHash, nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+
+	nonces := NewNonceManager("")
+	opt := WithNonceSource(nonces)
+	if _, err := client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(1), nil, opt); err == nil {
+		t.Fatalf("Expected the rejected send to fail")
+	}
+
+	// The node still reports pending nonce 4 for both sends
+	if _, err := client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(1), nil, opt); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+	var sent uint64
+	if _, err := client.SendTransaction(ctx, keyPair, vectorTo, big.NewInt(1), opt, WithSentNonce(&sent)); err != nil {
+		t.Fatalf("Failed to send legacy transaction: %v", err)
+	}
+	if sent != 5 {
+		t.Fatalf("Expected sent nonce 5, got %d", sent)
+	}
+
+	var typed, legacy []rlp.RawValue
+	rlp.DecodeBytes(rawTxs[0][1:], &typed)
+	rlp.DecodeBytes(rawTxs[1], &legacy)
+	var first, second uint64
+	rlp.DecodeBytes(typed[1], &first)
+	rlp.DecodeBytes(legacy[0], &second)
+	if first != 4 || second != 5 {
+		t.Fatalf("Expected nonces 4 and 5, got %d and %d", first, second)
+	}
+
+	// Resync goes back to the node's pending nonce
+	if pending, err := nonces.Resync(ctx, client, keyPair.Address); err != nil || pending != 4 {
+		t.Fatalf("Resync = %d, %v", pending, err)
+	}
+	if next, _ := nonces.Next(11155111, keyPair.Address, 4); next != 4 {
+		t.Fatalf("Expected nonce 4 after resync, got %d", next)
+	}
+}
+
+// TestSendKeepsNonceInUse tests that a nonce stays reserved when the node's
+// rejection shows a transaction with that nonce is already pending or mined
+func TestSendKeepsNonceInUse(t *testing.T) {
+	rejection := ""
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_chainId":
+			return "0xaa36a7", nil
+		case "eth_getTransactionCount":
+			return "0x4", nil
+		case "eth_estimateGas":
+			return "0x5208", nil
+		case "eth_getBlockByNumber":
+			return map[string]string{"baseFeePerGas": "0x3b9aca00"}, nil
+		case "eth_feeHistory":
+			return mockFeeHistory(), nil
+		case "eth_sendRawTransaction":
+			return nil, &mockRPCError{Code: -32000, Message: rejection}
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+
+	for _, tc := range []struct {
+		message string
+		next    uint64
+	}{
+		{"already known", 5},
+		{"nonce too low: next nonce 5, tx nonce 4", 5},
+		{"replacement transaction underpriced", 5},
+		{"transaction underpriced", 4},
+		{"insufficient funds for gas * price + value", 4},
+	} {
+		rejection = tc.message
+		nonces := NewNonceManager("")
+		if _, err := client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(1), nil, WithNonceSource(nonces)); err == nil {
+			t.Fatalf("%s: expected the send to fail", tc.message)
+		}
+		if next, _ := nonces.Next(11155111, keyPair.Address, 4); next != tc.next {
+			t.Fatalf("%s: expected next nonce %d, got %d", tc.message, tc.next, next)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package ethereum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/metana-bootcamp/ethwallet/internal/address"
+)
+
+// UnsignedTxVersion is the version of the unsigned transaction file format
+const UnsignedTxVersion = 1
+
+// Transaction types
+const (
+	TxTypeLegacy     = 0
+	TxTypeAccessList = 1 // EIP-2930
+	TxTypeDynamicFee = 2 // EIP-1559
+)
+
+// txTypeNames are the names of the transaction types in unsigned transaction files
+var txTypeNames = map[uint64]string{
+	TxTypeLegacy:     "legacy",
+	TxTypeAccessList: "eip2930",
+	TxTypeDynamicFee: "eip1559",
+}
+
+// UnsignedTx is a transaction with every field filled in except the signature.
+// It is built online and signed on a machine that has the key but no network.
+type UnsignedTx struct {
+	Type                 uint64
+	Network              string // network name, for review only
+	ChainID              *big.Int
+	From                 common.Address
+	To                   *common.Address // nil for contract creation
+	Value                *big.Int
+	Nonce                uint64
+	GasLimit             uint64
+	GasPrice             *big.Int // legacy and EIP-2930
+	MaxPriorityFeePerGas *big.Int // EIP-1559
+	MaxFeePerGas         *big.Int // EIP-1559
+	Data                 []byte
+	AccessList           AccessList
+}
+
+// unsignedTxJSON is the file format of an UnsignedTx. Amounts are decimal wei
+// strings and addresses are checksummed, so the file can be reviewed by hand.
+type unsignedTxJSON struct {
+	Version              int        `json:"version"`
+	Type                 string     `json:"type"`
+	Network              string     `json:"network,omitempty"`
+	ChainID              string     `json:"chainId"`
+	From                 string     `json:"from"`
+	To                   *string    `json:"to"`
+	Value                string     `json:"value"`
+	Nonce                uint64     `json:"nonce"`
+	Gas                  uint64     `json:"gas"`
+	GasPrice             string     `json:"gasPrice,omitempty"`
+	MaxPriorityFeePerGas string     `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerGas         string     `json:"maxFeePerGas,omitempty"`
+	Data                 string     `json:"data,omitempty"`
+	AccessList           AccessList `json:"accessList,omitempty"`
+}
+
+// MarshalJSON writes the versioned file format
+func (u *UnsignedTx) MarshalJSON() ([]byte, error) {
+	name, ok := txTypeNames[u.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported transaction type %d", u.Type)
+	}
+
+	j := unsignedTxJSON{
+		Version: UnsignedTxVersion,
+		Type:    name,
+		Network: u.Network,
+		ChainID: u.ChainID.String(),
+		From:    address.Checksum(u.From, 0),
+		Value:   u.Value.String(),
+		Nonce:   u.Nonce,
+		Gas:     u.GasLimit,
+	}
+	if u.To != nil {
+		to := address.Checksum(*u.To, 0)
+		j.To = &to
+	}
+	if u.Type == TxTypeDynamicFee {
+		j.MaxPriorityFeePerGas = u.MaxPriorityFeePerGas.String()
+		j.MaxFeePerGas = u.MaxFeePerGas.String()
+	} else {
+		j.GasPrice = u.GasPrice.String()
+	}
+	if len(u.Data) > 0 {
+		j.Data = "0x" + hex.EncodeToString(u.Data)
+	}
+	if u.Type != TxTypeLegacy {
+		j.AccessList = u.AccessList
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON reads the file format, rejecting unknown versions and missing fields
+func (u *UnsignedTx) UnmarshalJSON(data []byte) error {
+	var j unsignedTxJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Version != UnsignedTxVersion {
+		return fmt.Errorf("unsupported unsigned transaction version %d (expected %d)", j.Version, UnsignedTxVersion)
+	}
+
+	tx := UnsignedTx{Network: j.Network, Nonce: j.Nonce, GasLimit: j.Gas, AccessList: j.AccessList}
+	found := false
+	for txType, name := range txTypeNames {
+		if name == j.Type {
+			tx.Type, found = txType, true
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown transaction type %q (use legacy, eip2930 or eip1559)", j.Type)
+	}
+
+	var err error
+	if tx.ChainID, err = parseWei("chainId", j.ChainID); err != nil {
+		return err
+	}
+	if tx.ChainID.Sign() == 0 {
+		return fmt.Errorf("chainId is required")
+	}
+	if tx.From, err = address.Parse(j.From, 0); err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	if j.To != nil {
+		to, err := address.Parse(*j.To, 0)
+		if err != nil {
+			return fmt.Errorf("invalid to address: %w", err)
+		}
+		tx.To = &to
+	}
+	if tx.Value, err = parseWei("value", j.Value); err != nil {
+		return err
+	}
+	if tx.GasLimit == 0 {
+		return fmt.Errorf("gas is required")
+	}
+
+	if tx.Type == TxTypeDynamicFee {
+		if tx.MaxPriorityFeePerGas, err = parseWei("maxPriorityFeePerGas", j.MaxPriorityFeePerGas); err != nil {
+			return err
+		}
+		if tx.MaxFeePerGas, err = parseWei("maxFeePerGas", j.MaxFeePerGas); err != nil {
+			return err
+		}
+		if tx.MaxFeePerGas.Cmp(tx.MaxPriorityFeePerGas) < 0 {
+			return fmt.Errorf("maxFeePerGas %s is below maxPriorityFeePerGas %s", tx.MaxFeePerGas, tx.MaxPriorityFeePerGas)
+		}
+	} else if tx.GasPrice, err = parseWei("gasPrice", j.GasPrice); err != nil {
+		return err
+	}
+
+	if j.Data != "" {
+		if tx.Data, err = HexDecode(j.Data); err != nil {
+			return fmt.Errorf("invalid data: %v", err)
+		}
+	}
+	if tx.Type == TxTypeLegacy && len(j.AccessList) > 0 {
+		return fmt.Errorf("legacy transactions cannot have an access list")
+	}
+	if tx.Type == TxTypeAccessList && tx.AccessList == nil {
+		tx.AccessList = AccessList{}
+	}
+
+	*u = tx
+	return nil
+}
+
+// parseWei parses a required non-negative decimal amount
+func parseWei(field, s string) (*big.Int, error) {
+	if s == "" {
+		return nil, fmt.Errorf("%s is required", field)
+	}
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid %s %q: must be a non-negative decimal number", field, s)
+	}
+	return value, nil
+}
+
+// LoadUnsignedTx reads an unsigned transaction file
+func LoadUnsignedTx(path string) (*UnsignedTx, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read unsigned transaction: %v", err)
+	}
+	var tx UnsignedTx
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("failed to parse unsigned transaction %s: %v", path, err)
+	}
+	return &tx, nil
+}
+
+// SaveUnsignedTx writes an unsigned transaction file
+func SaveUnsignedTx(tx *UnsignedTx, path string) error {
+	data, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode unsigned transaction: %v", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// MaxCost returns the most the transaction can cost the sender: the value plus
+// the gas limit at the max fee (or gas price)
+func (u *UnsignedTx) MaxCost() *big.Int {
+	fee := u.GasPrice
+	if u.Type == TxTypeDynamicFee {
+		fee = u.MaxFeePerGas
+	}
+	cost := new(big.Int).Mul(fee, new(big.Int).SetUint64(u.GasLimit))
+	return cost.Add(cost, u.Value)
+}
+
+// Sign signs the transaction and returns the raw transaction bytes. It needs no
+// network access, and refuses keys that do not belong to the From address.
+func (u *UnsignedTx) Sign(priv *ecdsa.PrivateKey) ([]byte, error) {
+	if signer := crypto.PubkeyToAddress(priv.PublicKey); signer != u.From {
+		return nil, fmt.Errorf("the key of %s cannot sign a transaction from %s", signer.Hex(), u.From.Hex())
+	}
+
+	var to *[20]byte
+	if u.To != nil {
+		addr := [20]byte(*u.To)
+		to = &addr
+	}
+
+	switch u.Type {
+	case TxTypeDynamicFee:
+		tx := &TX1559{
+			ChainID:              u.ChainID,
+			Nonce:                u.Nonce,
+			MaxPriorityFeePerGas: u.MaxPriorityFeePerGas,
+			MaxFeePerGas:         u.MaxFeePerGas,
+			GasLimit:             u.GasLimit,
+			To:                   to,
+			Value:                u.Value,
+			Data:                 u.Data,
+			AccessList:           u.AccessList,
+		}
+		return tx.Sign(priv)
+	case TxTypeAccessList:
+		tx := &TX2930{
+			ChainID:    u.ChainID,
+			Nonce:      u.Nonce,
+			GasPrice:   u.GasPrice,
+			GasLimit:   u.GasLimit,
+			To:         to,
+			Value:      u.Value,
+			Data:       u.Data,
+			AccessList: u.AccessList,
+		}
+		return tx.Sign(priv)
+	case TxTypeLegacy:
+		tx := &TXLegacy{
+			Nonce:    u.Nonce,
+			GasPrice: u.GasPrice,
+			GasLimit: u.GasLimit,
+			To:       to,
+			Value:    u.Value,
+			Data:     u.Data,
+			ChainID:  u.ChainID,
+		}
+		return tx.Sign(priv)
+	}
+	return nil, fmt.Errorf("unsupported transaction type %d", u.Type)
+}
+
+// BuildTransaction prepares a legacy (type-0) transaction priced with eth_gasPrice
+// (or WithGasPrice), or an EIP-2930 (type-1) transaction when an access list is
+// given, without signing it. The nonce is the node's pending nonce. An empty
+// toAddress deploys the WithData init code as a contract.
+func (c *Client) BuildTransaction(ctx context.Context, from common.Address, toAddress string, valueWei *big.Int, opts ...SendOption) (*UnsignedTx, error) {
+	o := applySendOptions(opts)
+
+	// Get chain ID, nonce and gas estimate in one round trip
+	preflight, err := c.Preflight(ctx, from, toAddress, valueWei, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Legacy transactions pay a single gas price
+	gasPrice := o.gasPrice
+	if gasPrice == nil {
+		if gasPrice, err = c.GetGasPrice(ctx); err != nil {
+			return nil, fmt.Errorf("error getting gas price: %w", err)
+		}
+	}
+	if err := checkFeeCap(gasPrice, o.feeCap); err != nil {
+		return nil, err
+	}
+
+	tx, err := newUnsignedTx(TxTypeLegacy, preflight, from, toAddress, valueWei, o)
+	if err != nil {
+		return nil, err
+	}
+	if o.accessList != nil {
+		tx.Type = TxTypeAccessList
+	}
+	tx.GasPrice = gasPrice
+	return tx, nil
+}
+
+// BuildEIP1559Transaction prepares an EIP-1559 transaction without signing it. A
+// nil priorityFeeWei, or a missing WithMaxFeePerGas, is filled in from the
+// WithFeeSpeed preset of SuggestFees. The nonce is the node's pending nonce. An
+// empty toAddress deploys the WithData init code as a contract.
+func (c *Client) BuildEIP1559Transaction(ctx context.Context, from common.Address, toAddress string, valueWei *big.Int, priorityFeeWei *big.Int, opts ...SendOption) (*UnsignedTx, error) {
+	o := applySendOptions(opts)
+
+	// Get chain ID, nonce and gas estimate in one round trip
+	preflight, err := c.Preflight(ctx, from, toAddress, valueWei, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in missing fees from recent blocks and enforce the fee cap
+	priorityFeeWei, maxFeePerGas, err := c.eip1559Fees(ctx, priorityFeeWei, o)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := newUnsignedTx(TxTypeDynamicFee, preflight, from, toAddress, valueWei, o)
+	if err != nil {
+		return nil, err
+	}
+	tx.MaxPriorityFeePerGas, tx.MaxFeePerGas = priorityFeeWei, maxFeePerGas
+	return tx, nil
+}
+
+// newUnsignedTx fills in the fields shared by every transaction type
+func newUnsignedTx(txType uint64, preflight *TxPreflight, from common.Address, toAddress string, valueWei *big.Int, o *sendOptions) (*UnsignedTx, error) {
+	// Decode to address, nil for contract creation
+	to, err := decodeRecipient(toAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &UnsignedTx{
+		Type:       txType,
+		ChainID:    preflight.ChainID,
+		From:       from,
+		Value:      valueWei,
+		Nonce:      preflight.Nonce,
+		GasLimit:   preflight.GasLimit,
+		Data:       o.data,
+		AccessList: o.accessList,
+	}
+	if to != nil {
+		addr := common.Address(*to)
+		tx.To = &addr
+	}
+	return tx, nil
+}
+
+// SendRawTransaction broadcasts a signed transaction with eth_sendRawTransaction
+// and returns its hash
+func (c *Client) SendRawTransaction(ctx context.Context, rawTx []byte) (string, error) {
+	txHash, err := c.Call(ctx, "eth_sendRawTransaction", []interface{}{"0x" + hex.EncodeToString(rawTx)})
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(string(txHash), "\""), nil
+}
+
+// signAndSend takes the nonce from the nonce source, if any, then signs and
+// broadcasts tx, releasing the nonce when the transaction did not reach the node
+// and reporting it to WithSentNonce when it did
+func (c *Client) signAndSend(ctx context.Context, fromKeyPair *KeyPair, tx *UnsignedTx, o *sendOptions) (string, error) {
+	nonce, err := o.reserveNonce(tx.ChainID, fromKeyPair.Address, tx.Nonce)
+	if err != nil {
+		return "", err
+	}
+	tx.Nonce = nonce
+
+	rawTx, err := tx.Sign(fromKeyPair.PrivateKey)
+	if err != nil {
+		o.releaseNonce(tx.ChainID, fromKeyPair.Address, nonce, nil)
+		return "", fmt.Errorf("error signing transaction: %w", err)
+	}
+
+	txHash, err := c.SendRawTransaction(ctx, rawTx)
+	if err != nil {
+		o.releaseNonce(tx.ChainID, fromKeyPair.Address, nonce, err)
+		return "", fmt.Errorf("error sending transaction: %w", err)
+	}
+	if o.sentNonce != nil {
+		*o.sentNonce = nonce
+	}
+	return txHash, nil
+}
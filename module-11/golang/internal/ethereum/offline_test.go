@@ -0,0 +1,179 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// vectorUnsignedTx is the unsigned form of the TestEIP1559TxVector transaction
+const vectorUnsignedTx = `{
+  "version": 1,
+  "type": "eip1559",
+  "network": "sepolia",
+  "chainId": "11155111",
+  "from": "0xA65e6944Fe4Fa192A61a5454fA198a584365A28A",
+  "to": "0xde9ca654aE5a3673d894eba15b63603Fa00F8504",
+  "value": "1000",
+  "nonce": 5,
+  "gas": 21000,
+  "maxPriorityFeePerGas": "1500000000",
+  "maxFeePerGas": "31500000000"
+}`
+
+// TestUnsignedTxSign tests that a hand-written unsigned transaction file signs to
+// the known EIP-1559 vector
+func TestUnsignedTxSign(t *testing.T) {
+	key, err := crypto.HexToECDSA(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Invalid key: %v", err)
+	}
+
+	var tx UnsignedTx
+	if err := json.Unmarshal([]byte(vectorUnsignedTx), &tx); err != nil {
+		t.Fatalf("Failed to parse unsigned transaction: %v", err)
+	}
+	raw, err := tx.Sign(key)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+
+	want := "02f87083aa36a7058459682f008507558bdb0082520894de9ca654ae5a3673d894eba15b63603fa00f85048203e880c001a09f0dbb5f88a0d8905b3141adfabe782f5742dff6dc474e47af34725988d510d1a023d94d3c1d6237fa46010ce1a22e4f1f70b1c622fe91c695db241600c516672c"
+	if got := hex.EncodeToString(raw); got != want {
+		t.Fatalf("Raw transaction mismatch\n got: %s\nwant: %s", got, want)
+	}
+
+	// Another key must not sign for the From address
+	other, _ := crypto.GenerateKey()
+	if _, err := tx.Sign(other); err == nil {
+		t.Fatalf("Expected an error signing with the wrong key")
+	}
+}
+
+// TestUnsignedTxFormat tests that invalid or unknown unsigned transaction files are rejected
+func TestUnsignedTxFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		wantErr string
+	}{
+		{"future version", `"version": 1`, `"version": 2`, "unsupported unsigned transaction version 2"},
+		{"unknown type", `"eip1559"`, `"eip4844"`, "unknown transaction type"},
+		{"bad checksum", `0xde9ca654aE5a`, `0xDe9ca654aE5a`, "checksum"},
+		{"hex value", `"1000"`, `"0x3e8"`, "invalid value"},
+		{"missing fee", `"maxFeePerGas": "31500000000"`, `"gasPrice": "1"`, "maxFeePerGas is required"},
+		{"fees swapped", `"1500000000"`, `"41500000000"`, "is below maxPriorityFeePerGas"},
+		{"no gas", `"gas": 21000`, `"gas": 0`, "gas is required"},
+	}
+	for _, tt := range tests {
+		var tx UnsignedTx
+		err := json.Unmarshal([]byte(strings.Replace(vectorUnsignedTx, tt.old, tt.new, 1)), &tx)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+// TestBuildTransaction tests that built transactions survive a save and load and
+// sign to the same transaction the send functions broadcast
+func TestBuildTransaction(t *testing.T) {
+	var sent []string
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_chainId":
+			return "0xaa36a7", nil
+		case "eth_getTransactionCount":
+			return "0x7", nil
+		case "eth_estimateGas":
+			return "0x7530", nil
+		case "eth_getBlockByNumber":
+			return map[string]string{"baseFeePerGas": "0x3b9aca00"}, nil
+		case "eth_feeHistory":
+			return mockFeeHistory(), nil
+		case "eth_gasPrice":
+			return "0x77359400", nil
+		case "eth_sendRawTransaction":
+			var rawHex string
+			json.Unmarshal(params[0], &rawHex)
+			sent = append(sent, rawHex)
+			return testTxHash, nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+
+	accessList := AccessList{{Address: HexToAddress(vectorTo)}}
+	data := []byte{0xde, 0xad, 0xbe, 0xef}
+	dynamic, err := client.BuildEIP1559Transaction(ctx, keyPair.Address, vectorTo, big.NewInt(1000), nil, WithData(data))
+	if err != nil {
+		t.Fatalf("Failed to build EIP-1559 transaction: %v", err)
+	}
+	typed, err := client.BuildTransaction(ctx, keyPair.Address, vectorTo, big.NewInt(1000), WithAccessList(accessList))
+	if err != nil {
+		t.Fatalf("Failed to build EIP-2930 transaction: %v", err)
+	}
+	deploy, err := client.BuildTransaction(ctx, keyPair.Address, "", big.NewInt(0), WithData(data))
+	if err != nil {
+		t.Fatalf("Failed to build contract creation: %v", err)
+	}
+
+	if dynamic.Type != TxTypeDynamicFee || dynamic.Nonce != 7 || dynamic.GasLimit != 36000 || dynamic.MaxFeePerGas.Int64() != 3_500_000_000 {
+		t.Fatalf("Unexpected EIP-1559 transaction: %+v", dynamic)
+	}
+	if typed.Type != TxTypeAccessList || typed.GasPrice.Int64() != 2_000_000_000 {
+		t.Fatalf("Unexpected EIP-2930 transaction: %+v", typed)
+	}
+	if deploy.Type != TxTypeLegacy || deploy.To != nil {
+		t.Fatalf("Unexpected contract creation: %+v", deploy)
+	}
+	if cost := dynamic.MaxCost(); cost.Int64() != 1000+36000*3_500_000_000 {
+		t.Fatalf("MaxCost = %s", cost)
+	}
+
+	dir := t.TempDir()
+	for i, tx := range []*UnsignedTx{dynamic, typed, deploy} {
+		path := filepath.Join(dir, "unsigned.json")
+		if err := SaveUnsignedTx(tx, path); err != nil {
+			t.Fatalf("Failed to save: %v", err)
+		}
+		loaded, err := LoadUnsignedTx(path)
+		if err != nil {
+			t.Fatalf("Failed to load: %v", err)
+		}
+		raw, err := loaded.Sign(keyPair.PrivateKey)
+		if err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		if _, err := client.SendRawTransaction(ctx, raw); err != nil {
+			t.Fatalf("Failed to broadcast: %v", err)
+		}
+
+		// The same transaction sent online
+		switch i {
+		case 0:
+			_, err = client.SendEIP1559Transaction(ctx, keyPair, vectorTo, big.NewInt(1000), nil, WithData(data))
+		case 1:
+			_, err = client.SendTransaction(ctx, keyPair, vectorTo, big.NewInt(1000), WithAccessList(accessList))
+		case 2:
+			_, err = client.SendTransaction(ctx, keyPair, "", big.NewInt(0), WithData(data))
+		}
+		if err != nil {
+			t.Fatalf("Failed to send: %v", err)
+		}
+		if sent[2*i] != sent[2*i+1] {
+			t.Fatalf("Transaction %d differs when signed offline\noffline: %s\n online: %s", i, sent[2*i], sent[2*i+1])
+		}
+	}
+}
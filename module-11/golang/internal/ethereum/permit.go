@@ -0,0 +1,214 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/metana-bootcamp/ethwallet/internal/eip712"
+)
+
+// permitABI is the EIP-2612 extension of ERC-20, with the optional version()
+// that OpenZeppelin's ERC20Permit does not expose but many tokens do
+var permitABI = mustParseABI(
+	"function nonces(address owner) view returns (uint256)",
+	"function DOMAIN_SEPARATOR() view returns (bytes32)",
+	"function version() view returns (string)",
+	"function permit(address owner, address spender, uint256 value, uint256 deadline, uint8 v, bytes32 r, bytes32 s)",
+)
+
+// permit2ABI is the subset of Uniswap's Permit2 (AllowanceTransfer) used by the wallet
+var permit2ABI = mustParseABI(
+	"function allowance(address user, address token, address spender) view returns (uint160 amount, uint48 expiration, uint48 nonce)",
+)
+
+// Permit2Address is the Uniswap Permit2 contract, deployed at the same address on every chain
+var Permit2Address = common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+
+var (
+	// MaxUint160 is the largest Permit2 allowance, which Permit2 treats as unlimited
+	MaxUint160 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+
+	// MaxUint48 is the largest Permit2 expiration timestamp and nonce
+	MaxUint48 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 48), big.NewInt(1))
+)
+
+// ErrPermitNotSupported is returned for tokens without EIP-2612 nonces and DOMAIN_SEPARATOR
+var ErrPermitNotSupported = errors.New("token does not support EIP-2612 permit")
+
+// permitTypes are the EIP-2612 Permit struct fields
+var permitTypes = []eip712.Field{
+	{Name: "owner", Type: "address"},
+	{Name: "spender", Type: "address"},
+	{Name: "value", Type: "uint256"},
+	{Name: "nonce", Type: "uint256"},
+	{Name: "deadline", Type: "uint256"},
+}
+
+// permit2Types are the Permit2 AllowanceTransfer structs; PermitSingle and
+// PermitBatch differ only in holding one or a list of PermitDetails
+var permit2Types = eip712.Types{
+	"PermitDetails": {
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint160"},
+		{Name: "expiration", Type: "uint48"},
+		{Name: "nonce", Type: "uint48"},
+	},
+	"PermitSingle": {
+		{Name: "details", Type: "PermitDetails"},
+		{Name: "spender", Type: "address"},
+		{Name: "sigDeadline", Type: "uint256"},
+	},
+	"PermitBatch": {
+		{Name: "details", Type: "PermitDetails[]"},
+		{Name: "spender", Type: "address"},
+		{Name: "sigDeadline", Type: "uint256"},
+	},
+}
+
+// Permit builds the EIP-2612 typed data that lets spender transfer value tokens
+// from owner until deadline (a Unix timestamp), reading the owner's nonce and the
+// token's domain from the chain. The domain version comes from version() when
+// the token has it and is otherwise guessed; the domain is only returned once its
+// hash matches the token's DOMAIN_SEPARATOR, so a signature is never made for a
+// domain the token would reject.
+func (t *Token) Permit(ctx context.Context, owner, spender common.Address, value, deadline *big.Int) (*eip712.TypedData, error) {
+	separator, err := t.client.callMethod(ctx, t.Address, mustMethod(permitABI, "DOMAIN_SEPARATOR"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPermitNotSupported, err)
+	}
+	nonce, err := t.client.callMethod(ctx, t.Address, mustMethod(permitABI, "nonces"), owner)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPermitNotSupported, err)
+	}
+	name, err := t.call(ctx, "name")
+	if err != nil {
+		return nil, fmt.Errorf("error reading token name: %w", err)
+	}
+	chainID, err := t.client.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []string{"1", "2"}
+	if version, err := t.client.callMethod(ctx, t.Address, mustMethod(permitABI, "version")); err == nil {
+		versions = []string{version[0].(string)}
+	}
+
+	td := &eip712.TypedData{
+		Types:       eip712.Types{"Permit": permitTypes},
+		PrimaryType: "Permit",
+		Message: map[string]interface{}{
+			"owner":    owner,
+			"spender":  spender,
+			"value":    value,
+			"nonce":    nonce[0].(*big.Int),
+			"deadline": deadline,
+		},
+	}
+	expected := separator[0].([]byte)
+	for _, version := range versions {
+		td.Domain = map[string]interface{}{
+			"name":              name[0].(string),
+			"version":           version,
+			"chainId":           chainID,
+			"verifyingContract": t.Address,
+		}
+		td.Types[eip712.DomainType], _ = eip712.DomainFields(td.Domain)
+
+		hash, err := td.DomainSeparator()
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(hash, expected) {
+			return td, nil
+		}
+	}
+	return nil, fmt.Errorf("the token's DOMAIN_SEPARATOR 0x%x does not match name %q, chain %s and version %v; it may use a non-standard domain",
+		expected, name[0], chainID, versions)
+}
+
+// PermitData encodes permit(owner, spender, value, deadline, v, r, s) calldata
+// from a 65-byte signature
+func PermitData(owner, spender common.Address, value, deadline *big.Int, signature []byte) ([]byte, error) {
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("signature must be 65 bytes, got %d", len(signature))
+	}
+	return mustMethod(permitABI, "permit").Pack(owner, spender, value, deadline, signature[64], signature[:32], signature[32:64])
+}
+
+// Permit2Details is one token allowance of a Permit2 permit
+type Permit2Details struct {
+	Token      common.Address
+	Amount     *big.Int // at most MaxUint160
+	Expiration *big.Int // Unix timestamp when the allowance expires, at most MaxUint48
+}
+
+// Permit2 builds the Permit2 typed data that gives spender the allowances in
+// details until sigDeadline, reading each token's current nonce for owner from
+// the Permit2 contract. One allowance gives a PermitSingle, several a PermitBatch.
+func (c *Client) Permit2(ctx context.Context, permit2, owner, spender common.Address, details []Permit2Details, sigDeadline *big.Int) (*eip712.TypedData, error) {
+	if len(details) == 0 {
+		return nil, fmt.Errorf("no token allowances to permit")
+	}
+	chainID, err := c.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, len(details))
+	for i, d := range details {
+		if d.Amount.Sign() < 0 || d.Amount.Cmp(MaxUint160) > 0 {
+			return nil, fmt.Errorf("amount %s for token %s does not fit uint160", d.Amount, d.Token.Hex())
+		}
+		if d.Expiration.Sign() < 0 || d.Expiration.Cmp(MaxUint48) > 0 {
+			return nil, fmt.Errorf("expiration %s for token %s does not fit uint48", d.Expiration, d.Token.Hex())
+		}
+
+		allowance, err := c.callMethod(ctx, permit2, mustMethod(permit2ABI, "allowance"), owner, d.Token, spender)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the Permit2 nonce for %s: %w", d.Token.Hex(), err)
+		}
+		items[i] = map[string]interface{}{
+			"token":      d.Token,
+			"amount":     d.Amount,
+			"expiration": d.Expiration,
+			"nonce":      allowance[2].(*big.Int),
+		}
+	}
+
+	primaryType := "PermitBatch"
+	var detailsValue interface{} = items
+	if len(details) == 1 {
+		primaryType = "PermitSingle"
+		detailsValue = items[0]
+	}
+
+	td := &eip712.TypedData{
+		Types: eip712.Types{
+			eip712.DomainType: {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"PermitDetails": permit2Types["PermitDetails"],
+			primaryType:     permit2Types[primaryType],
+		},
+		PrimaryType: primaryType,
+		Domain: map[string]interface{}{
+			"name":              "Permit2",
+			"chainId":           chainID,
+			"verifyingContract": permit2,
+		},
+		Message: map[string]interface{}{
+			"details":     detailsValue,
+			"spender":     spender,
+			"sigDeadline": sigDeadline,
+		},
+	}
+	return td, nil
+}
@@ -0,0 +1,170 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// permitDomainSeparator computes an EIP-2612 domain separator independently of the eip712 package
+func permitDomainSeparator(name, version string, chainID uint64, token common.Address) string {
+	typeHash := crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	return hex.EncodeToString(crypto.Keccak256(typeHash,
+		crypto.Keccak256([]byte(name)), crypto.Keccak256([]byte(version)),
+		common.LeftPadBytes(new(big.Int).SetUint64(chainID).Bytes(), 32),
+		common.LeftPadBytes(token.Bytes(), 32)))
+}
+
+// TestTokenPermit tests building, signing and encoding an EIP-2612 permit against mock tokens
+func TestTokenPermit(t *testing.T) {
+	withVersion := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	withoutVersion := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	wrongDomain := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+	noPermit := common.HexToAddress("0x00000000000000000000000000000000000000a4")
+
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		if method == "eth_chainId" {
+			return "0x1", nil
+		}
+		var msg map[string]string
+		json.Unmarshal(params[0], &msg)
+		token := common.HexToAddress(msg["to"])
+
+		switch msg["data"][:10] {
+		case "0x06fdde03": // name()
+			return "0x" + abiString("USD Coin"), nil
+		case "0x54fd4d50": // version()
+			if token == withVersion {
+				return "0x" + abiString("2"), nil
+			}
+		case "0x7ecebe00": // nonces(address)
+			if token != noPermit {
+				return "0x" + abiWord(7), nil
+			}
+		case "0x3644e515": // DOMAIN_SEPARATOR()
+			switch token {
+			case withVersion, withoutVersion:
+				return "0x" + permitDomainSeparator("USD Coin", "2", 1, token), nil
+			case wrongDomain:
+				return "0x" + permitDomainSeparator("USDC", "1", 1, token), nil
+			}
+		}
+		return nil, &mockRPCError{Code: 3, Message: "execution reverted", Data: "0x"}
+	})
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+
+	key, _ := crypto.HexToECDSA(testPrivateKey)
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	spender := common.HexToAddress(vectorTo)
+	value, deadline := big.NewInt(1_000_000), big.NewInt(1_700_000_000)
+
+	for _, token := range []common.Address{withVersion, withoutVersion} {
+		td, err := NewToken(client, token).Permit(ctx, owner, spender, value, deadline)
+		if err != nil {
+			t.Fatalf("Failed to build the permit for %s: %v", token.Hex(), err)
+		}
+		if td.Domain["version"] != "2" || td.Message["nonce"].(*big.Int).Int64() != 7 {
+			t.Fatalf("Unexpected permit for %s: %v %v", token.Hex(), td.Domain, td.Message)
+		}
+		if got := td.EncodeType("Permit"); got != "Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)" {
+			t.Fatalf("Unexpected permit type %s", got)
+		}
+
+		signature, err := SignTypedData(key, td)
+		if err != nil {
+			t.Fatalf("Failed to sign the permit: %v", err)
+		}
+		if err := VerifyTypedData(owner, td, signature); err != nil {
+			t.Fatalf("Failed to verify the permit: %v", err)
+		}
+
+		data, err := PermitData(owner, spender, value, deadline, signature)
+		if err != nil {
+			t.Fatalf("Failed to encode permit: %v", err)
+		}
+		if hex.EncodeToString(data[:4]) != "d505accf" || len(data) != 4+7*32 || data[4+4*32+31] != signature[64] {
+			t.Fatalf("Unexpected permit calldata %x", data)
+		}
+	}
+
+	if _, err := NewToken(client, wrongDomain).Permit(ctx, owner, spender, value, deadline); err == nil || !strings.Contains(err.Error(), "DOMAIN_SEPARATOR") {
+		t.Fatalf("Expected a domain mismatch error, got %v", err)
+	}
+	if _, err := NewToken(client, noPermit).Permit(ctx, owner, spender, value, deadline); !errors.Is(err, ErrPermitNotSupported) {
+		t.Fatalf("Expected ErrPermitNotSupported, got %v", err)
+	}
+}
+
+// TestPermit2 tests PermitSingle and PermitBatch typed data against the Permit2 type hashes
+func TestPermit2(t *testing.T) {
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		if method == "eth_chainId" {
+			return "0x1", nil
+		}
+		var msg map[string]string
+		json.Unmarshal(params[0], &msg)
+		if common.HexToAddress(msg["to"]) != Permit2Address || !strings.HasPrefix(msg["data"], "0x927da105") {
+			return nil, &mockRPCError{Code: 3, Message: "execution reverted", Data: "0x"}
+		}
+		// allowance(user, token, spender) returns (amount, expiration, nonce)
+		return "0x" + abiWord(0) + abiWord(0) + abiWord(3), nil
+	})
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+
+	owner := HexToAddress(testAddress)
+	spender := common.HexToAddress(vectorTo)
+	details := []Permit2Details{
+		{Token: common.HexToAddress("0x00000000000000000000000000000000000000a1"), Amount: MaxUint160, Expiration: big.NewInt(1_800_000_000)},
+		{Token: common.HexToAddress("0x00000000000000000000000000000000000000a2"), Amount: big.NewInt(5), Expiration: big.NewInt(1_800_000_000)},
+	}
+
+	single, err := client.Permit2(ctx, Permit2Address, owner, spender, details[:1], big.NewInt(1_700_000_000))
+	if err != nil {
+		t.Fatalf("Failed to build PermitSingle: %v", err)
+	}
+	batch, err := client.Permit2(ctx, Permit2Address, owner, spender, details, big.NewInt(1_700_000_000))
+	if err != nil {
+		t.Fatalf("Failed to build PermitBatch: %v", err)
+	}
+
+	// The type strings Permit2 hashes in PermitHash.sol
+	const permitDetails = "PermitDetails(address token,uint160 amount,uint48 expiration,uint48 nonce)"
+	if got := single.EncodeType(single.PrimaryType); got != "PermitSingle(PermitDetails details,address spender,uint256 sigDeadline)"+permitDetails {
+		t.Fatalf("Unexpected PermitSingle type %s", got)
+	}
+	if got := batch.EncodeType(batch.PrimaryType); got != "PermitBatch(PermitDetails[] details,address spender,uint256 sigDeadline)"+permitDetails {
+		t.Fatalf("Unexpected PermitBatch type %s", got)
+	}
+
+	// Permit2's domain has no version
+	separator, err := single.DomainSeparator()
+	if err != nil {
+		t.Fatalf("Failed to hash the domain: %v", err)
+	}
+	want := crypto.Keccak256(crypto.Keccak256([]byte("EIP712Domain(string name,uint256 chainId,address verifyingContract)")),
+		crypto.Keccak256([]byte("Permit2")), common.LeftPadBytes([]byte{1}, 32), common.LeftPadBytes(Permit2Address.Bytes(), 32))
+	if hex.EncodeToString(separator) != hex.EncodeToString(want) {
+		t.Fatalf("Unexpected Permit2 domain separator %x", separator)
+	}
+
+	if nonce := single.Message["details"].(map[string]interface{})["nonce"].(*big.Int); nonce.Int64() != 3 {
+		t.Fatalf("Unexpected nonce %s", nonce)
+	}
+	if _, err := batch.Hash(); err != nil {
+		t.Fatalf("Failed to hash PermitBatch: %v", err)
+	}
+
+	tooMuch := []Permit2Details{{Token: details[0].Token, Amount: new(big.Int).Add(MaxUint160, big.NewInt(1)), Expiration: big.NewInt(0)}}
+	if _, err := client.Permit2(ctx, Permit2Address, owner, spender, tooMuch, big.NewInt(0)); err == nil {
+		t.Fatalf("Expected an error for an amount above uint160")
+	}
+}
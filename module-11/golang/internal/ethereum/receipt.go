@@ -0,0 +1,457 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TxStatus describes the outcome of a broadcast transaction
+type TxStatus string
+
+const (
+	// TxStatusSuccess means the transaction was mined and executed successfully
+	TxStatusSuccess TxStatus = "success"
+	// TxStatusReverted means the transaction was mined but execution reverted
+	TxStatusReverted TxStatus = "reverted"
+	// TxStatusPending means the transaction is still waiting in the mempool
+	TxStatusPending TxStatus = "pending"
+	// TxStatusReplaced means another transaction with the same nonce was mined instead
+	TxStatusReplaced TxStatus = "replaced"
+	// TxStatusDropped means the node no longer knows the transaction and its nonce is unused
+	TxStatusDropped TxStatus = "dropped"
+	// TxStatusNotFound means the node never returned the transaction and its sender
+	// was not given, so whether it is still propagating or was dropped is unknown
+	TxStatusNotFound TxStatus = "not found"
+)
+
+// Receipt holds the fields of a transaction receipt we care about
+type Receipt struct {
+	TxHash            string
+	BlockHash         string
+	BlockNumber       uint64
+	From              common.Address
+	To                *common.Address
+	ContractAddress   *common.Address
+	Status            uint64 // 1 = success, 0 = reverted
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+	Type              uint64
+}
+
+// Fee returns the actual fee paid (gasUsed * effectiveGasPrice)
+func (r *Receipt) Fee() *big.Int {
+	if r.EffectiveGasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(r.GasUsed), r.EffectiveGasPrice)
+}
+
+// RPCTransaction is a transaction as returned by eth_getTransactionByHash
+type RPCTransaction struct {
+	Hash                 string
+	From                 common.Address
+	To                   *common.Address
+	Nonce                uint64
+	Value                *big.Int
+	Gas                  uint64
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	Input                []byte
+	AccessList           AccessList
+	Type                 uint64
+	BlockNumber          *uint64 // nil while pending
+}
+
+// IsPending reports whether the transaction has not been included in a block yet
+func (t *RPCTransaction) IsPending() bool {
+	return t.BlockNumber == nil
+}
+
+// rpcReceipt is the raw JSON form of a receipt
+type rpcReceipt struct {
+	TransactionHash   string  `json:"transactionHash"`
+	BlockHash         string  `json:"blockHash"`
+	BlockNumber       string  `json:"blockNumber"`
+	From              string  `json:"from"`
+	To                *string `json:"to"`
+	ContractAddress   *string `json:"contractAddress"`
+	Status            string  `json:"status"`
+	GasUsed           string  `json:"gasUsed"`
+	EffectiveGasPrice string  `json:"effectiveGasPrice"`
+	Type              string  `json:"type"`
+}
+
+// rpcTransaction is the raw JSON form of a transaction
+type rpcTransaction struct {
+	Hash                 string     `json:"hash"`
+	From                 string     `json:"from"`
+	To                   *string    `json:"to"`
+	Nonce                string     `json:"nonce"`
+	Value                string     `json:"value"`
+	Gas                  string     `json:"gas"`
+	GasPrice             string     `json:"gasPrice"`
+	MaxFeePerGas         string     `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string     `json:"maxPriorityFeePerGas"`
+	Input                string     `json:"input"`
+	AccessList           AccessList `json:"accessList"`
+	Type                 string     `json:"type"`
+	BlockNumber          *string    `json:"blockNumber"`
+}
+
+// GetTransactionReceipt gets the receipt of a mined transaction.
+// It returns nil without error if the transaction has not been mined yet.
+func (c *Client) GetTransactionReceipt(ctx context.Context, txHash string) (*Receipt, error) {
+	result, err := c.Call(ctx, "eth_getTransactionReceipt", []interface{}{txHash})
+	if err != nil {
+		return nil, fmt.Errorf("error getting receipt: %w", err)
+	}
+
+	return parseReceipt(result)
+}
+
+// GetTransactionByHash gets a transaction by its hash.
+// It returns nil without error if the node does not know the transaction.
+func (c *Client) GetTransactionByHash(ctx context.Context, txHash string) (*RPCTransaction, error) {
+	result, err := c.Call(ctx, "eth_getTransactionByHash", []interface{}{txHash})
+	if err != nil {
+		return nil, fmt.Errorf("error getting transaction: %w", err)
+	}
+
+	return parseTransaction(result)
+}
+
+// GetBlockNumber gets the number of the most recent block
+func (c *Client) GetBlockNumber(ctx context.Context) (uint64, error) {
+	result, err := c.Call(ctx, "eth_blockNumber", []interface{}{})
+	if err != nil {
+		return 0, fmt.Errorf("error getting block number: %w", err)
+	}
+
+	return hexToUint64(string(result))
+}
+
+// GetConfirmedNonce gets the nonce of an address counting only mined transactions
+func (c *Client) GetConfirmedNonce(ctx context.Context, address common.Address) (uint64, error) {
+	result, err := c.Call(ctx, "eth_getTransactionCount", []interface{}{address.Hex(), "latest"})
+	if err != nil {
+		return 0, fmt.Errorf("error getting nonce: %w", err)
+	}
+
+	return hexToUint64(string(result))
+}
+
+// WaitOptions configures WaitForTransaction
+type WaitOptions struct {
+	// Confirmations is the number of blocks (including the inclusion block) to wait for
+	Confirmations uint64
+	// Timeout bounds the total wait; zero means wait until ctx is done
+	Timeout time.Duration
+	// PollInterval is the initial delay between polls; it doubles up to MaxPollInterval
+	PollInterval time.Duration
+	// MaxPollInterval caps the backoff between polls
+	MaxPollInterval time.Duration
+	// DropAfter is the number of consecutive polls the transaction must be unknown
+	// to the node (with its nonce unused) before it is reported as dropped
+	DropAfter int
+	// From and Nonce identify the transaction before the node has returned it, so
+	// its nonce can be checked even if it is never seen. Without From, a transaction
+	// that is never seen is reported as not found rather than dropped.
+	From  *common.Address
+	Nonce uint64
+	// OnPoll, if set, is called after every poll with the current status
+	OnPoll func(status TxStatus, confirmations uint64)
+}
+
+// TxResult is the outcome of waiting for a transaction
+type TxResult struct {
+	Status        TxStatus
+	Receipt       *Receipt        // set for success and reverted
+	Transaction   *RPCTransaction // last seen version of the transaction, if any
+	Confirmations uint64
+}
+
+// DefaultWaitOptions returns the wait options used by the CLI
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		Confirmations:   1,
+		Timeout:         2 * time.Minute,
+		PollInterval:    time.Second,
+		MaxPollInterval: 15 * time.Second,
+		DropAfter:       5,
+	}
+}
+
+// withDefaults fills in unset poll settings from DefaultWaitOptions
+func (opts WaitOptions) withDefaults() WaitOptions {
+	defaults := DefaultWaitOptions()
+	if opts.Confirmations == 0 {
+		opts.Confirmations = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+	if opts.MaxPollInterval < opts.PollInterval {
+		opts.MaxPollInterval = opts.PollInterval
+	}
+	if opts.DropAfter <= 0 {
+		opts.DropAfter = defaults.DropAfter
+	}
+	return opts
+}
+
+// WaitForTransaction polls the node until the transaction is mined with the requested
+// number of confirmations, is replaced or dropped, or the timeout expires. On timeout
+// the last known status (usually pending) is returned together with context.DeadlineExceeded.
+func (c *Client) WaitForTransaction(ctx context.Context, txHash string, opts WaitOptions) (*TxResult, error) {
+	opts = opts.withDefaults()
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	result := &TxResult{Status: TxStatusPending}
+	interval := opts.PollInterval
+	notFound := 0
+
+	for {
+		done, err := c.pollTransaction(ctx, txHash, opts, result, &notFound)
+		if err != nil && ctx.Err() == nil {
+			return result, err
+		}
+		if done {
+			return result, nil
+		}
+
+		if opts.OnPoll != nil {
+			opts.OnPoll(result.Status, result.Confirmations)
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		// Exponential backoff between polls
+		interval *= 2
+		if interval > opts.MaxPollInterval {
+			interval = opts.MaxPollInterval
+		}
+	}
+}
+
+// pollTransaction performs a single poll and updates result. It reports whether
+// a final outcome has been reached.
+func (c *Client) pollTransaction(ctx context.Context, txHash string, opts WaitOptions, result *TxResult, notFound *int) (bool, error) {
+	receipt, err := c.GetTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return false, err
+	}
+
+	if receipt != nil {
+		*notFound = 0
+		result.Receipt = receipt
+		if receipt.Status == 1 {
+			result.Status = TxStatusSuccess
+		} else {
+			result.Status = TxStatusReverted
+		}
+
+		head, err := c.GetBlockNumber(ctx)
+		if err != nil {
+			return false, err
+		}
+		result.Confirmations = 0
+		if head >= receipt.BlockNumber {
+			result.Confirmations = head - receipt.BlockNumber + 1
+		}
+
+		return result.Confirmations >= opts.Confirmations, nil
+	}
+
+	// Not mined (or reorged out); fall back to the pending view
+	result.Receipt = nil
+	result.Confirmations = 0
+	result.Status = TxStatusPending
+
+	tx, err := c.GetTransactionByHash(ctx, txHash)
+	if err != nil {
+		return false, err
+	}
+
+	if tx != nil {
+		*notFound = 0
+		result.Transaction = tx
+		return false, nil
+	}
+
+	// The node doesn't know the transaction. If we know its sender, from an earlier
+	// poll or the options, check whether its nonce has been consumed by another transaction.
+	*notFound++
+	from, nonce := opts.From, opts.Nonce
+	if result.Transaction != nil {
+		from, nonce = &result.Transaction.From, result.Transaction.Nonce
+	}
+	if from != nil {
+		confirmed, err := c.GetConfirmedNonce(ctx, *from)
+		if err != nil {
+			return false, err
+		}
+		if confirmed > nonce {
+			result.Status = TxStatusReplaced
+			return true, nil
+		}
+	}
+
+	if *notFound >= opts.DropAfter {
+		result.Status = TxStatusDropped
+		if from == nil {
+			result.Status = TxStatusNotFound
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// parseReceipt converts a raw receipt result into a Receipt (nil for a null result)
+func parseReceipt(raw json.RawMessage) (*Receipt, error) {
+	if isNullResult(raw) {
+		return nil, nil
+	}
+
+	var r rpcReceipt
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal receipt: %w", err)
+	}
+
+	receipt := &Receipt{
+		TxHash:    r.TransactionHash,
+		BlockHash: r.BlockHash,
+		From:      common.HexToAddress(r.From),
+	}
+
+	var err error
+	if receipt.BlockNumber, err = hexToUint64(r.BlockNumber); err != nil {
+		return nil, fmt.Errorf("invalid receipt block number: %w", err)
+	}
+	if receipt.Status, err = hexToUint64(r.Status); err != nil {
+		return nil, fmt.Errorf("invalid receipt status: %w", err)
+	}
+	if receipt.GasUsed, err = hexToUint64(r.GasUsed); err != nil {
+		return nil, fmt.Errorf("invalid receipt gas used: %w", err)
+	}
+	if r.EffectiveGasPrice != "" {
+		if receipt.EffectiveGasPrice, err = HexToBig(r.EffectiveGasPrice); err != nil {
+			return nil, fmt.Errorf("invalid receipt effective gas price: %w", err)
+		}
+	}
+	if r.Type != "" {
+		if receipt.Type, err = hexToUint64(r.Type); err != nil {
+			return nil, fmt.Errorf("invalid receipt type: %w", err)
+		}
+	}
+	if r.To != nil {
+		to := common.HexToAddress(*r.To)
+		receipt.To = &to
+	}
+	if r.ContractAddress != nil {
+		addr := common.HexToAddress(*r.ContractAddress)
+		receipt.ContractAddress = &addr
+	}
+
+	return receipt, nil
+}
+
+// parseTransaction converts a raw transaction result into an RPCTransaction (nil for a null result)
+func parseTransaction(raw json.RawMessage) (*RPCTransaction, error) {
+	if isNullResult(raw) {
+		return nil, nil
+	}
+
+	var t rpcTransaction
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction: %w", err)
+	}
+
+	tx := &RPCTransaction{
+		Hash:       t.Hash,
+		From:       common.HexToAddress(t.From),
+		AccessList: t.AccessList,
+	}
+
+	var err error
+	if tx.Nonce, err = hexToUint64(t.Nonce); err != nil {
+		return nil, fmt.Errorf("invalid transaction nonce: %w", err)
+	}
+	if tx.Gas, err = hexToUint64(t.Gas); err != nil {
+		return nil, fmt.Errorf("invalid transaction gas: %w", err)
+	}
+	if tx.Value, err = HexToBig(t.Value); err != nil {
+		return nil, fmt.Errorf("invalid transaction value: %w", err)
+	}
+	if t.GasPrice != "" {
+		if tx.GasPrice, err = HexToBig(t.GasPrice); err != nil {
+			return nil, fmt.Errorf("invalid transaction gas price: %w", err)
+		}
+	}
+	if t.MaxFeePerGas != "" {
+		if tx.MaxFeePerGas, err = HexToBig(t.MaxFeePerGas); err != nil {
+			return nil, fmt.Errorf("invalid transaction max fee: %w", err)
+		}
+	}
+	if t.MaxPriorityFeePerGas != "" {
+		if tx.MaxPriorityFeePerGas, err = HexToBig(t.MaxPriorityFeePerGas); err != nil {
+			return nil, fmt.Errorf("invalid transaction priority fee: %w", err)
+		}
+	}
+	if t.Type != "" {
+		if tx.Type, err = hexToUint64(t.Type); err != nil {
+			return nil, fmt.Errorf("invalid transaction type: %w", err)
+		}
+	}
+	if t.Input != "" && t.Input != "0x" {
+		if tx.Input, err = HexDecode(t.Input); err != nil {
+			return nil, fmt.Errorf("invalid transaction input: %w", err)
+		}
+	}
+	if t.To != nil {
+		to := common.HexToAddress(*t.To)
+		tx.To = &to
+	}
+	if t.BlockNumber != nil {
+		blockNumber, err := hexToUint64(*t.BlockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction block number: %w", err)
+		}
+		tx.BlockNumber = &blockNumber
+	}
+
+	return tx, nil
+}
+
+// isNullResult reports whether an RPC result is JSON null or empty
+func isNullResult(raw json.RawMessage) bool {
+	s := strings.TrimSpace(string(raw))
+	return s == "" || s == "null"
+}
+
+// hexToUint64 parses a (possibly quoted) 0x-prefixed hex quantity
+func hexToUint64(s string) (uint64, error) {
+	s = strings.Trim(s, "\"")
+	if !strings.HasPrefix(s, "0x") {
+		return 0, errors.New("hex string must start with 0x")
+	}
+	return strconv.ParseUint(s[2:], 16, 64)
+}
@@ -0,0 +1,231 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testTxHash = "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+
+// fastWaitOptions returns wait options suitable for unit tests
+func fastWaitOptions() WaitOptions {
+	return WaitOptions{
+		Confirmations:   1,
+		Timeout:         5 * time.Second,
+		PollInterval:    time.Millisecond,
+		MaxPollInterval: 2 * time.Millisecond,
+		DropAfter:       3,
+	}
+}
+
+// mockReceipt builds a receipt JSON object
+func mockReceipt(status string, block string) map[string]interface{} {
+	return map[string]interface{}{
+		"transactionHash":   testTxHash,
+		"blockHash":         "0x8243343df08b9751f5ca0c5f8c9c0460d8a9b6351066fae0acbd4d3e776de8bb",
+		"blockNumber":       block,
+		"from":              testAddress,
+		"to":                testAddress,
+		"contractAddress":   nil,
+		"status":            status,
+		"gasUsed":           "0x5208",     // 21000
+		"effectiveGasPrice": "0x3b9aca00", // 1 gwei
+		"type":              "0x2",
+	}
+}
+
+// mockPendingTx builds a pending transaction JSON object
+func mockPendingTx(nonce string) map[string]interface{} {
+	return map[string]interface{}{
+		"hash":                 testTxHash,
+		"from":                 testAddress,
+		"to":                   testAddress,
+		"nonce":                nonce,
+		"value":                "0x1",
+		"gas":                  "0x5208",
+		"maxFeePerGas":         "0x77359400",
+		"maxPriorityFeePerGas": "0x3b9aca00",
+		"input":                "0x",
+		"type":                 "0x2",
+		"blockNumber":          nil,
+	}
+}
+
+// TestWaitForTransactionConfirmations tests waiting for a mined transaction with confirmations
+func TestWaitForTransactionConfirmations(t *testing.T) {
+	var head atomic.Uint64
+	head.Store(0x63) // one block before inclusion
+	var receiptPolls atomic.Int32
+
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_getTransactionReceipt":
+			// Pending for the first poll, then mined in block 0x64
+			if receiptPolls.Add(1) == 1 {
+				return nil, nil
+			}
+			return mockReceipt("0x1", "0x64"), nil
+		case "eth_getTransactionByHash":
+			return mockPendingTx("0x5"), nil
+		case "eth_blockNumber":
+			return hexUint(head.Add(1)), nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	opts := fastWaitOptions()
+	opts.Confirmations = 3
+
+	result, err := NewClient(rpc.URL).WaitForTransaction(context.Background(), testTxHash, opts)
+	if err != nil {
+		t.Fatalf("Failed to wait for transaction: %v", err)
+	}
+
+	if result.Status != TxStatusSuccess {
+		t.Fatalf("Status %s, expected %s", result.Status, TxStatusSuccess)
+	}
+	if result.Confirmations < 3 {
+		t.Fatalf("Confirmations %d, expected at least 3", result.Confirmations)
+	}
+	if result.Receipt.BlockNumber != 0x64 || result.Receipt.GasUsed != 21000 {
+		t.Fatalf("Unexpected receipt: %+v", result.Receipt)
+	}
+
+	// 21000 gas * 1 gwei
+	if fee := result.Receipt.Fee(); fee.String() != "21000000000000" {
+		t.Fatalf("Fee %s, expected 21000000000000", fee)
+	}
+}
+
+// TestWaitForTransactionReverted tests that a failed receipt is reported as reverted
+func TestWaitForTransactionReverted(t *testing.T) {
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_getTransactionReceipt":
+			return mockReceipt("0x0", "0x10"), nil
+		case "eth_blockNumber":
+			return "0x10", nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	result, err := NewClient(rpc.URL).WaitForTransaction(context.Background(), testTxHash, fastWaitOptions())
+	if err != nil {
+		t.Fatalf("Failed to wait for transaction: %v", err)
+	}
+	if result.Status != TxStatusReverted {
+		t.Fatalf("Status %s, expected %s", result.Status, TxStatusReverted)
+	}
+}
+
+// TestWaitForTransactionReplaced tests detecting a transaction whose nonce was used by another one
+func TestWaitForTransactionReplaced(t *testing.T) {
+	var txPolls atomic.Int32
+
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_getTransactionReceipt":
+			return nil, nil
+		case "eth_getTransactionByHash":
+			// Seen once in the mempool, then gone
+			if txPolls.Add(1) == 1 {
+				return mockPendingTx("0x5"), nil
+			}
+			return nil, nil
+		case "eth_getTransactionCount":
+			return "0x6", nil // nonce 5 has been mined
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	result, err := NewClient(rpc.URL).WaitForTransaction(context.Background(), testTxHash, fastWaitOptions())
+	if err != nil {
+		t.Fatalf("Failed to wait for transaction: %v", err)
+	}
+	if result.Status != TxStatusReplaced {
+		t.Fatalf("Status %s, expected %s", result.Status, TxStatusReplaced)
+	}
+	if result.Transaction == nil || result.Transaction.Nonce != 5 {
+		t.Fatalf("Expected last seen transaction with nonce 5, got %+v", result.Transaction)
+	}
+}
+
+// TestWaitForTransactionDropped tests detecting a transaction the node has forgotten
+func TestWaitForTransactionDropped(t *testing.T) {
+	confirmedNonce := "0x5"
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_getTransactionReceipt", "eth_getTransactionByHash":
+			return nil, nil
+		case "eth_getTransactionCount":
+			return confirmedNonce, nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	client := NewClient(rpc.URL)
+	sender := HexToAddress(testAddress)
+
+	// Never seen and the sender is unknown: not found rather than dropped
+	result, err := client.WaitForTransaction(context.Background(), testTxHash, fastWaitOptions())
+	if err != nil {
+		t.Fatalf("Failed to wait for transaction: %v", err)
+	}
+	if result.Status != TxStatusNotFound {
+		t.Fatalf("Status %s, expected %s", result.Status, TxStatusNotFound)
+	}
+	if n := rpc.callCount("eth_getTransactionByHash"); n != 3 {
+		t.Fatalf("Expected 3 lookups before giving up, got %d", n)
+	}
+
+	// Never seen, with the sender's nonce unused: dropped
+	opts := fastWaitOptions()
+	opts.From, opts.Nonce = &sender, 5
+	result, err = client.WaitForTransaction(context.Background(), testTxHash, opts)
+	if err != nil {
+		t.Fatalf("Failed to wait for transaction: %v", err)
+	}
+	if result.Status != TxStatusDropped {
+		t.Fatalf("Status %s, expected %s", result.Status, TxStatusDropped)
+	}
+
+	// Never seen, with the sender's nonce used by another transaction: replaced
+	confirmedNonce = "0x6"
+	result, err = client.WaitForTransaction(context.Background(), testTxHash, opts)
+	if err != nil {
+		t.Fatalf("Failed to wait for transaction: %v", err)
+	}
+	if result.Status != TxStatusReplaced {
+		t.Fatalf("Status %s, expected %s", result.Status, TxStatusReplaced)
+	}
+}
+
+// TestWaitForTransactionTimeout tests that a transaction stuck in the mempool times out as pending
+func TestWaitForTransactionTimeout(t *testing.T) {
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_getTransactionReceipt":
+			return nil, nil
+		case "eth_getTransactionByHash":
+			return mockPendingTx("0x5"), nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	opts := fastWaitOptions()
+	opts.Timeout = 50 * time.Millisecond
+
+	result, err := NewClient(rpc.URL).WaitForTransaction(context.Background(), testTxHash, opts)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	if result.Status != TxStatusPending {
+		t.Fatalf("Status %s, expected %s", result.Status, TxStatusPending)
+	}
+	if result.Transaction == nil || !result.Transaction.IsPending() {
+		t.Fatal("Expected the pending transaction to be reported")
+	}
+}
@@ -0,0 +1,283 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ReplacementBump is the minimum fee increase, in percent, that nodes require
+// before a transaction may replace a pending one with the same nonce
+const ReplacementBump = 10
+
+// cancelGasLimit is the gas limit of a cancelling 0-value self-transfer
+const cancelGasLimit = 21000
+
+var (
+	// ErrNotPending is returned when replacing a transaction that is already mined
+	ErrNotPending = errors.New("transaction is not pending")
+	// ErrReplacementUnderpriced is returned when explicit fees are below the replacement minimum
+	ErrReplacementUnderpriced = errors.New("replacement fee too low")
+)
+
+// Replacement is a transaction sent to speed up or cancel a pending one
+type Replacement struct {
+	Original        *RPCTransaction
+	Tx              *UnsignedTx
+	Hash            string
+	Cancel          bool
+	MinPriorityFee  *big.Int // lowest priority fee the node accepts as a replacement; nil for legacy
+	MinMaxFeePerGas *big.Int // lowest max fee, or gas price of a legacy replacement, the node accepts
+}
+
+// BumpFee returns fee raised by percent, rounded up
+func BumpFee(fee *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// ReplaceTransaction re-sends a pending transaction of keyPair's account as an
+// EIP-1559 transaction with the same nonce and higher fees. A speed-up keeps the
+// recipient, value, data and gas limit; a cancel sends 0 to the sender itself.
+//
+// Both fees are raised by at least ReplacementBump percent over the original.
+// Fees that are not given (priorityFeeWei, WithMaxFeePerGas) are the higher of
+// that minimum and the WithFeeSpeed preset of SuggestFees; explicit fees below the
+// minimum fail with ErrReplacementUnderpriced. WithFeeCap is enforced.
+func (c *Client) ReplaceTransaction(ctx context.Context, fromKeyPair *KeyPair, txHash string, cancel bool, priorityFeeWei *big.Int, opts ...SendOption) (*Replacement, error) {
+	o := applySendOptions(opts)
+
+	r, tx, err := c.prepareReplacement(ctx, fromKeyPair, txHash, cancel)
+	if err != nil {
+		return nil, err
+	}
+	if tx.MaxPriorityFeePerGas, tx.MaxFeePerGas, err = c.replacementFees(ctx, r, priorityFeeWei, o); err != nil {
+		return nil, err
+	}
+	tx.Type = TxTypeDynamicFee
+	return c.sendReplacement(ctx, fromKeyPair, r, tx)
+}
+
+// ReplaceLegacyTransaction re-sends a pending transaction of keyPair's account as
+// a legacy transaction with the same nonce and a higher gas price, for networks
+// without EIP-1559. A speed-up keeps the recipient, value, data, gas limit and
+// access list (sending an EIP-2930 transaction when there is one); a cancel sends
+// 0 to the sender itself.
+//
+// The gas price is raised by at least ReplacementBump percent over the original.
+// Without WithGasPrice it is the higher of that minimum and eth_gasPrice; an
+// explicit gas price below the minimum fails with ErrReplacementUnderpriced.
+// WithFeeCap is enforced.
+func (c *Client) ReplaceLegacyTransaction(ctx context.Context, fromKeyPair *KeyPair, txHash string, cancel bool, opts ...SendOption) (*Replacement, error) {
+	o := applySendOptions(opts)
+
+	r, tx, err := c.prepareReplacement(ctx, fromKeyPair, txHash, cancel)
+	if err != nil {
+		return nil, err
+	}
+	if r.Original.GasPrice == nil {
+		return nil, fmt.Errorf("transaction %s has no gas price", txHash)
+	}
+	r.MinMaxFeePerGas = BumpFee(r.Original.GasPrice, ReplacementBump)
+
+	gasPrice := o.gasPrice
+	if gasPrice == nil {
+		if gasPrice, err = c.GetGasPrice(ctx); err != nil {
+			return nil, fmt.Errorf("error getting gas price: %w", err)
+		}
+		gasPrice = maxBig(gasPrice, r.MinMaxFeePerGas)
+	}
+	if gasPrice.Cmp(r.MinMaxFeePerGas) < 0 {
+		return nil, fmt.Errorf("%w: need a gas price of at least %s wei (%d%% above the original)",
+			ErrReplacementUnderpriced, r.MinMaxFeePerGas, ReplacementBump)
+	}
+	if err := checkFeeCap(gasPrice, o.feeCap); err != nil {
+		return nil, err
+	}
+
+	tx.GasPrice, tx.Type = gasPrice, TxTypeLegacy
+	if tx.AccessList != nil {
+		tx.Type = TxTypeAccessList
+	}
+	return c.sendReplacement(ctx, fromKeyPair, r, tx)
+}
+
+// prepareReplacement checks that txHash is a pending transaction of fromKeyPair's
+// account and returns the replacement without its fees and type
+func (c *Client) prepareReplacement(ctx context.Context, fromKeyPair *KeyPair, txHash string, cancel bool) (*Replacement, *UnsignedTx, error) {
+	original, err := c.GetTransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if original == nil {
+		return nil, nil, fmt.Errorf("transaction %s not found", txHash)
+	}
+	if !original.IsPending() {
+		return nil, nil, fmt.Errorf("%w: %s was mined in block %d", ErrNotPending, txHash, *original.BlockNumber)
+	}
+	if original.From != fromKeyPair.Address {
+		return nil, nil, fmt.Errorf("transaction %s was sent by %s, not %s", txHash, original.From.Hex(), fromKeyPair.Address.Hex())
+	}
+
+	chainID, err := c.GetChainID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.checkChainID(chainID.Uint64()); err != nil {
+		return nil, nil, err
+	}
+
+	tx := &UnsignedTx{
+		ChainID: chainID,
+		From:    fromKeyPair.Address,
+		Nonce:   original.Nonce,
+	}
+	if cancel {
+		to := fromKeyPair.Address
+		tx.To, tx.Value, tx.GasLimit = &to, big.NewInt(0), cancelGasLimit
+	} else {
+		tx.To, tx.Value, tx.GasLimit, tx.Data, tx.AccessList = original.To, original.Value, original.Gas, original.Input, original.AccessList
+	}
+	return &Replacement{Original: original, Cancel: cancel}, tx, nil
+}
+
+// sendReplacement signs and broadcasts the replacement tx
+func (c *Client) sendReplacement(ctx context.Context, fromKeyPair *KeyPair, r *Replacement, tx *UnsignedTx) (*Replacement, error) {
+	rawTx, err := tx.Sign(fromKeyPair.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error signing transaction: %w", err)
+	}
+	if r.Hash, err = c.SendRawTransaction(ctx, rawTx); err != nil {
+		return nil, fmt.Errorf("error sending replacement: %w", err)
+	}
+
+	r.Tx = tx
+	return r, nil
+}
+
+// replacementFees returns the fees of a replacement and records the minimums in r
+func (c *Client) replacementFees(ctx context.Context, r *Replacement, priorityFee *big.Int, o *sendOptions) (*big.Int, *big.Int, error) {
+	// Legacy and EIP-2930 transactions pay their gas price as both fees
+	original := r.Original
+	oldTip, oldMaxFee := original.MaxPriorityFeePerGas, original.MaxFeePerGas
+	if oldTip == nil || oldMaxFee == nil {
+		if original.GasPrice == nil {
+			return nil, nil, fmt.Errorf("transaction %s has no fees", original.Hash)
+		}
+		oldTip, oldMaxFee = original.GasPrice, original.GasPrice
+	}
+	r.MinPriorityFee = BumpFee(oldTip, ReplacementBump)
+	r.MinMaxFeePerGas = BumpFee(oldMaxFee, ReplacementBump)
+
+	tip, maxFee := priorityFee, o.maxFeePerGas
+	if tip == nil || maxFee == nil {
+		suggestion, err := c.SuggestFees(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error estimating fees: %w", err)
+		}
+		if tip == nil {
+			tip = maxBig(suggestion.Preset(o.feeSpeed).MaxPriorityFeePerGas, r.MinPriorityFee)
+		}
+		if maxFee == nil {
+			maxFee = maxBig(suggestion.MaxFee(o.feeSpeed, tip), r.MinMaxFeePerGas)
+		}
+	}
+
+	if tip.Cmp(r.MinPriorityFee) < 0 || maxFee.Cmp(r.MinMaxFeePerGas) < 0 {
+		return nil, nil, fmt.Errorf("%w: need at least %s wei priority fee and %s wei max fee (%d%% above the original)",
+			ErrReplacementUnderpriced, r.MinPriorityFee, r.MinMaxFeePerGas, ReplacementBump)
+	}
+	if maxFee.Cmp(tip) < 0 {
+		return nil, nil, fmt.Errorf("max fee %s wei is below the priority fee %s wei", maxFee, tip)
+	}
+	if err := checkFeeCap(maxFee, o.feeCap); err != nil {
+		return nil, nil, err
+	}
+	return tip, maxFee, nil
+}
+
+// maxBig returns the larger of a and b
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// WaitForReplacement waits until one of several transactions that share the
+// sender's nonce (an original and its replacements) is mined, and returns the
+// winning hash with its result once it has the requested confirmations. When the
+// nonce is used by a transaction outside hashes, the status is TxStatusReplaced
+// and the hash is empty. On timeout the status is pending.
+func (c *Client) WaitForReplacement(ctx context.Context, from common.Address, nonce uint64, hashes []string, opts WaitOptions) (string, *TxResult, error) {
+	opts = opts.withDefaults()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	pending := &TxResult{Status: TxStatusPending}
+	interval := opts.PollInterval
+
+	for {
+		winner, nonceUsed, err := c.pollReplacement(ctx, from, nonce, hashes)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", pending, ctx.Err()
+			}
+			return "", pending, err
+		}
+		if winner != "" {
+			// Wait for the remaining confirmations under the same deadline
+			opts.Timeout = 0
+			opts.From, opts.Nonce = &from, nonce
+			result, err := c.WaitForTransaction(ctx, winner, opts)
+			return winner, result, err
+		}
+		if nonceUsed {
+			return "", &TxResult{Status: TxStatusReplaced}, nil
+		}
+
+		if opts.OnPoll != nil {
+			opts.OnPoll(TxStatusPending, 0)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", pending, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > opts.MaxPollInterval {
+			interval = opts.MaxPollInterval
+		}
+	}
+}
+
+// pollReplacement returns the first of hashes that has a receipt, and whether the
+// nonce was already used. The nonce is read first, so a used nonce without a
+// receipt among hashes means another transaction took it.
+func (c *Client) pollReplacement(ctx context.Context, from common.Address, nonce uint64, hashes []string) (string, bool, error) {
+	confirmed, err := c.GetConfirmedNonce(ctx, from)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, hash := range hashes {
+		receipt, err := c.GetTransactionReceipt(ctx, hash)
+		if err != nil {
+			return "", false, err
+		}
+		if receipt != nil {
+			return hash, true, nil
+		}
+	}
+	return "", confirmed > nonce, nil
+}
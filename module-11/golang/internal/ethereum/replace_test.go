@@ -0,0 +1,244 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TestBumpFee tests that bumped fees are rounded up
+func TestBumpFee(t *testing.T) {
+	cases := map[int64]int64{
+		1_000_000_000: 1_100_000_000,
+		1_000_000_001: 1_100_000_002,
+		1:             2,
+		0:             0,
+	}
+	for fee, want := range cases {
+		if got := BumpFee(big.NewInt(fee), ReplacementBump); got.Int64() != want {
+			t.Fatalf("BumpFee(%d) = %s, expected %d", fee, got, want)
+		}
+	}
+}
+
+// TestReplaceTransaction tests speed-up and cancel replacements of a pending transaction
+func TestReplaceTransaction(t *testing.T) {
+	original := mockPendingTx("0x5")
+	original["to"] = vectorTo
+	original["input"] = "0xa9059cbb"
+	original["gas"] = "0x186a0"
+
+	var rawTxs [][]byte
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_getTransactionByHash":
+			return original, nil
+		case "eth_chainId":
+			return "0xaa36a7", nil
+		case "eth_feeHistory":
+			return mockFeeHistory(), nil
+		case "eth_sendRawTransaction":
+			var rawHex string
+			json.Unmarshal(params[0], &rawHex)
+			raw, _ := HexDecode(rawHex)
+			rawTxs = append(rawTxs, raw)
+			return fmt.Sprintf("0x%x", Keccak256(raw)), nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+
+	// A speed-up keeps the call and uses the oracle fees, which are above the 10% minimum
+	r, err := client.ReplaceTransaction(ctx, keyPair, testTxHash, false, nil)
+	if err != nil {
+		t.Fatalf("Failed to speed up: %v", err)
+	}
+	if r.MinPriorityFee.Int64() != 1_100_000_000 || r.MinMaxFeePerGas.Int64() != 2_200_000_000 {
+		t.Fatalf("Unexpected minimum fees %s/%s", r.MinPriorityFee, r.MinMaxFeePerGas)
+	}
+	if r.Tx.Nonce != 5 || r.Tx.GasLimit != 100000 || *r.Tx.To != HexToAddress(vectorTo) || len(r.Tx.Data) != 4 {
+		t.Fatalf("Speed-up changed the transaction: %+v", r.Tx)
+	}
+	if tip, maxFee := decodeFees(t, rawTxs[0]); tip != 1_500_000_000 || maxFee != 3_500_000_000 {
+		t.Fatalf("Unexpected speed-up fees %d/%d", tip, maxFee)
+	}
+	if r.Hash != fmt.Sprintf("0x%x", Keccak256(rawTxs[0])) {
+		t.Fatalf("Unexpected replacement hash %s", r.Hash)
+	}
+
+	// A cancel is a 0-value self-transfer with the same nonce; explicit fees only
+	// need to clear the minimum
+	r, err = client.ReplaceTransaction(ctx, keyPair, testTxHash, true, big.NewInt(1_100_000_000), WithMaxFeePerGas(big.NewInt(2_200_000_000)))
+	if err != nil {
+		t.Fatalf("Failed to cancel: %v", err)
+	}
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(rawTxs[1][1:], &fields); err != nil {
+		t.Fatalf("Failed to decode cancel transaction: %v", err)
+	}
+	var nonce, gas uint64
+	var to, data []byte
+	var value big.Int
+	rlp.DecodeBytes(fields[1], &nonce)
+	rlp.DecodeBytes(fields[4], &gas)
+	rlp.DecodeBytes(fields[5], &to)
+	rlp.DecodeBytes(fields[6], &value)
+	rlp.DecodeBytes(fields[7], &data)
+	if nonce != 5 || gas != 21000 || HexToAddress(fmt.Sprintf("0x%x", to)) != keyPair.Address || value.Sign() != 0 || len(data) != 0 {
+		t.Fatalf("Unexpected cancel transaction: nonce=%d gas=%d to=%x value=%s data=%x", nonce, gas, to, &value, data)
+	}
+
+	// Fees below the replacement minimum or above the cap are refused
+	_, err = client.ReplaceTransaction(ctx, keyPair, testTxHash, false, big.NewInt(1_000_000_000))
+	if !errors.Is(err, ErrReplacementUnderpriced) {
+		t.Fatalf("Expected ErrReplacementUnderpriced, got %v", err)
+	}
+	_, err = client.ReplaceTransaction(ctx, keyPair, testTxHash, false, nil, WithFeeCap(big.NewInt(2_000_000_000)))
+	if !errors.Is(err, ErrFeeCapExceeded) {
+		t.Fatalf("Expected ErrFeeCapExceeded, got %v", err)
+	}
+
+	// Only the sender can replace, and only while pending
+	other, _ := GenerateKeyPair()
+	if _, err := client.ReplaceTransaction(ctx, other, testTxHash, true, nil); err == nil {
+		t.Fatalf("Expected an error when replacing another account's transaction")
+	}
+	original["blockNumber"] = "0x10"
+	if _, err := client.ReplaceTransaction(ctx, keyPair, testTxHash, true, nil); !errors.Is(err, ErrNotPending) {
+		t.Fatalf("Expected ErrNotPending, got %v", err)
+	}
+	if len(rawTxs) != 2 {
+		t.Fatalf("Expected two broadcasts, got %d", len(rawTxs))
+	}
+}
+
+// TestReplaceLegacyTransaction tests gas price replacements on a network without EIP-1559
+func TestReplaceLegacyTransaction(t *testing.T) {
+	original := mockPendingTx("0x5")
+	delete(original, "maxFeePerGas")
+	delete(original, "maxPriorityFeePerGas")
+	original["gasPrice"] = "0x3b9aca00"
+	original["type"] = "0x0"
+
+	var rawTxs [][]byte
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_getTransactionByHash":
+			return original, nil
+		case "eth_chainId":
+			return "0x38", nil
+		case "eth_gasPrice":
+			return "0x3b9aca00", nil
+		case "eth_sendRawTransaction":
+			var rawHex string
+			json.Unmarshal(params[0], &rawHex)
+			raw, _ := HexDecode(rawHex)
+			rawTxs = append(rawTxs, raw)
+			return fmt.Sprintf("0x%x", Keccak256(raw)), nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	client := NewClient(rpc.URL)
+	ctx := context.Background()
+	keyPair, err := ImportPrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+
+	// The node's gas price is below the minimum, so the minimum is used
+	r, err := client.ReplaceLegacyTransaction(ctx, keyPair, testTxHash, false)
+	if err != nil {
+		t.Fatalf("Failed to speed up: %v", err)
+	}
+	if r.MinPriorityFee != nil || r.MinMaxFeePerGas.Int64() != 1_100_000_000 {
+		t.Fatalf("Unexpected minimum fees %v/%s", r.MinPriorityFee, r.MinMaxFeePerGas)
+	}
+	tx, err := DecodeTransaction(rawTxs[0])
+	if err != nil {
+		t.Fatalf("Failed to decode replacement: %v", err)
+	}
+	if tx.Type != TxTypeLegacy || tx.ChainID.Int64() != 56 || tx.Nonce != 5 || tx.GasPrice.Int64() != 1_100_000_000 || tx.From != keyPair.Address {
+		t.Fatalf("Unexpected speed-up transaction: %+v", tx)
+	}
+
+	// An explicit gas price must clear the minimum
+	_, err = client.ReplaceLegacyTransaction(ctx, keyPair, testTxHash, true, WithGasPrice(big.NewInt(1_000_000_000)))
+	if !errors.Is(err, ErrReplacementUnderpriced) {
+		t.Fatalf("Expected ErrReplacementUnderpriced, got %v", err)
+	}
+	if _, err := client.ReplaceLegacyTransaction(ctx, keyPair, testTxHash, true, WithGasPrice(big.NewInt(2_000_000_000))); err != nil {
+		t.Fatalf("Failed to cancel: %v", err)
+	}
+	tx, err = DecodeTransaction(rawTxs[1])
+	if err != nil {
+		t.Fatalf("Failed to decode cancel: %v", err)
+	}
+	if tx.GasPrice.Int64() != 2_000_000_000 || tx.GasLimit != 21000 || *tx.To != keyPair.Address || tx.Value.Sign() != 0 {
+		t.Fatalf("Unexpected cancel transaction: %+v", tx)
+	}
+}
+
+// TestWaitForReplacement tests that the mined transaction among competing ones is reported
+func TestWaitForReplacement(t *testing.T) {
+	replacementHash := "0x" + fmt.Sprintf("%064x", 0xbeef)
+	var polls atomic.Int32
+
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		var hash string
+		if len(params) > 0 {
+			json.Unmarshal(params[0], &hash)
+		}
+		switch method {
+		case "eth_getTransactionCount":
+			if polls.Load() < 3 {
+				return "0x5", nil
+			}
+			return "0x6", nil
+		case "eth_getTransactionReceipt":
+			// The replacement is mined on the third poll
+			if hash == replacementHash && polls.Add(1) >= 3 {
+				return mockReceipt("0x1", "0x10"), nil
+			}
+			return nil, nil
+		case "eth_blockNumber":
+			return "0x10", nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+
+	client := NewClient(rpc.URL)
+	from := HexToAddress(testAddress)
+	winner, result, err := client.WaitForReplacement(context.Background(), from, 5, []string{testTxHash, replacementHash}, fastWaitOptions())
+	if err != nil {
+		t.Fatalf("Failed to wait for replacement: %v", err)
+	}
+	if winner != replacementHash || result.Status != TxStatusSuccess {
+		t.Fatalf("Winner %s with status %s, expected the replacement to succeed", winner, result.Status)
+	}
+
+	// A nonce used by an unknown transaction is reported as replaced
+	rpc = newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		switch method {
+		case "eth_getTransactionCount":
+			return "0x6", nil
+		case "eth_getTransactionReceipt":
+			return nil, nil
+		}
+		return nil, &mockRPCError{Code: -32601, Message: "method not found"}
+	})
+	winner, result, err = NewClient(rpc.URL).WaitForReplacement(context.Background(), from, 5, []string{testTxHash}, fastWaitOptions())
+	if err != nil || winner != "" || result.Status != TxStatusReplaced {
+		t.Fatalf("Expected replaced by an unknown transaction, got %q, %s, %v", winner, result.Status, err)
+	}
+}
@@ -0,0 +1,326 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// endpointCooldown is how long a failing endpoint is skipped in favour of healthy ones
+const endpointCooldown = 30 * time.Second
+
+// RetryPolicy controls how failed RPC requests are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first; 1 disables retries
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles on every attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. A Retry-After longer than this ends the retries.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   250 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// backoff returns the delay before retry number attempt (1-based), using
+// exponential backoff with equal jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// WithRetry sets the retry policy
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		c.retry = policy
+	}
+}
+
+// WithFallbackURLs adds endpoints that are used when the primary URL fails
+func WithFallbackURLs(urls ...string) ClientOption {
+	return func(c *Client) {
+		for _, url := range urls {
+			if url = strings.TrimSpace(url); url != "" {
+				c.endpoints.add(url)
+			}
+		}
+	}
+}
+
+// HTTPError is returned when the node answers with a rate limit or server error status
+type HTTPError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+// Error implements the error interface
+func (e *HTTPError) Error() string {
+	body := e.Body
+	if len(body) > 200 {
+		body = body[:200] + "..."
+	}
+	return fmt.Sprintf("HTTP %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), strings.TrimSpace(body))
+}
+
+// transportError wraps network-level failures (connection refused, timeouts, resets)
+type transportError struct {
+	err error
+}
+
+// Error implements the error interface
+func (e *transportError) Error() string {
+	return fmt.Sprintf("request failed: %v", e.err)
+}
+
+// Unwrap returns the underlying network error
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// isRetryable reports whether a request that failed with err may succeed if retried
+func isRetryable(err error) bool {
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		// -32005 is the de facto "limit exceeded" code (Infura, Alchemy)
+		return rpcErr.Code == -32005 || rpcErr.Code == 429
+	}
+
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// EndpointStatus reports the health of one RPC endpoint
+type EndpointStatus struct {
+	URL       string
+	Healthy   bool
+	Failures  int
+	LastError error
+	DownUntil time.Time
+}
+
+// endpoint tracks the health of one RPC URL
+type endpoint struct {
+	url       string
+	index     int
+	failures  int
+	lastErr   error
+	downUntil time.Time
+}
+
+// endpointPool orders endpoints by health for failover
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+}
+
+// add registers an endpoint, ignoring duplicates
+func (p *endpointPool) add(url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, ep := range p.endpoints {
+		if ep.url == url {
+			return
+		}
+	}
+	p.endpoints = append(p.endpoints, &endpoint{url: url, index: len(p.endpoints)})
+}
+
+// pick returns the best endpoint: healthy before cooling down, fewer recent
+// failures first, then configuration order
+func (p *endpointPool) pick() *endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	candidates := append([]*endpoint(nil), p.endpoints...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		aDown, bDown := now.Before(a.downUntil), now.Before(b.downUntil)
+		if aDown != bDown {
+			return !aDown
+		}
+		if aDown && !a.downUntil.Equal(b.downUntil) {
+			return a.downUntil.Before(b.downUntil)
+		}
+		if a.failures != b.failures {
+			return a.failures < b.failures
+		}
+		return a.index < b.index
+	})
+	return candidates[0]
+}
+
+// success records a response from the endpoint
+func (p *endpointPool) success(ep *endpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ep.failures = 0
+	ep.lastErr = nil
+	ep.downUntil = time.Time{}
+}
+
+// failure records a failed request and puts the endpoint into cooldown
+func (p *endpointPool) failure(ep *endpoint, err error, retryAfter time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ep.failures++
+	ep.lastErr = err
+	cooldown := endpointCooldown
+	if retryAfter > cooldown {
+		cooldown = retryAfter
+	}
+	ep.downUntil = time.Now().Add(cooldown)
+}
+
+// status returns a snapshot of every endpoint's health
+func (p *endpointPool) status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	for i, ep := range p.endpoints {
+		statuses[i] = EndpointStatus{
+			URL:       ep.url,
+			Healthy:   !now.Before(ep.downUntil),
+			Failures:  ep.failures,
+			LastError: ep.lastErr,
+			DownUntil: ep.downUntil,
+		}
+	}
+	return statuses
+}
+
+// EndpointStatus returns the health of the primary and fallback endpoints
+func (c *Client) EndpointStatus() []EndpointStatus {
+	return c.endpoints.status()
+}
+
+// withRetry runs attempt against the healthiest endpoint, retrying retryable
+// failures with backoff. Switching to a different endpoint happens without delay.
+func (c *Client) withRetry(ctx context.Context, attempt func(url string, n int) error) error {
+	var lastErr error
+	var previous *endpoint
+
+	for n := 0; n < c.retry.MaxAttempts; n++ {
+		ep := c.endpoints.pick()
+
+		// Back off before hitting the same endpoint again
+		if previous == ep {
+			delay := c.retry.backoff(n)
+			var httpErr *HTTPError
+			if errors.As(lastErr, &httpErr) && httpErr.RetryAfter > 0 {
+				if httpErr.RetryAfter > c.retry.MaxDelay {
+					return lastErr
+				}
+				delay = httpErr.RetryAfter
+			}
+
+			select {
+			case <-ctx.Done():
+				return lastErr
+			case <-time.After(delay):
+			}
+		}
+
+		err := attempt(ep.url, n)
+		if err == nil || !isRetryable(err) {
+			c.endpoints.success(ep)
+			return err
+		}
+
+		var retryAfter time.Duration
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			retryAfter = httpErr.RetryAfter
+		}
+		c.endpoints.failure(ep, err, retryAfter)
+
+		lastErr = err
+		previous = ep
+		if ctx.Err() != nil {
+			return lastErr
+		}
+	}
+
+	return lastErr
+}
+
+// resolveRawTxRetry interprets an error from a re-sent eth_sendRawTransaction.
+// Re-sending identical signed bytes is safe, but an earlier attempt may already
+// have reached the node, so "already known" means our transaction is in the pool
+// and "nonce too low" may mean it was already mined. Neither is hidden as a plain failure.
+func resolveRawTxRetry(params []interface{}, rpcErr *RPCError) (json.RawMessage, error) {
+	rawHex, ok := params[0].(string)
+	if !ok {
+		return nil, rpcErr
+	}
+	raw, err := HexDecode(rawHex)
+	if err != nil {
+		return nil, rpcErr
+	}
+	txHash := fmt.Sprintf("0x%x", Keccak256(raw))
+
+	msg := strings.ToLower(rpcErr.Message)
+	switch {
+	case strings.Contains(msg, "already known"),
+		strings.Contains(msg, "known transaction"),
+		strings.Contains(msg, "already exists"),
+		strings.Contains(msg, "already imported"):
+		return json.RawMessage(strconv.Quote(txHash)), nil
+	case strings.Contains(msg, "nonce too low"):
+		return nil, fmt.Errorf("%w (transaction %s may have been accepted by an earlier attempt; check its receipt)", rpcErr, txHash)
+	}
+
+	return nil, rpcErr
+}
@@ -0,0 +1,193 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetryPolicy returns a retry policy suitable for unit tests
+func fastRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+}
+
+// flakyServer answers with status for the first failures requests, then with result
+func flakyServer(t *testing.T, failures int32, status int, retryAfter string, result interface{}) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		if count.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(w, "upstream unavailable", status)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &count
+}
+
+// TestRetryTransientErrors tests that 5xx and 429 responses are retried until success
+func TestRetryTransientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		server, count := flakyServer(t, 2, status, "", "0x10")
+
+		client := NewClient(server.URL, WithRetry(fastRetryPolicy()))
+		blockNumber, err := client.GetBlockNumber(context.Background())
+		if err != nil {
+			t.Fatalf("HTTP %d: expected success after retries, got %v", status, err)
+		}
+		if blockNumber != 16 {
+			t.Fatalf("HTTP %d: block number %d, expected 16", status, blockNumber)
+		}
+		if n := count.Load(); n != 3 {
+			t.Fatalf("HTTP %d: expected 3 requests, got %d", status, n)
+		}
+	}
+}
+
+// TestRetryGivesUp tests that retries stop after MaxAttempts and on long Retry-After
+func TestRetryGivesUp(t *testing.T) {
+	server, count := flakyServer(t, 100, http.StatusBadGateway, "", "0x10")
+
+	_, err := NewClient(server.URL, WithRetry(fastRetryPolicy())).GetBlockNumber(context.Background())
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected HTTP 502 error, got %v", err)
+	}
+	if n := count.Load(); n != 4 {
+		t.Fatalf("Expected 4 attempts, got %d", n)
+	}
+
+	// A Retry-After longer than MaxDelay is respected by not hammering the node
+	server, count = flakyServer(t, 100, http.StatusTooManyRequests, "120", "0x10")
+	_, err = NewClient(server.URL, WithRetry(fastRetryPolicy())).GetBlockNumber(context.Background())
+	if !errors.As(err, &httpErr) || httpErr.RetryAfter != 120*time.Second {
+		t.Fatalf("Expected HTTP 429 with Retry-After, got %v", err)
+	}
+	if n := count.Load(); n != 1 {
+		t.Fatalf("Expected 1 attempt, got %d", n)
+	}
+
+	// Errors reported by the node itself are not retried
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		return nil, &mockRPCError{Code: 3, Message: "execution reverted"}
+	})
+	_, err = NewClient(rpc.URL, WithRetry(fastRetryPolicy())).GetBlockNumber(context.Background())
+	if err == nil || rpc.callCount("eth_blockNumber") != 1 {
+		t.Fatalf("Expected a single failed attempt, got %d attempts (%v)", rpc.callCount("eth_blockNumber"), err)
+	}
+}
+
+// TestFailoverToFallbackURL tests that a failing primary endpoint is skipped in favour of a fallback
+func TestFailoverToFallbackURL(t *testing.T) {
+	primary, primaryCount := flakyServer(t, 100, http.StatusInternalServerError, "", "0x1")
+	fallback, fallbackCount := flakyServer(t, 0, http.StatusOK, "", "0x2")
+
+	client := NewClient(primary.URL, WithFallbackURLs(fallback.URL), WithRetry(fastRetryPolicy()))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		blockNumber, err := client.GetBlockNumber(ctx)
+		if err != nil {
+			t.Fatalf("Expected failover to succeed, got %v", err)
+		}
+		if blockNumber != 2 {
+			t.Fatalf("Block number %d, expected the fallback's 2", blockNumber)
+		}
+	}
+
+	// The unhealthy primary is only tried once, then skipped while cooling down
+	if n := primaryCount.Load(); n != 1 {
+		t.Fatalf("Expected 1 request to the primary, got %d", n)
+	}
+	if n := fallbackCount.Load(); n != 3 {
+		t.Fatalf("Expected 3 requests to the fallback, got %d", n)
+	}
+
+	status := client.EndpointStatus()
+	if len(status) != 2 || status[0].Healthy || !status[1].Healthy {
+		t.Fatalf("Unexpected endpoint health: %+v", status)
+	}
+}
+
+// TestSendRawTransactionRetry tests that a re-sent raw transaction reports "already known" as the
+// transaction hash and never hides a possibly-accepted transaction behind "nonce too low"
+func TestSendRawTransactionRetry(t *testing.T) {
+	raw := []byte{0x02, 0xc0}
+	rawHex := "0x" + hex.EncodeToString(raw)
+	wantHash := fmt.Sprintf("0x%x", Keccak256(raw))
+
+	newServer := func(retryMessage string) (*httptest.Server, *atomic.Int32) {
+		var count atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req rpcRequest
+			json.NewDecoder(r.Body).Decode(&req)
+
+			// The first attempt reaches the node but the response is lost
+			if count.Add(1) == 1 {
+				http.Error(w, "gateway timeout", http.StatusGatewayTimeout)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0", "id": req.ID,
+				"error": map[string]interface{}{"code": -32000, "message": retryMessage},
+			})
+		}))
+		t.Cleanup(server.Close)
+		return server, &count
+	}
+
+	server, _ := newServer("already known")
+	result, err := NewClient(server.URL, WithRetry(fastRetryPolicy())).Call(context.Background(), "eth_sendRawTransaction", []interface{}{rawHex})
+	if err != nil {
+		t.Fatalf("Expected already-known retry to succeed, got %v", err)
+	}
+	if got := strings.Trim(string(result), "\""); got != wantHash {
+		t.Fatalf("Hash %s, expected %s", got, wantHash)
+	}
+
+	server, _ = newServer("nonce too low")
+	_, err = NewClient(server.URL, WithRetry(fastRetryPolicy())).Call(context.Background(), "eth_sendRawTransaction", []interface{}{rawHex})
+	if err == nil || !strings.Contains(err.Error(), wantHash) {
+		t.Fatalf("Expected nonce-too-low error mentioning %s, got %v", wantHash, err)
+	}
+
+	// On the first attempt "already known" is reported as-is
+	rpc := newMockRPC(t, func(method string, params []json.RawMessage) (interface{}, *mockRPCError) {
+		return nil, &mockRPCError{Code: -32000, Message: "already known"}
+	})
+	_, err = NewClient(rpc.URL).Call(context.Background(), "eth_sendRawTransaction", []interface{}{rawHex})
+	if err == nil {
+		t.Fatal("Expected already-known error on the first attempt")
+	}
+}
+
+// TestParseRetryAfter tests parsing both Retry-After formats
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("3"); d != 3*time.Second {
+		t.Fatalf("Retry-After 3 parsed as %s", d)
+	}
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d := parseRetryAfter(future); d < 50*time.Second || d > time.Minute {
+		t.Fatalf("Retry-After %s parsed as %s", future, d)
+	}
+	if d := parseRetryAfter("soon"); d != 0 {
+		t.Fatalf("Invalid Retry-After parsed as %s", d)
+	}
+}
@@ -0,0 +1,75 @@
+package ethereum
+
+import "math/big"
+
+// sendOptions holds the optional fields of a transaction sent by the Client
+type sendOptions struct {
+	accessList   AccessList
+	data         []byte
+	maxFeePerGas *big.Int
+	gasPrice     *big.Int
+	feeCap       *big.Int
+	feeSpeed     FeeSpeed
+	nonceSource  NonceSource
+	sentNonce    *uint64
+}
+
+// SendOption configures an optional transaction field for Preflight and the send methods
+type SendOption func(*sendOptions)
+
+// WithAccessList attaches an EIP-2930 access list. SendTransaction then sends a
+// type-1 transaction instead of a legacy one; SendEIP1559Transaction includes the
+// list in its type-2 transaction.
+func WithAccessList(accessList AccessList) SendOption {
+	return func(o *sendOptions) {
+		o.accessList = accessList
+	}
+}
+
+// WithData sets the transaction calldata, or the init code when deploying a contract
+func WithData(data []byte) SendOption {
+	return func(o *sendOptions) {
+		o.data = data
+	}
+}
+
+// WithMaxFeePerGas sets the max fee per gas of an EIP-1559 transaction instead of
+// deriving it from the fee oracle (see SuggestFees)
+func WithMaxFeePerGas(maxFeePerGas *big.Int) SendOption {
+	return func(o *sendOptions) {
+		o.maxFeePerGas = maxFeePerGas
+	}
+}
+
+// WithFeeSpeed selects the SuggestFees preset used for fees that are not given
+// (default FeeStandard)
+func WithFeeSpeed(speed FeeSpeed) SendOption {
+	return func(o *sendOptions) {
+		o.feeSpeed = speed
+	}
+}
+
+// WithGasPrice sets the gas price of a legacy or EIP-2930 transaction instead of
+// asking the node with eth_gasPrice
+func WithGasPrice(gasPrice *big.Int) SendOption {
+	return func(o *sendOptions) {
+		o.gasPrice = gasPrice
+	}
+}
+
+// WithFeeCap refuses to sign, with ErrFeeCapExceeded, when the max fee per gas (or
+// the gas price of a legacy transaction) is above feeCap
+func WithFeeCap(feeCap *big.Int) SendOption {
+	return func(o *sendOptions) {
+		o.feeCap = feeCap
+	}
+}
+
+// applySendOptions collects the options into a sendOptions
+func applySendOptions(opts []SendOption) *sendOptions {
+	o := &sendOptions{feeSpeed: FeeStandard}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
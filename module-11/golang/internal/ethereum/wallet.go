@@ -1,21 +1,17 @@
 package ethereum
 
 import (
-	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"math/big"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/ethereum/go-ethereum/common"
@@ -25,6 +21,9 @@ import (
 	"github.com/tyler-smith/go-bip32"
 	"github.com/tyler-smith/go-bip39"
 	"golang.org/x/crypto/sha3"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/metana-bootcamp/ethwallet/internal/units"
 )
 
 // RPC request structure
@@ -40,8 +39,9 @@ type rpcResponse struct {
 	JsonRPC string          `json:"jsonrpc"`
 	Result  json.RawMessage `json:"result"`
 	Error   *struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
 	} `json:"error"`
 	ID int `json:"id"`
 }
@@ -56,7 +56,7 @@ type TX1559 struct {
 	To                   *[20]byte  // S  (pointer lets RLP encode nil for contract creation)
 	Value                *big.Int   // S
 	Data                 []byte     // S
-	AccessList           []struct{} // always empty
+	AccessList           AccessList // S
 	// after signing:
 	V uint8
 	R *big.Int
@@ -69,12 +69,14 @@ type KeyPair struct {
 	Address    common.Address
 }
 
-// HDWalletInfo holds information about an HD wallet
+// HDWalletInfo holds information about an HD wallet. The BIP-39 passphrase
+// itself is never kept; HasPassphrase records whether one went into the seed.
 type HDWalletInfo struct {
-	Mnemonic     string
-	Seed         string
-	HDPath       string
-	AccountIndex uint32
+	Mnemonic      string
+	Seed          string
+	HDPath        string
+	AccountIndex  uint32
+	HasPassphrase bool
 }
 
 // HDKeyPair extends KeyPair with HD wallet information
@@ -154,6 +156,22 @@ func GetRPCURL() string {
 	return rpcURL
 }
 
+// GetFallbackRPCURLs gets the comma-separated fallback RPC URLs from RPC_FALLBACK_URLS.
+// Network profiles only use them for DefaultNetwork (see SelectNetwork).
+func GetFallbackRPCURLs() []string {
+	LoadEnvVariables()
+
+	var urls []string
+	for _, url := range splitURLList(os.Getenv("RPC_FALLBACK_URLS")) {
+		// Expand ${ALCHEMY_API_KEY} the same way as SEPOLIA_RPC_URL
+		if strings.Contains(url, "${ALCHEMY_API_KEY}") {
+			url = strings.Replace(url, "${ALCHEMY_API_KEY}", GetAPIKey(), -1)
+		}
+		urls = append(urls, url)
+	}
+	return urls
+}
+
 // GetBlockExplorerURL gets the block explorer URL from env vars or falls back to default
 func GetBlockExplorerURL() string {
 	// Ensure we've tried to load from .env
@@ -224,59 +242,18 @@ func SaveToFile(keyPair *KeyPair, filename string) error {
 	return os.WriteFile(filename, []byte(content), 0600) // 0600 = only owner can read/write
 }
 
-// CallRPC sends a JSON-RPC request to the given URL
+// CallRPC sends a JSON-RPC request to the given URL.
+//
+// Deprecated: create a Client with NewClient and use Client.Call, which reuses
+// connections and supports timeouts, headers and middleware.
 func CallRPC(ctx context.Context, url, method string, params []interface{}) (json.RawMessage, error) {
-	// Create request body
-	reqBody, err := json.Marshal(rpcRequest{
-		JsonRPC: "2.0",
-		Method:  method,
-		Params:  params,
-		ID:      1,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %v", err)
-	}
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send request with timeout
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
-	}
-
-	// Parse response
-	var rpcResp rpcResponse
-	if err := json.Unmarshal(body, &rpcResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %v", err)
-	}
-
-	// Check for RPC error
-	if rpcResp.Error != nil {
-		return nil, fmt.Errorf("RPC error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
-	}
-
-	return rpcResp.Result, nil
+	return NewClient(url).Call(ctx, method, params)
 }
 
-// WeiToEth converts wei (as a bigint) to ETH (as a string)
+// WeiToEth converts wei (as a bigint) to ETH (as a string). The result is exact
+// and shows at least 9 decimal places.
 func WeiToEth(wei *big.Int) string {
-	eth := new(big.Float).SetInt(wei)
-	eth.Quo(eth, new(big.Float).SetFloat64(1e18))
-	return fmt.Sprintf("%.9f", eth)
+	return units.FormatPadded(wei, units.EtherDecimals, 9)
 }
 
 // HexToBig converts a hex string to a big.Int
@@ -321,21 +298,35 @@ func Keccak256(data []byte) []byte {
 	return h.Sum(nil)
 }
 
-// GetBalance gets the balance of an address
+// GetBalance gets the balance of an address.
+//
+// Deprecated: use Client.GetBalance.
 func GetBalance(ctx context.Context, address common.Address, rpcURL string) (*big.Int, error) {
-	result, err := CallRPC(ctx, rpcURL, "eth_getBalance", []interface{}{address.Hex(), "latest"})
+	return NewClient(rpcURL).GetBalance(ctx, address)
+}
+
+// GetBalance gets the balance of an address
+func (c *Client) GetBalance(ctx context.Context, address common.Address) (*big.Int, error) {
+	result, err := c.Call(ctx, "eth_getBalance", []interface{}{address.Hex(), "latest"})
 	if err != nil {
-		return nil, fmt.Errorf("error checking balance: %v", err)
+		return nil, fmt.Errorf("error checking balance: %w", err)
 	}
 
 	return HexToBig(string(result))
 }
 
-// GetNonce gets the nonce (transaction count) of an address
+// GetNonce gets the nonce (transaction count) of an address.
+//
+// Deprecated: use Client.GetNonce.
 func GetNonce(ctx context.Context, address common.Address, rpcURL string) (uint64, error) {
-	result, err := CallRPC(ctx, rpcURL, "eth_getTransactionCount", []interface{}{address.Hex(), "pending"})
+	return NewClient(rpcURL).GetNonce(ctx, address)
+}
+
+// GetNonce gets the nonce (transaction count) of an address
+func (c *Client) GetNonce(ctx context.Context, address common.Address) (uint64, error) {
+	result, err := c.Call(ctx, "eth_getTransactionCount", []interface{}{address.Hex(), "pending"})
 	if err != nil {
-		return 0, fmt.Errorf("error getting nonce: %v", err)
+		return 0, fmt.Errorf("error getting nonce: %w", err)
 	}
 
 	nonceStr := string(result)
@@ -343,29 +334,48 @@ func GetNonce(ctx context.Context, address common.Address, rpcURL string) (uint6
 	return strconv.ParseUint(nonceStr[2:], 16, 64)
 }
 
-// GetChainID gets the chain ID from the network
+// GetChainID gets the chain ID from the network.
+//
+// Deprecated: use Client.GetChainID.
 func GetChainID(ctx context.Context, rpcURL string) (*big.Int, error) {
-	result, err := CallRPC(ctx, rpcURL, "eth_chainId", []interface{}{})
+	return NewClient(rpcURL).GetChainID(ctx)
+}
+
+// GetChainID gets the chain ID from the network
+func (c *Client) GetChainID(ctx context.Context) (*big.Int, error) {
+	result, err := c.Call(ctx, "eth_chainId", []interface{}{})
 	if err != nil {
-		return nil, fmt.Errorf("error getting chainID: %v", err)
+		return nil, fmt.Errorf("error getting chainID: %w", err)
 	}
 
 	return HexToBig(string(result))
 }
 
-// EstimateGas estimates the gas required for a transaction
+// EstimateGas estimates the gas required for a transaction.
+//
+// Deprecated: use Client.EstimateGas.
 func EstimateGas(ctx context.Context, from, to string, value *big.Int, rpcURL string) (uint64, error) {
+	return NewClient(rpcURL).EstimateGas(ctx, from, to, value)
+}
+
+// EstimateGas estimates the gas required for a transaction
+func (c *Client) EstimateGas(ctx context.Context, from, to string, value *big.Int) (uint64, error) {
 	call := map[string]string{
 		"from":  from,
 		"to":    to,
 		"value": fmt.Sprintf("0x%x", value),
 	}
 
-	result, err := CallRPC(ctx, rpcURL, "eth_estimateGas", []interface{}{call})
+	result, err := c.Call(ctx, "eth_estimateGas", []interface{}{call})
 	if err != nil {
-		return 0, fmt.Errorf("error estimating gas: %v", err)
+		return 0, fmt.Errorf("error estimating gas: %w", err)
 	}
 
+	return parseGasEstimate(result)
+}
+
+// parseGasEstimate parses an eth_estimateGas result and adds a safety buffer
+func parseGasEstimate(result json.RawMessage) (uint64, error) {
 	gasLimitStr := string(result)
 	gasLimitStr = strings.Trim(gasLimitStr, "\"")
 	gasLimit, err := strconv.ParseUint(gasLimitStr[2:], 16, 64)
@@ -377,14 +387,31 @@ func EstimateGas(ctx context.Context, from, to string, value *big.Int, rpcURL st
 	return uint64(float64(gasLimit) * 1.2), nil // Add 20% buffer
 }
 
-// GetBaseFee gets the current base fee from the network
+// GetBaseFee gets the current base fee from the network.
+//
+// Deprecated: use Client.GetBaseFee.
 func GetBaseFee(ctx context.Context, rpcURL string) (*big.Int, error) {
+	return NewClient(rpcURL).GetBaseFee(ctx)
+}
+
+// ErrNoBaseFee is returned when the latest block has no base fee, as on networks
+// without EIP-1559
+var ErrNoBaseFee = errors.New("block has no base fee")
+
+// GetBaseFee gets the current base fee from the network. It fails with
+// ErrNoBaseFee on networks without EIP-1559.
+func (c *Client) GetBaseFee(ctx context.Context) (*big.Int, error) {
 	// Get latest block
-	blockData, err := CallRPC(ctx, rpcURL, "eth_getBlockByNumber", []interface{}{"latest", false})
+	blockData, err := c.Call(ctx, "eth_getBlockByNumber", []interface{}{"latest", false})
 	if err != nil {
 		return nil, err
 	}
 
+	return parseBaseFee(blockData)
+}
+
+// parseBaseFee extracts the base fee from an eth_getBlockByNumber result
+func parseBaseFee(blockData json.RawMessage) (*big.Int, error) {
 	// Parse the block data to get the base fee
 	var block struct {
 		BaseFeePerGas string `json:"baseFeePerGas"`
@@ -395,16 +422,22 @@ func GetBaseFee(ctx context.Context, rpcURL string) (*big.Int, error) {
 	}
 
 	if block.BaseFeePerGas == "" {
-		// Fallback for networks that don't support EIP-1559
-		return big.NewInt(30_000_000_000), nil // 30 gwei default
+		return nil, ErrNoBaseFee
 	}
 
 	return HexToBig(block.BaseFeePerGas)
 }
 
-// GetGasPrice gets the current gas price from the network (for legacy transactions)
+// GetGasPrice gets the current gas price from the network (for legacy transactions).
+//
+// Deprecated: use Client.GetGasPrice.
 func GetGasPrice(ctx context.Context, rpcURL string) (*big.Int, error) {
-	result, err := CallRPC(ctx, rpcURL, "eth_gasPrice", []interface{}{})
+	return NewClient(rpcURL).GetGasPrice(ctx)
+}
+
+// GetGasPrice gets the current gas price from the network (for legacy transactions)
+func (c *Client) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	result, err := c.Call(ctx, "eth_gasPrice", []interface{}{})
 	if err != nil {
 		return nil, fmt.Errorf("error getting gas price: %w", err)
 	}
@@ -423,11 +456,11 @@ func (t *TX1559) PayloadRLP() ([]byte, error) {
 		To                   *[20]byte
 		Value                *big.Int
 		Data                 []byte
-		AccessList           []struct{}
+		AccessList           AccessList
 	}
 	return rlp.EncodeToBytes(unsigned{
 		t.ChainID, t.Nonce, t.MaxPriorityFeePerGas, t.MaxFeePerGas,
-		t.GasLimit, t.To, t.Value, t.Data, nil,
+		t.GasLimit, t.To, t.Value, t.Data, t.AccessList,
 	})
 }
 
@@ -463,74 +496,41 @@ func (t *TX1559) Sign(priv *ecdsa.PrivateKey) ([]byte, error) {
 	return append([]byte{0x02}, raw...), nil
 }
 
-// SendTransaction sends a transaction with the specified parameters
+// SendTransaction sends a legacy (type-0) EIP-155 transaction.
+//
+// Deprecated: use Client.SendTransaction.
 func SendTransaction(ctx context.Context, fromKeyPair *KeyPair, toAddress string, valueWei *big.Int, rpcURL string) (string, error) {
-	// Get chain ID
-	chainID, err := GetChainID(ctx, rpcURL)
-	if err != nil {
-		return "", err
-	}
-
-	// Get nonce
-	nonce, err := GetNonce(ctx, fromKeyPair.Address, rpcURL)
-	if err != nil {
-		return "", err
-	}
+	return NewClient(rpcURL).SendTransaction(ctx, fromKeyPair, toAddress, valueWei)
+}
 
-	// Estimate gas
-	gasLimit, err := EstimateGas(ctx, fromKeyPair.Address.Hex(), toAddress, valueWei, rpcURL)
+// SendTransaction sends a legacy (type-0) EIP-155 transaction priced with eth_gasPrice
+// (or WithGasPrice), or an EIP-2930 (type-1) transaction when an access list is given.
+// An empty toAddress deploys the WithData init code as a contract.
+func (c *Client) SendTransaction(ctx context.Context, fromKeyPair *KeyPair, toAddress string, valueWei *big.Int, opts ...SendOption) (string, error) {
+	tx, err := c.BuildTransaction(ctx, fromKeyPair.Address, toAddress, valueWei, opts...)
 	if err != nil {
 		return "", err
 	}
+	return c.signAndSend(ctx, fromKeyPair, tx, applySendOptions(opts))
+}
 
-	// Get base fee
-	baseFee, err := GetBaseFee(ctx, rpcURL)
-	if err != nil {
-		baseFee = big.NewInt(30_000_000_000) // 30 gwei default
+// decodeRecipient decodes a transaction recipient; an empty address means contract creation
+func decodeRecipient(toAddress string) (*[20]byte, error) {
+	if toAddress == "" {
+		return nil, nil
 	}
 
-	// Priority tip - 1.5 gwei on top of base fee
-	tip := big.NewInt(1_500_000_000) // 1.5 gwei
-
-	// Calculate max fee: baseFee * 2 + tip
-	maxFee := new(big.Int).Mul(baseFee, big.NewInt(2))
-	maxFee = new(big.Int).Add(maxFee, tip)
-
-	// Decode to address
-	var to [20]byte
 	toBytes, err := HexDecode(toAddress)
 	if err != nil {
-		return "", fmt.Errorf("error decoding to address: %w", err)
-	}
-	copy(to[:], toBytes)
-
-	// Create transaction
-	tx := &TX1559{
-		ChainID:              chainID,
-		Nonce:                nonce,
-		MaxPriorityFeePerGas: tip,
-		MaxFeePerGas:         maxFee,
-		GasLimit:             gasLimit,
-		To:                   &to,
-		Value:                valueWei,
-		Data:                 []byte{}, // Empty data for a simple transfer
-	}
-
-	// Sign transaction
-	rawTx, err := tx.Sign(fromKeyPair.PrivateKey)
-	if err != nil {
-		return "", fmt.Errorf("error signing transaction: %w", err)
+		return nil, fmt.Errorf("error decoding to address: %w", err)
 	}
-
-	// Send transaction
-	rawHex := "0x" + hex.EncodeToString(rawTx)
-	txHash, err := CallRPC(ctx, rpcURL, "eth_sendRawTransaction", []interface{}{rawHex})
-	if err != nil {
-		return "", fmt.Errorf("error sending transaction: %w", err)
+	if len(toBytes) != 20 {
+		return nil, fmt.Errorf("error decoding to address: expected 20 bytes, got %d", len(toBytes))
 	}
 
-	// Return transaction hash
-	return strings.Trim(string(txHash), "\""), nil
+	var to [20]byte
+	copy(to[:], toBytes)
+	return &to, nil
 }
 
 // GetAddressFromPrivateKeyHex gets an Ethereum address from a private key hex string
@@ -556,45 +556,111 @@ func FormatTransactionURL(txHash string, blockExplorer string) string {
 	return fmt.Sprintf("%s/tx/%s", blockExplorer, txHash)
 }
 
-// Generate a new HD wallet with mnemonic
-func GenerateHDWallet(hdPath string) (*HDKeyPair, error) {
-	// Use default path if not specified
-	if hdPath == "" {
-		hdPath = DefaultHDPath
+// hdOptions holds the optional settings of GenerateHDWallet and ImportHDWallet
+type hdOptions struct {
+	passphrase string
+	words      int
+}
+
+// HDOption configures an optional setting of an HD wallet
+type HDOption func(*hdOptions)
+
+// WithPassphrase sets the BIP-39 passphrase (the "25th word") mixed into the seed.
+// The same mnemonic with a different passphrase gives an unrelated wallet.
+func WithPassphrase(passphrase string) HDOption {
+	return func(o *hdOptions) {
+		o.passphrase = passphrase
 	}
+}
+
+// WithWordCount sets the number of words of a generated mnemonic: 12, 15, 18, 21 or 24.
+// ImportHDWallet ignores it.
+func WithWordCount(words int) HDOption {
+	return func(o *hdOptions) {
+		o.words = words
+	}
+}
 
-	// Generate entropy for mnemonic
-	entropy, err := bip39.NewEntropy(128) // 128 bits = 12 words
+// NewMnemonic generates a random BIP-39 mnemonic of 12, 15, 18, 21 or 24 words
+func NewMnemonic(words int) (string, error) {
+	switch words {
+	case 12, 15, 18, 21, 24:
+	default:
+		return "", fmt.Errorf("invalid word count %d: must be 12, 15, 18, 21 or 24", words)
+	}
+
+	// Every 3 words encode 32 bits of entropy plus a 1-bit checksum
+	entropy, err := bip39.NewEntropy(words / 3 * 32)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate entropy: %w", err)
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
 	}
 
-	// Generate mnemonic
 	mnemonic, err := bip39.NewMnemonic(entropy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate mnemonic: %w", err)
+		return "", fmt.Errorf("failed to generate mnemonic: %w", err)
+	}
+	return mnemonic, nil
+}
+
+// Generate a new HD wallet with mnemonic, 12 words unless WithWordCount is given
+func GenerateHDWallet(hdPath string, opts ...HDOption) (*HDKeyPair, error) {
+	options := hdOptions{words: 12}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	mnemonic, err := NewMnemonic(options.words)
+	if err != nil {
+		return nil, err
 	}
 
-	return ImportHDWallet(mnemonic, hdPath)
+	return ImportHDWallet(mnemonic, hdPath, opts...)
 }
 
-// Import HD wallet from mnemonic
-func ImportHDWallet(mnemonic string, hdPath string) (*HDKeyPair, error) {
-	// Use default path if not specified
-	if hdPath == "" {
-		hdPath = DefaultHDPath
+// Import HD wallet from mnemonic, with the BIP-39 passphrase given by WithPassphrase
+func ImportHDWallet(mnemonic string, hdPath string, opts ...HDOption) (*HDKeyPair, error) {
+	var options hdOptions
+	for _, opt := range opts {
+		opt(&options)
 	}
 
+	// BIP-39 seeds are derived from the NFKD forms, which go-bip39 leaves to the
+	// caller; without it a non-ASCII passphrase typed in NFC gives another wallet
+	// than hardware and browser wallets do
+	mnemonic = norm.NFKD.String(mnemonic)
+	passphrase := norm.NFKD.String(options.passphrase)
+
 	// Validate mnemonic
 	if !bip39.IsMnemonicValid(mnemonic) {
 		return nil, errors.New("invalid mnemonic phrase")
 	}
 
 	// Generate seed from mnemonic
-	seed := bip39.NewSeed(mnemonic, "")
+	seed := bip39.NewSeed(mnemonic, passphrase)
+
+	return deriveHDKeyPair(&HDWalletInfo{
+		Mnemonic:      mnemonic,
+		Seed:          hex.EncodeToString(seed),
+		HDPath:        hdPath,
+		HasPassphrase: options.passphrase != "",
+	})
+}
+
+// deriveHDKeyPair derives the key at info.HDPath from info.Seed and fills in the
+// account index
+func deriveHDKeyPair(info *HDWalletInfo) (*HDKeyPair, error) {
+	// Use default path if not specified
+	if info.HDPath == "" {
+		info.HDPath = DefaultHDPath
+	}
+
+	seed, err := hex.DecodeString(info.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed: %w", err)
+	}
 
 	// Parse HD path
-	pathSegments, err := parseHDPath(hdPath)
+	pathSegments, err := parseHDPath(info.HDPath)
 	if err != nil {
 		return nil, fmt.Errorf("invalid HD path: %w", err)
 	}
@@ -641,40 +707,28 @@ func ImportHDWallet(mnemonic string, hdPath string) (*HDKeyPair, error) {
 	}
 
 	// Extract account index from path
-	accountIndex := uint32(0)
+	info.AccountIndex = 0
 	if len(pathSegments) >= 5 {
-		accountIndex = pathSegments[4]
-	}
-
-	// Create HD wallet info
-	hdInfo := &HDWalletInfo{
-		Mnemonic:     mnemonic,
-		Seed:         hex.EncodeToString(seed),
-		HDPath:       hdPath,
-		AccountIndex: accountIndex,
+		info.AccountIndex = pathSegments[4]
 	}
 
 	return &HDKeyPair{
 		KeyPair: keyPair,
-		HDInfo:  hdInfo,
+		HDInfo:  info,
 	}, nil
 }
 
-// DeriveChildAccount derives a new account at the specified index
+// DeriveChildAccount derives a new account at the specified index. The account
+// comes from the parent's seed, so it uses the same BIP-39 passphrase.
 func DeriveChildAccount(hdKeyPair *HDKeyPair, index uint32) (*HDKeyPair, error) {
 	// Get base path without the last segment
 	basePath := getBaseHDPath(hdKeyPair.HDInfo.HDPath)
 
 	// Create new path with the specified index
-	newPath := fmt.Sprintf("%s/%d", basePath, index)
-
-	// Import HD wallet with the new path
-	childKeyPair, err := ImportHDWallet(hdKeyPair.HDInfo.Mnemonic, newPath)
-	if err != nil {
-		return nil, err
-	}
+	info := *hdKeyPair.HDInfo
+	info.HDPath = fmt.Sprintf("%s/%d", basePath, index)
 
-	return childKeyPair, nil
+	return deriveHDKeyPair(&info)
 }
 
 // Parse HD path into segments
@@ -734,74 +788,20 @@ func getBaseHDPath(path string) string {
 	return strings.Join(parts[:len(parts)-1], "/")
 }
 
-// SendEIP1559Transaction sends an EIP-1559 transaction with the specified parameters
+// SendEIP1559Transaction sends an EIP-1559 transaction with the specified parameters.
+//
+// Deprecated: use Client.SendEIP1559Transaction.
 func SendEIP1559Transaction(ctx context.Context, fromKeyPair *KeyPair, toAddress string, valueWei *big.Int, rpcURL string, priorityFeeWei *big.Int) (string, error) {
-	// Get chain ID
-	chainID, err := GetChainID(ctx, rpcURL)
-	if err != nil {
-		return "", err
-	}
-
-	// Get nonce
-	nonce, err := GetNonce(ctx, fromKeyPair.Address, rpcURL)
-	if err != nil {
-		return "", err
-	}
+	return NewClient(rpcURL).SendEIP1559Transaction(ctx, fromKeyPair, toAddress, valueWei, priorityFeeWei)
+}
 
-	// Estimate gas
-	gasLimit, err := EstimateGas(ctx, fromKeyPair.Address.Hex(), toAddress, valueWei, rpcURL)
+// SendEIP1559Transaction sends an EIP-1559 transaction with the specified parameters.
+// A nil priorityFeeWei, or a missing WithMaxFeePerGas, is filled in from the
+// WithFeeSpeed preset of SuggestFees. An empty toAddress deploys the WithData init code as a contract.
+func (c *Client) SendEIP1559Transaction(ctx context.Context, fromKeyPair *KeyPair, toAddress string, valueWei *big.Int, priorityFeeWei *big.Int, opts ...SendOption) (string, error) {
+	tx, err := c.BuildEIP1559Transaction(ctx, fromKeyPair.Address, toAddress, valueWei, priorityFeeWei, opts...)
 	if err != nil {
 		return "", err
 	}
-
-	// Get base fee
-	baseFee, err := GetBaseFee(ctx, rpcURL)
-	if err != nil {
-		baseFee = big.NewInt(30_000_000_000) // 30 gwei default
-	}
-
-	// If priority fee is not specified, use a default of 1.5 gwei
-	if priorityFeeWei == nil {
-		priorityFeeWei = big.NewInt(1_500_000_000) // 1.5 gwei
-	}
-
-	// Calculate max fee: baseFee * 2 + priorityFee
-	maxFeePerGas := new(big.Int).Mul(baseFee, big.NewInt(2))
-	maxFeePerGas = new(big.Int).Add(maxFeePerGas, priorityFeeWei)
-
-	// Decode to address
-	var to [20]byte
-	toBytes, err := HexDecode(toAddress)
-	if err != nil {
-		return "", fmt.Errorf("error decoding to address: %w", err)
-	}
-	copy(to[:], toBytes)
-
-	// Create transaction
-	tx := &TX1559{
-		ChainID:              chainID,
-		Nonce:                nonce,
-		MaxPriorityFeePerGas: priorityFeeWei,
-		MaxFeePerGas:         maxFeePerGas,
-		GasLimit:             gasLimit,
-		To:                   &to,
-		Value:                valueWei,
-		Data:                 []byte{}, // Empty data for a simple transfer
-	}
-
-	// Sign transaction
-	rawTx, err := tx.Sign(fromKeyPair.PrivateKey)
-	if err != nil {
-		return "", fmt.Errorf("error signing transaction: %w", err)
-	}
-
-	// Send transaction
-	rawHex := "0x" + hex.EncodeToString(rawTx)
-	txHash, err := CallRPC(ctx, rpcURL, "eth_sendRawTransaction", []interface{}{rawHex})
-	if err != nil {
-		return "", fmt.Errorf("error sending transaction: %w", err)
-	}
-
-	// Return transaction hash
-	return strings.Trim(string(txHash), "\""), nil
+	return c.signAndSend(ctx, fromKeyPair, tx, applySendOptions(opts))
 }
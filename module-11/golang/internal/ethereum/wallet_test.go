@@ -339,6 +339,14 @@ func TestWeiToEth(t *testing.T) {
 	if !strings.Contains(smallEthStr, "0.000000001") {
 		t.Fatalf("WeiToEth gave %s for small amount, expected approximately 0.000000001", smallEthStr)
 	}
+	// Amounts are exact: nothing is rounded away from large balances or single wei
+	largeWei, _ := new(big.Int).SetString("123456789123456789123456789", 10)
+	if got := WeiToEth(largeWei); got != "123456789.123456789123456789" {
+		t.Fatalf("WeiToEth gave %s for a large amount", got)
+	}
+	if got := WeiToEth(big.NewInt(1)); got != "0.000000000000000001" {
+		t.Fatalf("WeiToEth gave %s for 1 wei", got)
+	}
 }
 
 // TestEnvVariables tests loading environment variables
@@ -435,6 +443,79 @@ func TestHDWalletDerivation(t *testing.T) {
 	}
 }
 
+// TestHDWalletPassphrase tests the BIP-39 passphrase against the reference test vector
+func TestHDWalletPassphrase(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	hdKeyPair, err := ImportHDWallet(mnemonic, DefaultHDPath, WithPassphrase("TREZOR"))
+	if err != nil {
+		t.Fatalf("Failed to import HD wallet: %v", err)
+	}
+	wantSeed := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+	if hdKeyPair.HDInfo.Seed != wantSeed || !hdKeyPair.HDInfo.HasPassphrase {
+		t.Fatalf("Unexpected HD wallet info: %+v", hdKeyPair.HDInfo)
+	}
+
+	plain, err := ImportHDWallet(mnemonic, DefaultHDPath)
+	if err != nil {
+		t.Fatalf("Failed to import HD wallet: %v", err)
+	}
+	if plain.HDInfo.HasPassphrase || plain.KeyPair.Address == hdKeyPair.KeyPair.Address {
+		t.Fatalf("Expected a different wallet without the passphrase")
+	}
+
+	// Non-ASCII passphrases are NFKD-normalized, so the composed (NFC) and
+	// decomposed (NFD) spellings give the same seed
+	wantSeed = "6883a9239bc67a6467fa9216ff6425a2b85cd7badd5dd18ec8bde2d63b5cebcf9109defac0a9c33c53749b9804b6e3a4d211d3ef8d2aee8e4608da0a83ea0f82"
+	for _, passphrase := range []string{"P\u00e4ssw\u00f6rd \u00fc", "Pa\u0308sswo\u0308rd u\u0308"} {
+		unicodeKeyPair, err := ImportHDWallet(mnemonic, DefaultHDPath, WithPassphrase(passphrase))
+		if err != nil {
+			t.Fatalf("Failed to import HD wallet: %v", err)
+		}
+		if unicodeKeyPair.HDInfo.Seed != wantSeed {
+			t.Fatalf("Unexpected seed for passphrase %q: %s", passphrase, unicodeKeyPair.HDInfo.Seed)
+		}
+	}
+
+	// Child accounts keep the passphrase
+	child, err := DeriveChildAccount(hdKeyPair, 1)
+	if err != nil {
+		t.Fatalf("Failed to derive account 1: %v", err)
+	}
+	want, err := ImportHDWallet(mnemonic, "m/44'/60'/0'/0/1", WithPassphrase("TREZOR"))
+	if err != nil {
+		t.Fatalf("Failed to import account 1: %v", err)
+	}
+	if child.KeyPair.Address != want.KeyPair.Address || !child.HDInfo.HasPassphrase || child.HDInfo.AccountIndex != 1 {
+		t.Fatalf("Unexpected child account %s: %+v", child.KeyPair.Address.Hex(), child.HDInfo)
+	}
+	if hdKeyPair.HDInfo.HDPath != DefaultHDPath {
+		t.Fatalf("Deriving a child changed the parent path to %s", hdKeyPair.HDInfo.HDPath)
+	}
+}
+
+// TestHDWalletWordCount tests generating mnemonics of every BIP-39 length
+func TestHDWalletWordCount(t *testing.T) {
+	for _, words := range []int{12, 15, 18, 21, 24} {
+		hdKeyPair, err := GenerateHDWallet("", WithWordCount(words))
+		if err != nil {
+			t.Fatalf("Failed to generate a %d-word HD wallet: %v", words, err)
+		}
+		if got := len(strings.Fields(hdKeyPair.HDInfo.Mnemonic)); got != words {
+			t.Fatalf("Expected %d words, got %d", words, got)
+		}
+		if hdKeyPair.HDInfo.HasPassphrase || hdKeyPair.HDInfo.HDPath != DefaultHDPath {
+			t.Fatalf("Unexpected HD wallet info: %+v", hdKeyPair.HDInfo)
+		}
+	}
+
+	for _, words := range []int{0, 11, 13, 25} {
+		if _, err := GenerateHDWallet("", WithWordCount(words)); err == nil {
+			t.Fatalf("Expected an error for %d words", words)
+		}
+	}
+}
+
 // TestEIP1559Transaction tests preparing and signing EIP-1559 transactions
 func TestEIP1559Transaction(t *testing.T) {
 	// Import a known private key
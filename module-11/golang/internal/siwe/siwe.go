@@ -0,0 +1,323 @@
+// Package siwe builds, parses and validates Sign-In with Ethereum (EIP-4361)
+// messages. The messages are signed and verified as EIP-191 personal messages.
+package siwe
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/metana-bootcamp/ethwallet/internal/address"
+)
+
+var (
+	// ErrExpired is returned when the expiration time of a message has passed
+	ErrExpired = errors.New("message has expired")
+
+	// ErrNotYetValid is returned before the not-before time of a message
+	ErrNotYetValid = errors.New("message is not yet valid")
+)
+
+const (
+	// Version is the only SIWE message version
+	Version = "1"
+
+	// header ends the first line of every message
+	header = " wants you to sign in with your Ethereum account:"
+
+	// nonceAlphabet are the characters of generated nonces
+	nonceAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+// Message is a Sign-In with Ethereum message. Times are kept as the RFC 3339
+// strings that appear in the message, since the signature covers the exact text.
+type Message struct {
+	Scheme         string // optional, e.g. "https"
+	Domain         string // RFC 3986 authority requesting the sign-in, e.g. "example.com:443"
+	Address        common.Address
+	Statement      string // optional single line shown to the user
+	URI            string // RFC 3986 URI the sign-in is for
+	Version        string
+	ChainID        uint64
+	Nonce          string // at least 8 alphanumeric characters
+	IssuedAt       string
+	ExpirationTime string // optional
+	NotBefore      string // optional
+	RequestID      string // optional
+	Resources      []string
+}
+
+// GenerateNonce returns a random 16-character alphanumeric nonce
+func GenerateNonce() (string, error) {
+	nonce := make([]byte, 16)
+	max := big.NewInt(int64(len(nonceAlphabet)))
+	for i := range nonce {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		nonce[i] = nonceAlphabet[n.Int64()]
+	}
+	return string(nonce), nil
+}
+
+// String returns the message text that is signed
+func (m *Message) String() string {
+	var b strings.Builder
+	if m.Scheme != "" {
+		b.WriteString(m.Scheme + "://")
+	}
+	b.WriteString(m.Domain + header + "\n")
+	b.WriteString(address.Checksum(m.Address, 0) + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString("URI: " + m.URI + "\n")
+	b.WriteString("Version: " + m.Version + "\n")
+	b.WriteString("Chain ID: " + strconv.FormatUint(m.ChainID, 10) + "\n")
+	b.WriteString("Nonce: " + m.Nonce + "\n")
+	b.WriteString("Issued At: " + m.IssuedAt)
+	if m.ExpirationTime != "" {
+		b.WriteString("\nExpiration Time: " + m.ExpirationTime)
+	}
+	if m.NotBefore != "" {
+		b.WriteString("\nNot Before: " + m.NotBefore)
+	}
+	if m.RequestID != "" {
+		b.WriteString("\nRequest ID: " + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			b.WriteString("\n- " + resource)
+		}
+	}
+	return b.String()
+}
+
+// Validate checks every field against the EIP-4361 grammar
+func (m *Message) Validate() error {
+	if m.Scheme != "" && !isScheme(m.Scheme) {
+		return fmt.Errorf("invalid scheme %q", m.Scheme)
+	}
+	if err := validateDomain(m.Domain); err != nil {
+		return err
+	}
+	if strings.ContainsAny(m.Statement, "\r\n") {
+		return fmt.Errorf("statement must be a single line")
+	}
+	if err := validateURI("URI", m.URI); err != nil {
+		return err
+	}
+	if m.Version != Version {
+		return fmt.Errorf("unsupported version %q, expected %s", m.Version, Version)
+	}
+	if m.ChainID == 0 {
+		return fmt.Errorf("chain ID must not be 0")
+	}
+	if len(m.Nonce) < 8 || strings.Trim(m.Nonce, nonceAlphabet) != "" {
+		return fmt.Errorf("nonce %q must be at least 8 alphanumeric characters", m.Nonce)
+	}
+
+	issuedAt, err := parseTime("issued-at", m.IssuedAt)
+	if err != nil {
+		return err
+	}
+	if m.ExpirationTime != "" {
+		expires, err := parseTime("expiration time", m.ExpirationTime)
+		if err != nil {
+			return err
+		}
+		if !expires.After(issuedAt) {
+			return fmt.Errorf("expiration time %s is not after issued-at %s", m.ExpirationTime, m.IssuedAt)
+		}
+	}
+	if m.NotBefore != "" {
+		if _, err := parseTime("not-before time", m.NotBefore); err != nil {
+			return err
+		}
+	}
+
+	if strings.ContainsAny(m.RequestID, " \r\n") {
+		return fmt.Errorf("request ID must not contain spaces or line breaks")
+	}
+	for _, resource := range m.Resources {
+		if err := validateURI("resource", resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckTime checks that now falls inside the validity window of the message
+func (m *Message) CheckTime(now time.Time) error {
+	if m.ExpirationTime != "" {
+		expires, err := parseTime("expiration time", m.ExpirationTime)
+		if err != nil {
+			return err
+		}
+		if !now.Before(expires) {
+			return fmt.Errorf("%w at %s", ErrExpired, m.ExpirationTime)
+		}
+	}
+	if m.NotBefore != "" {
+		notBefore, err := parseTime("not-before time", m.NotBefore)
+		if err != nil {
+			return err
+		}
+		if now.Before(notBefore) {
+			return fmt.Errorf("%w until %s", ErrNotYetValid, m.NotBefore)
+		}
+	}
+	return nil
+}
+
+// Parse parses and validates the text of a message. The text must be exactly the
+// message that String produces, since a signature covers every byte of it.
+func Parse(text string) (*Message, error) {
+	lines := strings.Split(text, "\n")
+	m := &Message{}
+	next := 0
+	line := func() (string, bool) {
+		if next >= len(lines) {
+			return "", false
+		}
+		next++
+		return lines[next-1], true
+	}
+
+	first, _ := line()
+	if !strings.HasSuffix(first, header) {
+		return nil, fmt.Errorf("first line must end with %q", strings.TrimSpace(header))
+	}
+	m.Domain = strings.TrimSuffix(first, header)
+	if scheme, domain, ok := strings.Cut(m.Domain, "://"); ok {
+		m.Scheme, m.Domain = scheme, domain
+	}
+
+	addressLine, _ := line()
+	addr, err := address.Parse(addressLine, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address line: %w", err)
+	}
+	if !address.HasChecksum(addressLine) {
+		return nil, fmt.Errorf("address %s must be EIP-55 checksummed", addressLine)
+	}
+	m.Address = addr
+
+	if blank, _ := line(); blank != "" {
+		return nil, fmt.Errorf("expected an empty line after the address")
+	}
+	if statement, _ := line(); statement != "" {
+		m.Statement = statement
+		if blank, _ := line(); blank != "" {
+			return nil, fmt.Errorf("expected an empty line after the statement")
+		}
+	}
+
+	// Required fields, in order
+	var chainID string
+	for _, field := range []struct {
+		tag   string
+		value *string
+	}{
+		{"URI: ", &m.URI},
+		{"Version: ", &m.Version},
+		{"Chain ID: ", &chainID},
+		{"Nonce: ", &m.Nonce},
+		{"Issued At: ", &m.IssuedAt},
+	} {
+		l, ok := line()
+		if !ok || !strings.HasPrefix(l, field.tag) {
+			return nil, fmt.Errorf("expected %q", strings.TrimSpace(field.tag))
+		}
+		*field.value = strings.TrimPrefix(l, field.tag)
+	}
+	m.ChainID, err = strconv.ParseUint(chainID, 10, 64)
+	if err != nil || strconv.FormatUint(m.ChainID, 10) != chainID {
+		return nil, fmt.Errorf("invalid chain ID %q", chainID)
+	}
+
+	// Optional fields, in order
+	for _, field := range []struct {
+		tag   string
+		value *string
+	}{
+		{"Expiration Time: ", &m.ExpirationTime},
+		{"Not Before: ", &m.NotBefore},
+		{"Request ID: ", &m.RequestID},
+	} {
+		if next < len(lines) && strings.HasPrefix(lines[next], field.tag) {
+			l, _ := line()
+			*field.value = strings.TrimPrefix(l, field.tag)
+		}
+	}
+	if next < len(lines) && lines[next] == "Resources:" {
+		line()
+		for next < len(lines) && strings.HasPrefix(lines[next], "- ") {
+			l, _ := line()
+			m.Resources = append(m.Resources, strings.TrimPrefix(l, "- "))
+		}
+	}
+	if next < len(lines) {
+		return nil, fmt.Errorf("unexpected line %d: %q", next+1, lines[next])
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	if m.String() != text {
+		return nil, fmt.Errorf("message is not in canonical form")
+	}
+	return m, nil
+}
+
+// parseTime parses an RFC 3339 timestamp field
+func parseTime(name, s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: must be an RFC 3339 timestamp", name, s)
+	}
+	return t, nil
+}
+
+// validateDomain checks that domain is an RFC 3986 authority: a host with an
+// optional user info and port, and nothing else
+func validateDomain(domain string) error {
+	u, err := url.Parse("//" + domain)
+	if err != nil || domain == "" || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || strings.ContainsAny(domain, " \t") {
+		return fmt.Errorf("invalid domain %q: expected a host such as example.com or example.com:8443", domain)
+	}
+	return nil
+}
+
+// validateURI checks that s is an absolute RFC 3986 URI
+func validateURI(name, s string) error {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || strings.ContainsAny(s, " \t\r\n") {
+		return fmt.Errorf("invalid %s %q: expected an absolute URI", name, s)
+	}
+	return nil
+}
+
+// isScheme reports whether s is an RFC 3986 URI scheme
+func isScheme(s string) bool {
+	for i, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,152 @@
+package siwe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// specMessage is the example message from EIP-4361
+const specMessage = `example.com wants you to sign in with your Ethereum account:
+0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2
+
+I accept the ExampleOrg Terms of Service: https://example.com/tos
+
+URI: https://example.com/login
+Version: 1
+Chain ID: 1
+Nonce: 32891756
+Issued At: 2021-09-30T16:25:24Z
+Resources:
+- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/
+- https://example.com/my-web2-claim.json`
+
+// TestParseSpecMessage tests parsing the EIP-4361 example and formatting it back
+func TestParseSpecMessage(t *testing.T) {
+	m, err := Parse(specMessage)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if m.Domain != "example.com" || m.Scheme != "" || m.Address != common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2") {
+		t.Fatalf("Unexpected domain or address: %+v", m)
+	}
+	if m.Statement != "I accept the ExampleOrg Terms of Service: https://example.com/tos" || m.URI != "https://example.com/login" {
+		t.Fatalf("Unexpected statement or URI: %+v", m)
+	}
+	if m.Version != "1" || m.ChainID != 1 || m.Nonce != "32891756" || m.IssuedAt != "2021-09-30T16:25:24Z" {
+		t.Fatalf("Unexpected fields: %+v", m)
+	}
+	if len(m.Resources) != 2 || m.Resources[1] != "https://example.com/my-web2-claim.json" {
+		t.Fatalf("Unexpected resources: %v", m.Resources)
+	}
+	if m.String() != specMessage {
+		t.Fatalf("Formatted message differs:\n%s", m.String())
+	}
+}
+
+// TestMessageFormat tests building messages with and without the optional fields
+func TestMessageFormat(t *testing.T) {
+	m := &Message{
+		Scheme:   "https",
+		Domain:   "dashboard.example.com:8443",
+		Address:  common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"),
+		URI:      "https://dashboard.example.com:8443/login",
+		Version:  Version,
+		ChainID:  11155111,
+		Nonce:    "abcDEF123456",
+		IssuedAt: "2024-01-01T00:00:00Z",
+	}
+	want := `https://dashboard.example.com:8443 wants you to sign in with your Ethereum account:
+0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2
+
+
+URI: https://dashboard.example.com:8443/login
+Version: 1
+Chain ID: 11155111
+Nonce: abcDEF123456
+Issued At: 2024-01-01T00:00:00Z`
+	if got := m.String(); got != want {
+		t.Fatalf("Unexpected message without a statement:\n%s", got)
+	}
+	if parsed, err := Parse(want); err != nil || parsed.Scheme != "https" || parsed.Statement != "" {
+		t.Fatalf("Failed to parse the message without a statement: %+v, %v", parsed, err)
+	}
+
+	m.Statement = "Sign in to the dashboard"
+	m.ExpirationTime = "2024-01-01T00:10:00Z"
+	m.NotBefore = "2024-01-01T00:00:00Z"
+	m.RequestID = "req-42"
+	m.Resources = []string{"https://dashboard.example.com/api"}
+	parsed, err := Parse(m.String())
+	if err != nil {
+		t.Fatalf("Failed to parse the full message: %v", err)
+	}
+	if parsed.String() != m.String() || parsed.RequestID != "req-42" || parsed.NotBefore != m.NotBefore {
+		t.Fatalf("Round-trip mismatch: %+v", parsed)
+	}
+
+	nonce, err := GenerateNonce()
+	if err != nil || len(nonce) != 16 || strings.Trim(nonce, nonceAlphabet) != "" {
+		t.Fatalf("Unexpected nonce %q, %v", nonce, err)
+	}
+}
+
+// TestParseInvalid tests that malformed messages are rejected
+func TestParseInvalid(t *testing.T) {
+	invalid := []struct {
+		name     string
+		old, new string
+	}{
+		{"missing header", "wants you to sign in", "wants you to log in"},
+		{"unchecksummed address", "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"},
+		{"bad checksum", "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756CC2"},
+		{"domain with a path", "example.com wants", "example.com/login wants"},
+		{"missing URI", "URI: https://example.com/login\n", ""},
+		{"relative URI", "URI: https://example.com/login", "URI: /login"},
+		{"wrong version", "Version: 1", "Version: 2"},
+		{"fields out of order", "Version: 1\nChain ID: 1", "Chain ID: 1\nVersion: 1"},
+		{"short nonce", "Nonce: 32891756", "Nonce: 1234567"},
+		{"non-alphanumeric nonce", "Nonce: 32891756", "Nonce: 3289-1756"},
+		{"bad issued-at", "2021-09-30T16:25:24Z", "2021-09-30 16:25:24"},
+		{"leading zero chain ID", "Chain ID: 1", "Chain ID: 01"},
+		{"trailing line", "my-web2-claim.json", "my-web2-claim.json\nextra"},
+		{"trailing newline", "my-web2-claim.json", "my-web2-claim.json\n"},
+		{"expiration before issued-at", "Issued At: 2021-09-30T16:25:24Z", "Issued At: 2021-09-30T16:25:24Z\nExpiration Time: 2021-09-30T16:00:00Z"},
+	}
+	for _, tc := range invalid {
+		text := strings.Replace(specMessage, tc.old, tc.new, 1)
+		if text == specMessage {
+			t.Fatalf("%s: replacement not found", tc.name)
+		}
+		if _, err := Parse(text); err == nil {
+			t.Fatalf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+// TestCheckTime tests the expiration and not-before window
+func TestCheckTime(t *testing.T) {
+	m, err := Parse(strings.Replace(specMessage, "Issued At: 2021-09-30T16:25:24Z",
+		"Issued At: 2021-09-30T16:25:24Z\nExpiration Time: 2021-09-30T17:00:00Z\nNot Before: 2021-09-30T16:30:00Z", 1))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	at := func(s string) time.Time {
+		parsed, _ := time.Parse(time.RFC3339, s)
+		return parsed
+	}
+	if err := m.CheckTime(at("2021-09-30T16:45:00Z")); err != nil {
+		t.Fatalf("Expected the message to be valid: %v", err)
+	}
+	if err := m.CheckTime(at("2021-09-30T16:26:00Z")); !errors.Is(err, ErrNotYetValid) {
+		t.Fatalf("Expected ErrNotYetValid, got %v", err)
+	}
+	if err := m.CheckTime(at("2021-09-30T17:00:00Z")); !errors.Is(err, ErrExpired) {
+		t.Fatalf("Expected ErrExpired, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+// Package units converts between integer base units (such as wei) and exact
+// decimal strings (such as ether amounts) without floating point.
+package units
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimals of the common Ethereum denominations
+const (
+	WeiDecimals   uint8 = 0
+	GweiDecimals  uint8 = 9
+	EtherDecimals uint8 = 18
+)
+
+// unitDecimals maps denomination names to their decimals
+var unitDecimals = map[string]uint8{
+	"wei":        0,
+	"kwei":       3,
+	"babbage":    3,
+	"mwei":       6,
+	"lovelace":   6,
+	"gwei":       9,
+	"shannon":    9,
+	"szabo":      12,
+	"microether": 12,
+	"finney":     15,
+	"milliether": 15,
+	"ether":      18,
+	"eth":        18,
+}
+
+// UnitDecimals returns the decimals of a denomination such as "gwei" or "ether"
+func UnitDecimals(unit string) (uint8, error) {
+	decimals, ok := unitDecimals[strings.ToLower(strings.TrimSpace(unit))]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q (use wei, gwei or ether)", unit)
+	}
+	return decimals, nil
+}
+
+// Parse converts a non-negative decimal string such as "1.5" into base units with
+// the given decimals. Inputs with more fractional digits than decimals are
+// rejected rather than rounded.
+func Parse(s string, decimals uint8) (*big.Int, error) {
+	whole, fraction, hasPoint := strings.Cut(strings.TrimSpace(s), ".")
+	if (whole == "" && fraction == "") || (hasPoint && fraction == "") {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	for _, part := range []string{whole, fraction} {
+		if strings.Trim(part, "0123456789") != "" {
+			return nil, fmt.Errorf("invalid amount %q", s)
+		}
+	}
+
+	// Trailing zeros never lose precision
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("amount %q has more than %d decimal places", s, decimals)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", int(decimals)-len(fraction))
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
+
+// ParseAmount parses an amount with an optional unit suffix, such as "0.05ether",
+// "12 gwei" or "1000". Amounts without a suffix are in defaultUnit.
+func ParseAmount(s string, defaultUnit string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	number := strings.TrimRight(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	unit := strings.TrimSpace(s[len(number):])
+	if unit == "" {
+		unit = defaultUnit
+	}
+
+	decimals, err := UnitDecimals(unit)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(strings.TrimSpace(number), decimals)
+}
+
+// Format converts base units into an exact decimal string with the given
+// decimals, without trailing zeros: 1500000 with 6 decimals is "1.5"
+func Format(amount *big.Int, decimals uint8) string {
+	return FormatPadded(amount, decimals, 0)
+}
+
+// FormatPadded is like Format but keeps at least minFraction fractional digits,
+// e.g. "1.000000000" for one ether with 9. Digits are never rounded away.
+func FormatPadded(amount *big.Int, decimals uint8, minFraction int) string {
+	digits := new(big.Int).Abs(amount).String()
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	whole := digits[:len(digits)-int(decimals)]
+	fraction := strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	if len(fraction) < minFraction {
+		fraction += strings.Repeat("0", minFraction-len(fraction))
+	}
+
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+// ParseEther parses an ether amount such as "0.05" into wei
+func ParseEther(s string) (*big.Int, error) {
+	return Parse(s, EtherDecimals)
+}
+
+// ParseGwei parses a gwei amount such as "1.5" into wei
+func ParseGwei(s string) (*big.Int, error) {
+	return Parse(s, GweiDecimals)
+}
+
+// FormatEther formats wei as an exact ether amount
+func FormatEther(wei *big.Int) string {
+	return Format(wei, EtherDecimals)
+}
+
+// FormatGwei formats wei as an exact gwei amount
+func FormatGwei(wei *big.Int) string {
+	return Format(wei, GweiDecimals)
+}
@@ -0,0 +1,98 @@
+package units
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestFormat tests exact formatting with decimals
+func TestFormat(t *testing.T) {
+	vectors := []struct {
+		amount   string
+		decimals uint8
+		want     string
+	}{
+		{"1500000", 6, "1.5"},
+		{"1000000000000000000", 18, "1"},
+		{"1", 18, "0.000000000000000001"},
+		{"0", 6, "0"},
+		{"123456789", 0, "123456789"},
+		{"100010", 2, "1000.1"},
+		{"-2500000000", 9, "-2.5"},
+		// Larger than float64 can represent exactly
+		{"123456789012345678901234567890123456789", 18, "123456789012345678901.234567890123456789"},
+	}
+	for _, v := range vectors {
+		amount, _ := new(big.Int).SetString(v.amount, 10)
+		if got := Format(amount, v.decimals); got != v.want {
+			t.Fatalf("Format(%s, %d) = %s, expected %s", v.amount, v.decimals, got, v.want)
+		}
+
+		// Formatting and parsing round-trip
+		if amount.Sign() >= 0 {
+			parsed, err := Parse(v.want, v.decimals)
+			if err != nil || parsed.Cmp(amount) != 0 {
+				t.Fatalf("Parse(%s, %d) = %v, %v", v.want, v.decimals, parsed, err)
+			}
+		}
+	}
+
+	oneEther, _ := ParseEther("1")
+	if got := FormatPadded(oneEther, EtherDecimals, 9); got != "1.000000000" {
+		t.Fatalf("FormatPadded(1 ether) = %s", got)
+	}
+	if got := FormatPadded(big.NewInt(1), EtherDecimals, 9); got != "0.000000000000000001" {
+		t.Fatalf("FormatPadded(1 wei) = %s", got)
+	}
+	if got := FormatGwei(big.NewInt(1_500_000_000)); got != "1.5" {
+		t.Fatalf("FormatGwei = %s", got)
+	}
+}
+
+// TestParse tests exact parsing and the rejection of lossy or malformed input
+func TestParse(t *testing.T) {
+	if parsed, err := Parse(".25", 2); err != nil || parsed.Int64() != 25 {
+		t.Fatalf("Parse(.25) = %v, %v", parsed, err)
+	}
+	if parsed, err := Parse("1.2300", 2); err != nil || parsed.Int64() != 123 {
+		t.Fatalf("Parse(1.2300) = %v, %v", parsed, err)
+	}
+	for _, bad := range []string{"", ".", "1.", "1.2345", "-1", "1e6", "0x10", "1.2.3", "1,5"} {
+		if _, err := Parse(bad, 3); err == nil {
+			t.Fatalf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+// TestParseAmount tests amounts with unit suffixes
+func TestParseAmount(t *testing.T) {
+	vectors := []struct {
+		input       string
+		defaultUnit string
+		want        string
+	}{
+		{"0.05ether", "wei", "50000000000000000"},
+		{"12gwei", "wei", "12000000000"},
+		{"12 GWEI", "wei", "12000000000"},
+		{"1.5", "gwei", "1500000000"},
+		{"1.5", "ether", "1500000000000000000"},
+		{"1000", "wei", "1000"},
+		{"3finney", "wei", "3000000000000000"},
+		{"0.000000000000000001eth", "wei", "1"},
+	}
+	for _, v := range vectors {
+		got, err := ParseAmount(v.input, v.defaultUnit)
+		if err != nil {
+			t.Fatalf("ParseAmount(%q, %s) failed: %v", v.input, v.defaultUnit, err)
+		}
+		if got.String() != v.want {
+			t.Fatalf("ParseAmount(%q, %s) = %s, expected %s", v.input, v.defaultUnit, got, v.want)
+		}
+	}
+
+	for _, bad := range []string{"1.5wei", "0.1gwe", "ether", "1.5", "1e18"} {
+		if _, err := ParseAmount(bad, "wei"); err == nil {
+			t.Fatalf("ParseAmount(%q) should fail", bad)
+		}
+	}
+}
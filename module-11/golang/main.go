@@ -19,10 +19,22 @@ Perform various operations like key generation, balance checking, and sending tr
 		Version: "1.0.0",
 	}
 
+	// Global flags
+	cmd.AddNetworkFlags(rootCmd)
+
 	// Add subcommands
 	rootCmd.AddCommand(cmd.NewKeygenCmd())
 	rootCmd.AddCommand(cmd.NewSendCmd())
 	rootCmd.AddCommand(cmd.NewBalanceCmd())
+	rootCmd.AddCommand(cmd.NewDeployCmd())
+	rootCmd.AddCommand(cmd.NewCallCmd())
+	rootCmd.AddCommand(cmd.NewTokenCmd())
+	rootCmd.AddCommand(cmd.NewNFTCmd())
+	rootCmd.AddCommand(cmd.NewTxCmd())
+	rootCmd.AddCommand(cmd.NewSignCmd())
+	rootCmd.AddCommand(cmd.NewVerifyCmd())
+	rootCmd.AddCommand(cmd.NewSIWECmd())
+	rootCmd.AddCommand(cmd.NewNetworksCmd())
 
 	// Execute
 	if err := rootCmd.Execute(); err != nil {